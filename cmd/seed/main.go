@@ -97,7 +97,9 @@ func seedUsers(users []SeedUser) error {
 
 	// Create user repository and service
 	userRepo := persistence.NewUserRepository()
-	userService := service.NewUserService(userRepo)
+	attributeService := service.NewAttributeService(persistence.NewAttributeDefinitionRepository())
+	// Seed accounts such as admin are created regardless of identifier rules
+	userService := service.NewUserService(userRepo, attributeService, nil, nil, nil, entity.UsernamePolicy{AllowUnicode: config.GetConfig().Username.AllowUnicode}, nil, entity.PasswordPolicy{})
 
 	log.Printf("Seeding %d users...", len(users))
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"log"
 	"os"
@@ -10,14 +11,34 @@ import (
 	"time"
 
 	"github.com/gigi434/sample-grpc-server/internal/config"
+	groupusecase "github.com/gigi434/sample-grpc-server/internal/modules/group/application/usecase"
+	groupservice "github.com/gigi434/sample-grpc-server/internal/modules/group/domain/service"
+	groupgrpc "github.com/gigi434/sample-grpc-server/internal/modules/group/infrastructure/grpc"
+	grouppersistence "github.com/gigi434/sample-grpc-server/internal/modules/group/infrastructure/persistence"
 	healthgrpc "github.com/gigi434/sample-grpc-server/internal/modules/health/infrastructure/grpc"
+	organizationusecase "github.com/gigi434/sample-grpc-server/internal/modules/organization/application/usecase"
+	organizationservice "github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/service"
+	organizationgrpc "github.com/gigi434/sample-grpc-server/internal/modules/organization/infrastructure/grpc"
+	organizationpersistence "github.com/gigi434/sample-grpc-server/internal/modules/organization/infrastructure/persistence"
+	preferenceusecase "github.com/gigi434/sample-grpc-server/internal/modules/preference/application/usecase"
+	preferenceentity "github.com/gigi434/sample-grpc-server/internal/modules/preference/domain/entity"
+	preferenceservice "github.com/gigi434/sample-grpc-server/internal/modules/preference/domain/service"
+	preferencegrpc "github.com/gigi434/sample-grpc-server/internal/modules/preference/infrastructure/grpc"
+	preferencepersistence "github.com/gigi434/sample-grpc-server/internal/modules/preference/infrastructure/persistence"
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/usecase"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/service"
 	usergrpc "github.com/gigi434/sample-grpc-server/internal/modules/user/infrastructure/grpc"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/infrastructure/notification"
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/infrastructure/persistence"
 	"github.com/gigi434/sample-grpc-server/internal/server"
+	"github.com/gigi434/sample-grpc-server/internal/shared/auth"
 	"github.com/gigi434/sample-grpc-server/internal/shared/database"
+	"github.com/gigi434/sample-grpc-server/internal/shared/pagination"
+	grouppb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/group"
 	healthpb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/health"
+	organizationpb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/organization"
+	preferencepb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/preference"
 	userpb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
 	"google.golang.org/grpc"
 )
@@ -32,7 +53,7 @@ func main() {
 	log.Printf("Starting gRPC server version %s", version)
 
 	// Load configuration
-	_ = config.GetConfig()
+	cfg := config.GetConfig()
 
 	// Get server port from environment or use default
 	port := 50051
@@ -55,15 +76,51 @@ func main() {
 
 	// Initialize repositories
 	userRepo := persistence.NewUserRepository()
+	attributeRepo := persistence.NewAttributeDefinitionRepository()
+	invitationRepo := persistence.NewInvitationRepository()
+	identifierRuleRepo := persistence.NewIdentifierRuleRepository()
+	loginEventRepo := persistence.NewLoginEventRepository()
+	userChangeRepo := persistence.NewUserChangeRepository()
+	organizationRepo := organizationpersistence.NewOrganizationRepository()
+	membershipRepo := organizationpersistence.NewMembershipRepository()
+	groupRepo := grouppersistence.NewGroupRepository()
+	groupMemberRepo := grouppersistence.NewGroupMemberRepository()
+	preferenceRepo := preferencepersistence.NewPreferenceRepository()
+	transactor := database.NewTransactor()
+	accountNotifier := notification.NewLogNotifier()
 
 	// Initialize domain services
-	userService := service.NewUserService(userRepo)
+	organizationService := organizationservice.NewOrganizationService(organizationRepo, membershipRepo, userRepo)
+	groupService := groupservice.NewGroupService(groupRepo, groupMemberRepo, userRepo, transactor)
+	attributeService := service.NewAttributeService(attributeRepo)
+	identifierRuleService := service.NewIdentifierRuleService(identifierRuleRepo)
+	loginHistoryService := service.NewLoginHistoryService(loginEventRepo)
+	userService := service.NewUserService(userRepo, attributeService, identifierRuleService, organizationService, groupService, entity.UsernamePolicy{AllowUnicode: cfg.Username.AllowUnicode}, loginHistoryService, entity.PasswordPolicy{MaxAge: cfg.Password.MaxAge})
+	accountExpiryService := service.NewAccountExpiryService(userRepo, accountNotifier)
+	userChangeRetentionService := service.NewUserChangeRetentionService(userChangeRepo, cfg.UserChanges.Retention)
+	secret := tokenSecret(cfg.Auth)
+	tokenIssuer := auth.NewTokenIssuer(secret, cfg.Auth.TokenTTL, cfg.Auth.RestrictedTokenTTL)
+	pageTokens := pagination.NewTokenCodec(secret)
+	invitationService := service.NewInvitationService(invitationRepo, userService, organizationService, transactor, cfg.Invitation.TTL)
+	preferenceService := preferenceservice.NewPreferenceService(preferenceRepo, userRepo, preferenceentity.DefaultRegistry)
 
 	// Initialize use cases
-	userUseCase := usecase.NewUserUseCase(userRepo, userService)
+	userUseCase := usecase.NewUserUseCase(userRepo, userService, loginHistoryService, tokenIssuer, pageTokens, userChangeRepo)
+	attributeUseCase := usecase.NewAttributeUseCase(attributeRepo, attributeService, userService)
+	invitationUseCase := usecase.NewInvitationUseCase(invitationRepo, invitationService)
+	identifierRuleUseCase := usecase.NewIdentifierRuleUseCase(identifierRuleRepo, identifierRuleService, userService)
+
+	// Import reserved usernames and email domain rules
+	importIdentifierRules(identifierRuleUseCase, cfg.IdentifierRules)
+	organizationUseCase := organizationusecase.NewOrganizationUseCase(organizationRepo, membershipRepo, organizationService, userService)
+	groupUseCase := groupusecase.NewGroupUseCase(groupRepo, groupMemberRepo, groupService, userService)
+	preferenceUseCase := preferenceusecase.NewPreferenceUseCase(preferenceService, userService)
 
 	// Create gRPC service implementations
-	userServiceServer := usergrpc.NewUserServiceServer(userUseCase)
+	userServiceServer := usergrpc.NewUserServiceServer(userUseCase, attributeUseCase, invitationUseCase, identifierRuleUseCase)
+	organizationServiceServer := organizationgrpc.NewOrganizationServiceServer(organizationUseCase)
+	groupServiceServer := groupgrpc.NewGroupServiceServer(groupUseCase)
+	preferenceServiceServer := preferencegrpc.NewPreferenceServiceServer(preferenceUseCase)
 	healthServiceServer := healthgrpc.NewHealthServiceServer(version)
 
 	// Create gRPC server with interceptors
@@ -72,9 +129,18 @@ func main() {
 		server.ChainUnaryInterceptors(
 			server.RecoveryInterceptor(),
 			server.LoggingInterceptor(),
+			server.ErrorInterceptor(usergrpc.ErrorTranslator(), groupgrpc.ErrorTranslator(), organizationgrpc.ErrorTranslator(), preferencegrpc.ErrorTranslator()),
 			server.ValidationInterceptor(),
-			// Uncomment to enable authentication
-			// server.AuthInterceptor(),
+			server.AuthInterceptor(tokenIssuer),
+			server.TenantInterceptor(cfg.Tenancy.Header, cfg.Tenancy.Required, organizationService),
+		),
+		server.ChainStreamInterceptors(
+			server.StreamRecoveryInterceptor(),
+			server.StreamLoggingInterceptor(),
+			server.StreamErrorInterceptor(usergrpc.ErrorTranslator(), groupgrpc.ErrorTranslator(), organizationgrpc.ErrorTranslator(), preferencegrpc.ErrorTranslator()),
+			server.StreamValidationInterceptor(),
+			server.StreamAuthInterceptor(tokenIssuer),
+			server.StreamTenantInterceptor(cfg.Tenancy.Header, cfg.Tenancy.Required, organizationService),
 		),
 	)
 	if err != nil {
@@ -83,14 +149,26 @@ func main() {
 
 	// Register services
 	userpb.RegisterUserServiceServer(grpcServer.GetServer(), userServiceServer)
+	organizationpb.RegisterOrganizationServiceServer(grpcServer.GetServer(), organizationServiceServer)
+	grouppb.RegisterGroupServiceServer(grpcServer.GetServer(), groupServiceServer)
+	preferencepb.RegisterPreferenceServiceServer(grpcServer.GetServer(), preferenceServiceServer)
 	healthpb.RegisterHealthServiceServer(grpcServer.GetServer(), healthServiceServer)
 
+	// Deactivate expired accounts and prune the user change log in the background until shutdown
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go accountExpiryService.Run(jobCtx, cfg.AccountExpiry.Interval)
+	go userChangeRetentionService.Run(jobCtx, cfg.UserChanges.PruneInterval)
+
 	// Start server in a goroutine
 	serverErrors := make(chan error, 1)
 	go func() {
 		log.Printf("gRPC server listening on port %d", port)
 		log.Printf("Health check available at: grpc://localhost:%d/health.v1.HealthService/Check", port)
 		log.Printf("User service available at: grpc://localhost:%d/user.v1.UserService/*", port)
+		log.Printf("Organization service available at: grpc://localhost:%d/organization.v1.OrganizationService/*", port)
+		log.Printf("Group service available at: grpc://localhost:%d/group.v1.GroupService/*", port)
+		log.Printf("Preference service available at: grpc://localhost:%d/preference.v1.PreferenceService/*", port)
 		serverErrors <- grpcServer.Start()
 	}()
 
@@ -104,6 +182,9 @@ func main() {
 	case sig := <-quit:
 		log.Printf("Received signal: %v", sig)
 
+		// Stop background jobs
+		stopJobs()
+
 		// Create a context with timeout for graceful shutdown
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
@@ -122,26 +203,100 @@ func main() {
 	}
 }
 
+// tokenSecret returns the configured token signing secret, or a random one when none
+// is configured so that tokens cannot be forged with a well-known default
+func tokenSecret(authConfig config.AuthConfig) []byte {
+	if authConfig.TokenSecret != "" {
+		return []byte(authConfig.TokenSecret)
+	}
+
+	log.Println("Warning: AUTH_TOKEN_SECRET is not set; using a random secret, so tokens will not survive a restart")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatalf("Failed to generate token secret: %v", err)
+	}
+	return secret
+}
+
+// importIdentifierRules imports the configured identifier rule files.
+// Missing files are logged and skipped so the server can start without them.
+func importIdentifierRules(identifierRuleUseCase *usecase.IdentifierRuleUseCase, rulesConfig config.IdentifierRulesConfig) {
+	files := []struct {
+		kind entity.IdentifierRuleKind
+		path string
+	}{
+		{entity.IdentifierRuleReservedUsername, rulesConfig.ReservedUsernamesFile},
+		{entity.IdentifierRuleUsernamePattern, rulesConfig.UsernamePatternsFile},
+		{entity.IdentifierRuleBlockedEmailDomain, rulesConfig.BlockedEmailDomainsFile},
+		{entity.IdentifierRuleAllowedEmailDomain, rulesConfig.AllowedEmailDomainsFile},
+	}
+
+	for _, file := range files {
+		if file.path == "" {
+			continue
+		}
+		created, err := identifierRuleUseCase.LoadConfiguredRuleFile(context.Background(), string(file.kind), file.path)
+		if err != nil {
+			log.Printf("Warning: failed to import %s rules: %v", file.kind, err)
+			continue
+		}
+		log.Printf("Imported %d %s rules from %s", created, file.kind, file.path)
+	}
+}
+
 // Helper function to setup dependencies (for testing)
-func setupDependencies() (*usecase.UserUseCase, *healthgrpc.HealthServiceServer, error) {
+func setupDependencies() (*usergrpc.UserServiceServer, *organizationgrpc.OrganizationServiceServer, *groupgrpc.GroupServiceServer, *preferencegrpc.PreferenceServiceServer, *healthgrpc.HealthServiceServer, error) {
 	// Initialize repositories
 	userRepo := persistence.NewUserRepository()
+	attributeRepo := persistence.NewAttributeDefinitionRepository()
+	invitationRepo := persistence.NewInvitationRepository()
+	identifierRuleRepo := persistence.NewIdentifierRuleRepository()
+	loginEventRepo := persistence.NewLoginEventRepository()
+	userChangeRepo := persistence.NewUserChangeRepository()
+	organizationRepo := organizationpersistence.NewOrganizationRepository()
+	membershipRepo := organizationpersistence.NewMembershipRepository()
+	groupRepo := grouppersistence.NewGroupRepository()
+	groupMemberRepo := grouppersistence.NewGroupMemberRepository()
+	preferenceRepo := preferencepersistence.NewPreferenceRepository()
+	transactor := database.NewTransactor()
 
 	// Initialize domain services
-	userService := service.NewUserService(userRepo)
+	organizationService := organizationservice.NewOrganizationService(organizationRepo, membershipRepo, userRepo)
+	groupService := groupservice.NewGroupService(groupRepo, groupMemberRepo, userRepo, transactor)
+	attributeService := service.NewAttributeService(attributeRepo)
+	identifierRuleService := service.NewIdentifierRuleService(identifierRuleRepo)
+	loginHistoryService := service.NewLoginHistoryService(loginEventRepo)
+	userService := service.NewUserService(userRepo, attributeService, identifierRuleService, organizationService, groupService, entity.UsernamePolicy{AllowUnicode: config.GetConfig().Username.AllowUnicode}, loginHistoryService, entity.PasswordPolicy{MaxAge: config.GetConfig().Password.MaxAge})
+	secret := tokenSecret(config.GetConfig().Auth)
+	tokenIssuer := auth.NewTokenIssuer(secret, config.GetConfig().Auth.TokenTTL, config.GetConfig().Auth.RestrictedTokenTTL)
+	pageTokens := pagination.NewTokenCodec(secret)
+	invitationService := service.NewInvitationService(invitationRepo, userService, organizationService, transactor, config.GetConfig().Invitation.TTL)
+	preferenceService := preferenceservice.NewPreferenceService(preferenceRepo, userRepo, preferenceentity.DefaultRegistry)
 
 	// Initialize use cases
-	userUseCase := usecase.NewUserUseCase(userRepo, userService)
+	userUseCase := usecase.NewUserUseCase(userRepo, userService, loginHistoryService, tokenIssuer, pageTokens, userChangeRepo)
+	attributeUseCase := usecase.NewAttributeUseCase(attributeRepo, attributeService, userService)
+	invitationUseCase := usecase.NewInvitationUseCase(invitationRepo, invitationService)
+	identifierRuleUseCase := usecase.NewIdentifierRuleUseCase(identifierRuleRepo, identifierRuleService, userService)
+	organizationUseCase := organizationusecase.NewOrganizationUseCase(organizationRepo, membershipRepo, organizationService, userService)
+	groupUseCase := groupusecase.NewGroupUseCase(groupRepo, groupMemberRepo, groupService, userService)
+	preferenceUseCase := preferenceusecase.NewPreferenceUseCase(preferenceService, userService)
 
-	// Create health service
+	// Create gRPC service implementations
+	userServiceServer := usergrpc.NewUserServiceServer(userUseCase, attributeUseCase, invitationUseCase, identifierRuleUseCase)
+	organizationServiceServer := organizationgrpc.NewOrganizationServiceServer(organizationUseCase)
+	groupServiceServer := groupgrpc.NewGroupServiceServer(groupUseCase)
+	preferenceServiceServer := preferencegrpc.NewPreferenceServiceServer(preferenceUseCase)
 	healthServiceServer := healthgrpc.NewHealthServiceServer(version)
 
-	return userUseCase, healthServiceServer, nil
+	return userServiceServer, organizationServiceServer, groupServiceServer, preferenceServiceServer, healthServiceServer, nil
 }
 
 // RegisterServices registers all gRPC services (for testing)
-func RegisterServices(grpcServer *grpc.Server, userUseCase *usecase.UserUseCase, healthService *healthgrpc.HealthServiceServer) {
-	userServiceServer := usergrpc.NewUserServiceServer(userUseCase)
+func RegisterServices(grpcServer *grpc.Server, userServiceServer *usergrpc.UserServiceServer, organizationServiceServer *organizationgrpc.OrganizationServiceServer, groupServiceServer *groupgrpc.GroupServiceServer, preferenceServiceServer *preferencegrpc.PreferenceServiceServer, healthService *healthgrpc.HealthServiceServer) {
 	userpb.RegisterUserServiceServer(grpcServer, userServiceServer)
+	organizationpb.RegisterOrganizationServiceServer(grpcServer, organizationServiceServer)
+	grouppb.RegisterGroupServiceServer(grpcServer, groupServiceServer)
+	preferencepb.RegisterPreferenceServiceServer(grpcServer, preferenceServiceServer)
 	healthpb.RegisterHealthServiceServer(grpcServer, healthService)
 }
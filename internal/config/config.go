@@ -13,7 +13,15 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Database DatabaseConfig
+	Database        DatabaseConfig
+	Tenancy         TenancyConfig
+	Invitation      InvitationConfig
+	AccountExpiry   AccountExpiryConfig
+	UserChanges     UserChangesConfig
+	Auth            AuthConfig
+	Password        PasswordConfig
+	Username        UsernameConfig
+	IdentifierRules IdentifierRulesConfig
 }
 
 // DatabaseConfig holds database-related configuration
@@ -30,6 +38,67 @@ type DatabaseConfig struct {
 	ConnMaxLifetime time.Duration
 }
 
+// TenancyConfig holds multi-tenancy configuration
+type TenancyConfig struct {
+	// Header is the metadata key carrying the organization ID
+	Header string
+	// Required rejects requests that do not resolve to a tenant
+	Required bool
+}
+
+// InvitationConfig holds user invitation configuration
+type InvitationConfig struct {
+	// TTL is how long an invitation can be accepted after it is created
+	TTL time.Duration
+}
+
+// AccountExpiryConfig holds configuration of the job deactivating expired users
+type AccountExpiryConfig struct {
+	// Interval is how often users past their valid_until are deactivated
+	Interval time.Duration
+}
+
+// UserChangesConfig holds configuration of the user change log read by WatchUsers
+type UserChangesConfig struct {
+	// Retention is how long changes are kept; resume tokens older than that
+	// expire. Zero keeps changes forever.
+	Retention time.Duration
+	// PruneInterval is how often changes past the retention are deleted
+	PruneInterval time.Duration
+}
+
+// AuthConfig holds authentication token configuration
+type AuthConfig struct {
+	// TokenSecret signs bearer tokens; when empty a random secret is generated on
+	// startup, which invalidates tokens on restart and across replicas
+	TokenSecret string
+	// TokenTTL is how long a regular token is valid
+	TokenTTL time.Duration
+	// RestrictedTokenTTL is how long a token that only permits a password change is valid
+	RestrictedTokenTTL time.Duration
+}
+
+// PasswordConfig holds the password policy
+type PasswordConfig struct {
+	// MaxAge forces a password change once a password is older than this; zero disables expiry
+	MaxAge time.Duration
+}
+
+// UsernameConfig holds username validation configuration
+type UsernameConfig struct {
+	// AllowUnicode permits letters and digits from any script in usernames
+	AllowUnicode bool
+}
+
+// IdentifierRulesConfig holds the files identifier rules are imported from on startup.
+// An empty path skips that kind of rule.
+type IdentifierRulesConfig struct {
+	ReservedUsernamesFile   string
+	UsernamePatternsFile    string
+	BlockedEmailDomainsFile string
+	AllowedEmailDomainsFile string
+}
+
 var (
 	instance *Config
 	once     sync.Once
@@ -63,6 +132,37 @@ func loadConfig() *Config {
 			MaxIdleConns:    getEnvAsInt("DATABASE_MAX_IDLE_CONNS", 25),
 			ConnMaxLifetime: time.Duration(getEnvAsInt("DATABASE_CONN_MAX_LIFETIME", 300)) * time.Second,
 		},
+		Tenancy: TenancyConfig{
+			Header:   getEnv("TENANCY_HEADER", "x-organization-id"),
+			Required: getEnvAsBool("TENANCY_REQUIRED", false),
+		},
+		Invitation: InvitationConfig{
+			TTL: getEnvAsDuration("INVITATION_TTL", 7*24*time.Hour),
+		},
+		AccountExpiry: AccountExpiryConfig{
+			Interval: getEnvAsDuration("ACCOUNT_EXPIRY_INTERVAL", time.Hour),
+		},
+		UserChanges: UserChangesConfig{
+			Retention:     getEnvAsDuration("USER_CHANGE_RETENTION", 7*24*time.Hour),
+			PruneInterval: getEnvAsDuration("USER_CHANGE_PRUNE_INTERVAL", time.Hour),
+		},
+		Auth: AuthConfig{
+			TokenSecret:        getEnv("AUTH_TOKEN_SECRET", ""),
+			TokenTTL:           getEnvAsDuration("AUTH_TOKEN_TTL", 24*time.Hour),
+			RestrictedTokenTTL: getEnvAsDuration("AUTH_RESTRICTED_TOKEN_TTL", 15*time.Minute),
+		},
+		Password: PasswordConfig{
+			MaxAge: getEnvAsDuration("PASSWORD_MAX_AGE", 0),
+		},
+		Username: UsernameConfig{
+			AllowUnicode: getEnvAsBool("USERNAME_ALLOW_UNICODE", false),
+		},
+		IdentifierRules: IdentifierRulesConfig{
+			ReservedUsernamesFile:   getEnv("RESERVED_USERNAMES_FILE", "configs/identifier_rules/reserved_usernames.txt"),
+			UsernamePatternsFile:    getEnv("USERNAME_PATTERNS_FILE", "configs/identifier_rules/username_patterns.txt"),
+			BlockedEmailDomainsFile: getEnv("BLOCKED_EMAIL_DOMAINS_FILE", "configs/identifier_rules/blocked_email_domains.txt"),
+			AllowedEmailDomainsFile: getEnv("ALLOWED_EMAIL_DOMAINS_FILE", ""),
+		},
 	}
 
 	return cfg
@@ -99,4 +199,28 @@ func getEnvAsInt(key string, defaultValue int) int {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getEnvAsBool gets an environment variable as a boolean or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsDuration gets an environment variable as a duration (e.g. "72h") or returns a default value
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	if value, err := time.ParseDuration(valueStr); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
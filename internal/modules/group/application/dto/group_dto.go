@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/domain/entity"
+	"github.com/google/uuid"
+)
+
+// CreateGroupDTO represents the data transfer object for creating a group
+type CreateGroupDTO struct {
+	Name        string
+	Description string
+	Roles       []string
+}
+
+// GroupDTO represents the data transfer object for a group
+type GroupDTO struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	Roles       []string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// MembersDTO represents the data transfer object for adding or removing group members
+type MembersDTO struct {
+	GroupID  uuid.UUID
+	UserIDs  []uuid.UUID
+	GroupIDs []uuid.UUID
+}
+
+// GroupMemberDTO represents the data transfer object for a group member
+type GroupMemberDTO struct {
+	Type      string
+	ID        uuid.UUID
+	CreatedAt *time.Time
+}
+
+// ListGroupMembersDTO represents the data transfer object for listing group members
+type ListGroupMembersDTO struct {
+	Members    []*GroupMemberDTO
+	Page       int
+	PageSize   int
+	TotalItems int
+	TotalPages int
+}
+
+// UserGroupsDTO represents the groups a user belongs to and the roles they grant
+type UserGroupsDTO struct {
+	Groups []*GroupDTO
+	Roles  []string
+}
+
+// ToEntity converts CreateGroupDTO to Group entity
+func (dto *CreateGroupDTO) ToEntity() *entity.Group {
+	group := &entity.Group{
+		Name:        dto.Name,
+		Description: dto.Description,
+	}
+	seen := make(map[string]bool, len(dto.Roles))
+	for _, role := range dto.Roles {
+		if seen[role] {
+			continue
+		}
+		seen[role] = true
+		group.Roles = append(group.Roles, entity.GroupRole{Role: role})
+	}
+	return group
+}
+
+// FromEntity creates a GroupDTO from Group entity
+func FromEntity(group *entity.Group) *GroupDTO {
+	return &GroupDTO{
+		ID:          group.ID,
+		Name:        group.Name,
+		Description: group.Description,
+		Roles:       group.RoleNames(),
+		CreatedAt:   group.CreatedAt,
+		UpdatedAt:   group.UpdatedAt,
+	}
+}
+
+// FromMemberEntity creates a GroupMemberDTO from GroupMember entity
+func FromMemberEntity(member *entity.GroupMember) *GroupMemberDTO {
+	createdAt := member.CreatedAt
+	return &GroupMemberDTO{
+		Type:      string(member.MemberType),
+		ID:        member.MemberID,
+		CreatedAt: &createdAt,
+	}
+}
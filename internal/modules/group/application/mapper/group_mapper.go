@@ -0,0 +1,65 @@
+package mapper
+
+import (
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/domain/entity"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/group"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GroupDTOToProto converts a GroupDTO to proto message
+func GroupDTOToProto(dto *dto.GroupDTO) *pb.Group {
+	if dto == nil {
+		return nil
+	}
+
+	return &pb.Group{
+		Id:          dto.ID.String(),
+		Name:        dto.Name,
+		Description: dto.Description,
+		Roles:       dto.Roles,
+		CreatedAt:   timestamppb.New(dto.CreatedAt),
+		UpdatedAt:   timestamppb.New(dto.UpdatedAt),
+	}
+}
+
+// GroupMemberDTOToProto converts a GroupMemberDTO to proto message
+func GroupMemberDTOToProto(dto *dto.GroupMemberDTO) *pb.GroupMember {
+	if dto == nil {
+		return nil
+	}
+
+	member := &pb.GroupMember{
+		Type: MemberTypeToProto(dto.Type),
+		Id:   dto.ID.String(),
+	}
+	if dto.CreatedAt != nil {
+		member.CreatedAt = timestamppb.New(*dto.CreatedAt)
+	}
+	return member
+}
+
+// MemberTypeToProto converts a member type string to proto enum
+func MemberTypeToProto(memberType string) pb.MemberType {
+	switch entity.MemberType(memberType) {
+	case entity.MemberTypeUser:
+		return pb.MemberType_MEMBER_TYPE_USER
+	case entity.MemberTypeGroup:
+		return pb.MemberType_MEMBER_TYPE_GROUP
+	default:
+		return pb.MemberType_MEMBER_TYPE_UNSPECIFIED
+	}
+}
+
+// CreateGroupRequestToDTO converts CreateGroupRequest to CreateGroupDTO
+func CreateGroupRequestToDTO(req *pb.CreateGroupRequest) *dto.CreateGroupDTO {
+	if req == nil {
+		return nil
+	}
+
+	return &dto.CreateGroupDTO{
+		Name:        req.Name,
+		Description: req.Description,
+		Roles:       req.Roles,
+	}
+}
@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/domain/service"
+	"github.com/google/uuid"
+)
+
+// GroupUseCase handles group-related business logic
+type GroupUseCase struct {
+	groupRepo      repository.GroupRepository
+	memberRepo     repository.GroupMemberRepository
+	groupService   *service.GroupService
+	administrators repository.Administrators
+}
+
+// NewGroupUseCase creates a new instance of GroupUseCase
+func NewGroupUseCase(groupRepo repository.GroupRepository, memberRepo repository.GroupMemberRepository, groupService *service.GroupService, administrators repository.Administrators) *GroupUseCase {
+	return &GroupUseCase{
+		groupRepo:      groupRepo,
+		memberRepo:     memberRepo,
+		groupService:   groupService,
+		administrators: administrators,
+	}
+}
+
+// requireAdmin returns ErrAdminRequired unless the caller is an administrator.
+// Groups grant roles, so only administrators change them or their members.
+func (uc *GroupUseCase) requireAdmin(ctx context.Context) error {
+	isAdmin, err := uc.administrators.IsAdmin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check caller: %w", err)
+	}
+	if !isAdmin {
+		return entity.ErrAdminRequired
+	}
+	return nil
+}
+
+// CreateGroup creates a new group
+func (uc *GroupUseCase) CreateGroup(ctx context.Context, createDTO *dto.CreateGroupDTO) (*dto.GroupDTO, error) {
+	if err := uc.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	group := createDTO.ToEntity()
+
+	if err := uc.groupService.CreateGroup(ctx, group); err != nil {
+		return nil, fmt.Errorf("failed to create group: %w", err)
+	}
+
+	return dto.FromEntity(group), nil
+}
+
+// GetGroup retrieves a group by ID
+func (uc *GroupUseCase) GetGroup(ctx context.Context, id string) (*dto.GroupDTO, error) {
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidGroupID, err)
+	}
+
+	group, err := uc.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+
+	return dto.FromEntity(group), nil
+}
+
+// DeleteGroup deletes a group (soft delete) and its memberships
+func (uc *GroupUseCase) DeleteGroup(ctx context.Context, id string) error {
+	if err := uc.requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", entity.ErrInvalidGroupID, err)
+	}
+
+	if err := uc.groupRepo.Delete(ctx, groupID); err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+
+	return nil
+}
+
+// AddMembers adds users and nested groups to a group
+func (uc *GroupUseCase) AddMembers(ctx context.Context, membersDTO *dto.MembersDTO) error {
+	if err := uc.requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	if err := uc.groupService.AddMembers(ctx, membersDTO.GroupID, membersDTO.UserIDs, membersDTO.GroupIDs); err != nil {
+		return fmt.Errorf("failed to add members: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMembers removes users and nested groups from a group
+func (uc *GroupUseCase) RemoveMembers(ctx context.Context, membersDTO *dto.MembersDTO) (int64, error) {
+	if err := uc.requireAdmin(ctx); err != nil {
+		return 0, err
+	}
+
+	// Check if group exists
+	if _, err := uc.groupRepo.GetByID(ctx, membersDTO.GroupID); err != nil {
+		return 0, fmt.Errorf("failed to get group: %w", err)
+	}
+
+	members := make([]*entity.GroupMember, 0, len(membersDTO.UserIDs)+len(membersDTO.GroupIDs))
+	for _, userID := range membersDTO.UserIDs {
+		members = append(members, &entity.GroupMember{
+			GroupID:    membersDTO.GroupID,
+			MemberType: entity.MemberTypeUser,
+			MemberID:   userID,
+		})
+	}
+	for _, groupID := range membersDTO.GroupIDs {
+		members = append(members, &entity.GroupMember{
+			GroupID:    membersDTO.GroupID,
+			MemberType: entity.MemberTypeGroup,
+			MemberID:   groupID,
+		})
+	}
+
+	removed, err := uc.memberRepo.Remove(ctx, members)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove members: %w", err)
+	}
+
+	return removed, nil
+}
+
+// ListGroupMembers lists the direct members of a group, or every user that
+// belongs to it through nested groups when effective is set
+func (uc *GroupUseCase) ListGroupMembers(ctx context.Context, groupID uuid.UUID, effective bool, page, pageSize int) (*dto.ListGroupMembersDTO, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	// Check if group exists
+	if _, err := uc.groupRepo.GetByID(ctx, groupID); err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+
+	var memberDTOs []*dto.GroupMemberDTO
+	var totalCount int64
+	if effective {
+		userIDs, err := uc.memberRepo.ListEffectiveUserIDs(ctx, groupID, offset, pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list effective members: %w", err)
+		}
+
+		totalCount, err = uc.memberRepo.CountEffectiveUsers(ctx, groupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count effective members: %w", err)
+		}
+
+		memberDTOs = make([]*dto.GroupMemberDTO, len(userIDs))
+		for i, userID := range userIDs {
+			memberDTOs[i] = &dto.GroupMemberDTO{
+				Type: string(entity.MemberTypeUser),
+				ID:   userID,
+			}
+		}
+	} else {
+		members, err := uc.memberRepo.ListDirect(ctx, groupID, offset, pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list members: %w", err)
+		}
+
+		totalCount, err = uc.memberRepo.CountDirect(ctx, groupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count members: %w", err)
+		}
+
+		memberDTOs = make([]*dto.GroupMemberDTO, len(members))
+		for i, member := range members {
+			memberDTOs[i] = dto.FromMemberEntity(member)
+		}
+	}
+
+	return &dto.ListGroupMembersDTO{
+		Members:    memberDTOs,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: int(totalCount),
+		TotalPages: int(math.Ceil(float64(totalCount) / float64(pageSize))),
+	}, nil
+}
+
+// ListUserGroups lists the groups a user belongs to and the roles they grant
+func (uc *GroupUseCase) ListUserGroups(ctx context.Context, userID uuid.UUID, effective bool) (*dto.UserGroupsDTO, error) {
+	var groups []*entity.Group
+	if effective {
+		var err error
+		groups, err = uc.groupService.EffectiveGroups(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list user groups: %w", err)
+		}
+	} else {
+		groupIDs, err := uc.memberRepo.DirectGroupIDsOfUser(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list user groups: %w", err)
+		}
+		groups, err = uc.groupRepo.GetByIDs(ctx, groupIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get groups: %w", err)
+		}
+	}
+
+	groupDTOs := make([]*dto.GroupDTO, len(groups))
+	for i, group := range groups {
+		groupDTOs[i] = dto.FromEntity(group)
+	}
+
+	return &dto.UserGroupsDTO{
+		Groups: groupDTOs,
+		Roles:  service.RolesOf(groups),
+	}, nil
+}
+
+// AssignRoles assigns roles to a group
+func (uc *GroupUseCase) AssignRoles(ctx context.Context, groupID uuid.UUID, roles []string) (*dto.GroupDTO, error) {
+	if err := uc.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := uc.groupService.AssignRoles(ctx, groupID, roles); err != nil {
+		return nil, fmt.Errorf("failed to assign roles: %w", err)
+	}
+
+	return uc.reload(ctx, groupID)
+}
+
+// RevokeRoles revokes roles from a group
+func (uc *GroupUseCase) RevokeRoles(ctx context.Context, groupID uuid.UUID, roles []string) (*dto.GroupDTO, error) {
+	if err := uc.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	// Check if group exists
+	if _, err := uc.groupRepo.GetByID(ctx, groupID); err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+
+	if err := uc.groupRepo.RemoveRoles(ctx, groupID, roles); err != nil {
+		return nil, fmt.Errorf("failed to revoke roles: %w", err)
+	}
+
+	return uc.reload(ctx, groupID)
+}
+
+// reload retrieves a group after its roles have changed
+func (uc *GroupUseCase) reload(ctx context.Context, groupID uuid.UUID) (*dto.GroupDTO, error) {
+	group, err := uc.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+
+	return dto.FromEntity(group), nil
+}
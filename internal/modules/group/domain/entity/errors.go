@@ -0,0 +1,30 @@
+package entity
+
+import "errors"
+
+var (
+	// ErrGroupNotFound is returned when a group is not found
+	ErrGroupNotFound = errors.New("group not found")
+
+	// ErrGroupAlreadyExists is returned when a group name is already in use
+	ErrGroupAlreadyExists = errors.New("group already exists")
+
+	// ErrInvalidGroup is returned when group data is invalid
+	ErrInvalidGroup = errors.New("invalid group")
+
+	// ErrInvalidGroupID is returned when a group ID is invalid
+	ErrInvalidGroupID = errors.New("invalid group ID")
+
+	// ErrInvalidRole is returned when a role name is invalid
+	ErrInvalidRole = errors.New("invalid role")
+
+	// ErrGroupCycle is returned when adding a nested group would create a cycle
+	ErrGroupCycle = errors.New("group membership cycle")
+
+	// ErrAdminRequired is returned when a caller who is not an administrator
+	// attempts to change groups, their members or their roles
+	ErrAdminRequired = errors.New("administrator privileges required")
+
+	// ErrMemberUserNotFound is returned when adding a user that does not exist
+	ErrMemberUserNotFound = errors.New("user not found")
+)
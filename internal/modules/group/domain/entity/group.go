@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Group represents a named set of users and nested groups
+type Group struct {
+	ID             uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrganizationID *uuid.UUID     `gorm:"type:uuid;uniqueIndex:idx_groups_organization_name" json:"organization_id,omitempty"`
+	Name           string         `gorm:"type:varchar(100);not null;uniqueIndex:idx_groups_organization_name" json:"name"`
+	Description    string         `gorm:"type:text" json:"description"`
+	Roles          []GroupRole    `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"roles"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// TableName specifies the table name for Group entity
+func (Group) TableName() string {
+	return "groups"
+}
+
+// BeforeCreate hook to set UUID before creating
+func (g *Group) BeforeCreate(tx *gorm.DB) error {
+	if g.ID == uuid.Nil {
+		g.ID = uuid.New()
+	}
+	return nil
+}
+
+// Validate validates the group entity
+func (g *Group) Validate() error {
+	g.Name = strings.TrimSpace(g.Name)
+	if g.Name == "" || len(g.Name) > 100 {
+		return fmt.Errorf("%w: name must be between 1 and 100 characters", ErrInvalidGroup)
+	}
+	for _, role := range g.Roles {
+		if err := ValidateRole(role.Role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RoleNames returns the names of the roles assigned to the group
+func (g *Group) RoleNames() []string {
+	names := make([]string, len(g.Roles))
+	for i, role := range g.Roles {
+		names[i] = role.Role
+	}
+	return names
+}
+
+// GroupRole assigns a role to every effective member of a group
+type GroupRole struct {
+	GroupID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"group_id"`
+	Role      string    `gorm:"type:varchar(100);primaryKey" json:"role"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GroupRole entity
+func (GroupRole) TableName() string {
+	return "group_roles"
+}
+
+var roleRegex = regexp.MustCompile(`^[a-z][a-z0-9_.:-]{0,99}$`)
+
+// ValidateRole validates a role name
+func ValidateRole(role string) error {
+	if !roleRegex.MatchString(role) {
+		return fmt.Errorf("%w: %q", ErrInvalidRole, role)
+	}
+	return nil
+}
+
+// MemberType distinguishes user members from nested group members
+type MemberType string
+
+const (
+	MemberTypeUser  MemberType = "user"
+	MemberTypeGroup MemberType = "group"
+)
+
+// GroupMember is a direct member of a group
+type GroupMember struct {
+	GroupID    uuid.UUID  `gorm:"type:uuid;primaryKey" json:"group_id"`
+	MemberType MemberType `gorm:"type:varchar(10);primaryKey" json:"member_type"`
+	MemberID   uuid.UUID  `gorm:"type:uuid;primaryKey;index" json:"member_id"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for GroupMember entity
+func (GroupMember) TableName() string {
+	return "group_members"
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/domain/entity"
+	"github.com/google/uuid"
+)
+
+// GroupRepository defines the interface for group data operations
+type GroupRepository interface {
+	// Create creates a new group together with its roles
+	Create(ctx context.Context, group *entity.Group) error
+
+	// GetByID retrieves a group by ID, including its roles
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Group, error)
+
+	// GetByName retrieves a group by name within the current tenant
+	GetByName(ctx context.Context, name string) (*entity.Group, error)
+
+	// GetByIDs retrieves groups by IDs, including their roles
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Group, error)
+
+	// Delete soft deletes a group and removes its memberships
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// AddRoles assigns roles to a group, ignoring roles already assigned
+	AddRoles(ctx context.Context, groupID uuid.UUID, roles []string) error
+
+	// RemoveRoles revokes roles from a group
+	RemoveRoles(ctx context.Context, groupID uuid.UUID, roles []string) error
+}
+
+// GroupMemberRepository defines the interface for group membership data operations
+type GroupMemberRepository interface {
+	// Add adds direct members to a group, ignoring existing memberships
+	Add(ctx context.Context, members []*entity.GroupMember) error
+
+	// Remove removes direct members from a group
+	Remove(ctx context.Context, members []*entity.GroupMember) (int64, error)
+
+	// ListDirect retrieves direct members of a group with pagination
+	ListDirect(ctx context.Context, groupID uuid.UUID, offset, limit int) ([]*entity.GroupMember, error)
+
+	// CountDirect returns the number of direct members of a group
+	CountDirect(ctx context.Context, groupID uuid.UUID) (int64, error)
+
+	// ListEffectiveUserIDs retrieves users that belong to a group directly or through nested groups
+	ListEffectiveUserIDs(ctx context.Context, groupID uuid.UUID, offset, limit int) ([]uuid.UUID, error)
+
+	// CountEffectiveUsers returns the number of users that belong to a group directly or through nested groups
+	CountEffectiveUsers(ctx context.Context, groupID uuid.UUID) (int64, error)
+
+	// LockNesting serializes changes to group nesting in the current tenant until the
+	// transaction of the context ends. Outside of a transaction it does not lock.
+	LockNesting(ctx context.Context) error
+
+	// DescendantGroupIDs returns the IDs of all groups nested, at any depth, in a group
+	DescendantGroupIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error)
+
+	// DirectGroupIDsOfUser returns the IDs of the groups a user is a direct member of
+	DirectGroupIDsOfUser(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+
+	// EffectiveGroupIDsOfUser returns the IDs of the groups a user belongs to directly or through nesting
+	EffectiveGroupIDsOfUser(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// UserDirectory is the view of the user module the group module depends on
+type UserDirectory interface {
+	// Exists checks if a user exists by ID
+	Exists(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// Administrators is the view of the user module's authorization the group module depends on
+type Administrators interface {
+	// IsAdmin reports whether the caller is an administrator of the current tenant
+	IsAdmin(ctx context.Context) (bool, error)
+}
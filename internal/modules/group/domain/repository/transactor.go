@@ -0,0 +1,11 @@
+package repository
+
+import "context"
+
+// Transactor runs functions within a database transaction
+type Transactor interface {
+	// WithinTransaction runs fn in a transaction that repositories called with the
+	// context passed to fn take part in. It commits when fn returns nil and rolls
+	// back otherwise.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+)
+
+// GroupService provides domain services for group operations
+type GroupService struct {
+	groupRepo     repository.GroupRepository
+	memberRepo    repository.GroupMemberRepository
+	userDirectory repository.UserDirectory
+	transactor    repository.Transactor
+}
+
+// NewGroupService creates a new instance of GroupService
+func NewGroupService(groupRepo repository.GroupRepository, memberRepo repository.GroupMemberRepository, userDirectory repository.UserDirectory, transactor repository.Transactor) *GroupService {
+	return &GroupService{
+		groupRepo:     groupRepo,
+		memberRepo:    memberRepo,
+		userDirectory: userDirectory,
+		transactor:    transactor,
+	}
+}
+
+// CreateGroup validates and creates a new group in the current tenant
+func (s *GroupService) CreateGroup(ctx context.Context, group *entity.Group) error {
+	if organizationID, ok := tenant.OrganizationIDFromContext(ctx); ok {
+		group.OrganizationID = &organizationID
+	}
+
+	if err := group.Validate(); err != nil {
+		return err
+	}
+
+	// Check if name is already in use
+	existing, err := s.groupRepo.GetByName(ctx, group.Name)
+	if err != nil && !errors.Is(err, entity.ErrGroupNotFound) {
+		return fmt.Errorf("failed to check group existence: %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: name already in use", entity.ErrGroupAlreadyExists)
+	}
+
+	if err := s.groupRepo.Create(ctx, group); err != nil {
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+
+	return nil
+}
+
+// AddMembers adds users and nested groups to a group, rejecting membership cycles
+func (s *GroupService) AddMembers(ctx context.Context, groupID uuid.UUID, userIDs, groupIDs []uuid.UUID) error {
+	// Check for cycles and add the members in one transaction, so that no other
+	// nesting commits in between
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.addMembers(ctx, groupID, userIDs, groupIDs)
+	})
+}
+
+// addMembers adds users and nested groups to a group within a transaction
+func (s *GroupService) addMembers(ctx context.Context, groupID uuid.UUID, userIDs, groupIDs []uuid.UUID) error {
+	// Check if group exists
+	if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
+		return fmt.Errorf("failed to get group: %w", err)
+	}
+
+	members := make([]*entity.GroupMember, 0, len(userIDs)+len(groupIDs))
+
+	for _, userID := range userIDs {
+		exists, err := s.userDirectory.Exists(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to check user existence: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("%w: %s", entity.ErrMemberUserNotFound, userID)
+		}
+		members = append(members, &entity.GroupMember{
+			GroupID:    groupID,
+			MemberType: entity.MemberTypeUser,
+			MemberID:   userID,
+		})
+	}
+
+	if len(groupIDs) > 0 {
+		// Hold off concurrent nestings until this one commits, and only then read
+		// the nesting the cycle check relies on
+		if err := s.memberRepo.LockNesting(ctx); err != nil {
+			return fmt.Errorf("failed to lock group nesting: %w", err)
+		}
+
+		// Nested groups must exist in the same tenant
+		nestedGroups, err := s.groupRepo.GetByIDs(ctx, groupIDs)
+		if err != nil {
+			return fmt.Errorf("failed to get nested groups: %w", err)
+		}
+		found := make(map[uuid.UUID]bool, len(nestedGroups))
+		for _, nestedGroup := range nestedGroups {
+			found[nestedGroup.ID] = true
+		}
+
+		for _, nestedGroupID := range groupIDs {
+			if !found[nestedGroupID] {
+				return fmt.Errorf("%w: %s", entity.ErrGroupNotFound, nestedGroupID)
+			}
+			if err := s.checkCycle(ctx, groupID, nestedGroupID); err != nil {
+				return err
+			}
+			members = append(members, &entity.GroupMember{
+				GroupID:    groupID,
+				MemberType: entity.MemberTypeGroup,
+				MemberID:   nestedGroupID,
+			})
+		}
+	}
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	if err := s.memberRepo.Add(ctx, members); err != nil {
+		return fmt.Errorf("failed to add members: %w", err)
+	}
+
+	return nil
+}
+
+// checkCycle rejects nesting child in parent when parent is already nested in child
+func (s *GroupService) checkCycle(ctx context.Context, parentID, childID uuid.UUID) error {
+	if parentID == childID {
+		return fmt.Errorf("%w: a group cannot contain itself", entity.ErrGroupCycle)
+	}
+
+	descendants, err := s.memberRepo.DescendantGroupIDs(ctx, childID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve nested groups: %w", err)
+	}
+	for _, descendantID := range descendants {
+		if descendantID == parentID {
+			return fmt.Errorf("%w: group %s already contains group %s", entity.ErrGroupCycle, childID, parentID)
+		}
+	}
+
+	return nil
+}
+
+// AssignRoles validates and assigns roles to a group
+func (s *GroupService) AssignRoles(ctx context.Context, groupID uuid.UUID, roles []string) error {
+	for _, role := range roles {
+		if err := entity.ValidateRole(role); err != nil {
+			return err
+		}
+	}
+
+	// Check if group exists
+	if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
+		return fmt.Errorf("failed to get group: %w", err)
+	}
+
+	if err := s.groupRepo.AddRoles(ctx, groupID, roles); err != nil {
+		return fmt.Errorf("failed to assign roles: %w", err)
+	}
+
+	return nil
+}
+
+// EffectiveGroups returns the groups a user belongs to directly or through nested groups
+func (s *GroupService) EffectiveGroups(ctx context.Context, userID uuid.UUID) ([]*entity.Group, error) {
+	groupIDs, err := s.memberRepo.EffectiveGroupIDsOfUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve effective groups: %w", err)
+	}
+	if len(groupIDs) == 0 {
+		return []*entity.Group{}, nil
+	}
+
+	groups, err := s.groupRepo.GetByIDs(ctx, groupIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups: %w", err)
+	}
+	return groups, nil
+}
+
+// EffectiveRoles returns the roles a user holds through its effective groups
+func (s *GroupService) EffectiveRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	groups, err := s.EffectiveGroups(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return RolesOf(groups), nil
+}
+
+// TenantRoles returns the roles a user holds through effective groups of the
+// current tenant. Without a tenant only groups outside any organization count,
+// so that a role granted within one organization does not apply across tenants.
+func (s *GroupService) TenantRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	groups, err := s.EffectiveGroups(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	organizationID, hasTenant := tenant.OrganizationIDFromContext(ctx)
+	tenantGroups := make([]*entity.Group, 0, len(groups))
+	for _, group := range groups {
+		if hasTenant && group.OrganizationID != nil && *group.OrganizationID == organizationID ||
+			!hasTenant && group.OrganizationID == nil {
+			tenantGroups = append(tenantGroups, group)
+		}
+	}
+	return RolesOf(tenantGroups), nil
+}
+
+// RolesOf returns the sorted, de-duplicated roles assigned to the given groups
+func RolesOf(groups []*entity.Group) []string {
+	seen := make(map[string]bool)
+	roles := make([]string, 0)
+	for _, group := range groups {
+		for _, role := range group.RoleNames() {
+			if !seen[role] {
+				seen[role] = true
+				roles = append(roles, role)
+			}
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
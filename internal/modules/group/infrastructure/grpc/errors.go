@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/domain/entity"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+)
+
+// errorRules maps the group module's domain errors to application errors
+var errorRules = apperrors.Rules(
+	// Invalid arguments
+	apperrors.Rule{Err: entity.ErrInvalidGroup, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_GROUP",
+		Translations: map[string]string{"ja": "グループが正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidGroupID, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_GROUP_ID",
+		Translations: map[string]string{"ja": "グループIDが正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidRole, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_ROLE",
+		Translations: map[string]string{"ja": "ロールが正しくありません"}},
+
+	// Missing resources
+	apperrors.Rule{Err: entity.ErrGroupNotFound, Code: apperrors.CodeNotFound, Reason: "GROUP_NOT_FOUND",
+		Translations: map[string]string{"ja": "グループが見つかりません"}},
+	apperrors.Rule{Err: entity.ErrMemberUserNotFound, Code: apperrors.CodeNotFound, Reason: "GROUP_MEMBER_USER_NOT_FOUND",
+		Translations: map[string]string{"ja": "追加するユーザーが見つかりません"}},
+
+	// Authorization
+	apperrors.Rule{Err: entity.ErrAdminRequired, Code: apperrors.CodePermissionDenied, Reason: "ADMIN_REQUIRED",
+		Translations: map[string]string{"ja": "管理者権限が必要です"}},
+
+	// Conflicts with existing resources
+	apperrors.Rule{Err: entity.ErrGroupAlreadyExists, Code: apperrors.CodeAlreadyExists, Reason: "GROUP_ALREADY_EXISTS",
+		Translations: map[string]string{"ja": "グループは既に存在します"}},
+
+	// Operations the current state does not allow
+	apperrors.Rule{Err: entity.ErrGroupCycle, Code: apperrors.CodeFailedPrecondition, Reason: "GROUP_CYCLE",
+		Translations: map[string]string{"ja": "グループの入れ子が循環しています"}},
+)
+
+// ErrorTranslator converts the group module's domain errors to application errors
+func ErrorTranslator() apperrors.Translator {
+	return errorRules
+}
@@ -0,0 +1,281 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/application/mapper"
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/application/usecase"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+	commonpb "github.com/gigi434/sample-grpc-server/pkg/generated/common"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/group"
+	"github.com/google/uuid"
+)
+
+// GroupServiceServer implements the GroupService gRPC server
+type GroupServiceServer struct {
+	pb.UnimplementedGroupServiceServer
+	groupUseCase *usecase.GroupUseCase
+}
+
+// NewGroupServiceServer creates a new GroupServiceServer instance
+func NewGroupServiceServer(groupUseCase *usecase.GroupUseCase) *GroupServiceServer {
+	return &GroupServiceServer{
+		groupUseCase: groupUseCase,
+	}
+}
+
+// CreateGroup creates a new group
+func (s *GroupServiceServer) CreateGroup(ctx context.Context, req *pb.CreateGroupRequest) (*pb.CreateGroupResponse, error) {
+	// Validate request
+	if req.Name == "" {
+		return nil, apperrors.MissingField("name")
+	}
+
+	// Create group
+	groupDTO, err := s.groupUseCase.CreateGroup(ctx, mapper.CreateGroupRequestToDTO(req))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateGroupResponse{
+		Group: mapper.GroupDTOToProto(groupDTO),
+	}, nil
+}
+
+// GetGroup retrieves a group by ID
+func (s *GroupServiceServer) GetGroup(ctx context.Context, req *pb.GetGroupRequest) (*pb.GetGroupResponse, error) {
+	// Validate request
+	if req.Id == "" {
+		return nil, apperrors.MissingField("id")
+	}
+
+	// Get group
+	groupDTO, err := s.groupUseCase.GetGroup(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetGroupResponse{
+		Group: mapper.GroupDTOToProto(groupDTO),
+	}, nil
+}
+
+// DeleteGroup deletes a group
+func (s *GroupServiceServer) DeleteGroup(ctx context.Context, req *pb.DeleteGroupRequest) (*pb.DeleteGroupResponse, error) {
+	// Validate request
+	if req.Id == "" {
+		return nil, apperrors.MissingField("id")
+	}
+
+	// Delete group
+	if err := s.groupUseCase.DeleteGroup(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteGroupResponse{
+		Success: true,
+		Message: "Group deleted successfully",
+	}, nil
+}
+
+// AddMembers adds users and nested groups to a group
+func (s *GroupServiceServer) AddMembers(ctx context.Context, req *pb.AddMembersRequest) (*pb.AddMembersResponse, error) {
+	// Validate request
+	membersDTO, err := parseMembers(req.GroupId, req.UserIds, req.GroupIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add members
+	if err := s.groupUseCase.AddMembers(ctx, membersDTO); err != nil {
+		return nil, err
+	}
+
+	return &pb.AddMembersResponse{
+		Success: true,
+		Message: "Members added successfully",
+	}, nil
+}
+
+// RemoveMembers removes users and nested groups from a group
+func (s *GroupServiceServer) RemoveMembers(ctx context.Context, req *pb.RemoveMembersRequest) (*pb.RemoveMembersResponse, error) {
+	// Validate request
+	membersDTO, err := parseMembers(req.GroupId, req.UserIds, req.GroupIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove members
+	removed, err := s.groupUseCase.RemoveMembers(ctx, membersDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RemoveMembersResponse{
+		RemovedCount: int32(removed),
+	}, nil
+}
+
+// ListGroupMembers lists the direct or effective members of a group
+func (s *GroupServiceServer) ListGroupMembers(ctx context.Context, req *pb.ListGroupMembersRequest) (*pb.ListGroupMembersResponse, error) {
+	// Validate request
+	groupID, err := uuid.Parse(req.GroupId)
+	if err != nil {
+		return nil, apperrors.InvalidField("group_id", "must be a UUID")
+	}
+
+	page, pageSize := paginationParams(req.Pagination)
+
+	// List members
+	listDTO, err := s.groupUseCase.ListGroupMembers(ctx, groupID, req.Effective, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to proto
+	members := make([]*pb.GroupMember, len(listDTO.Members))
+	for i, memberDTO := range listDTO.Members {
+		members[i] = mapper.GroupMemberDTOToProto(memberDTO)
+	}
+
+	return &pb.ListGroupMembersResponse{
+		Members:    members,
+		Pagination: paginationResponse(listDTO.Page, listDTO.PageSize, listDTO.TotalItems, listDTO.TotalPages),
+	}, nil
+}
+
+// ListUserGroups lists the groups a user belongs to
+func (s *GroupServiceServer) ListUserGroups(ctx context.Context, req *pb.ListUserGroupsRequest) (*pb.ListUserGroupsResponse, error) {
+	// Validate request
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, apperrors.InvalidField("user_id", "must be a UUID")
+	}
+
+	// List groups
+	resultDTO, err := s.groupUseCase.ListUserGroups(ctx, userID, req.Effective)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to proto
+	groups := make([]*pb.Group, len(resultDTO.Groups))
+	for i, groupDTO := range resultDTO.Groups {
+		groups[i] = mapper.GroupDTOToProto(groupDTO)
+	}
+
+	return &pb.ListUserGroupsResponse{
+		Groups: groups,
+		Roles:  resultDTO.Roles,
+	}, nil
+}
+
+// AssignRoles assigns roles to a group
+func (s *GroupServiceServer) AssignRoles(ctx context.Context, req *pb.AssignRolesRequest) (*pb.AssignRolesResponse, error) {
+	// Validate request
+	groupID, err := uuid.Parse(req.GroupId)
+	if err != nil {
+		return nil, apperrors.InvalidField("group_id", "must be a UUID")
+	}
+	if len(req.Roles) == 0 {
+		return nil, apperrors.MissingField("roles")
+	}
+
+	// Assign roles
+	groupDTO, err := s.groupUseCase.AssignRoles(ctx, groupID, req.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AssignRolesResponse{
+		Group: mapper.GroupDTOToProto(groupDTO),
+	}, nil
+}
+
+// RevokeRoles revokes roles from a group
+func (s *GroupServiceServer) RevokeRoles(ctx context.Context, req *pb.RevokeRolesRequest) (*pb.RevokeRolesResponse, error) {
+	// Validate request
+	groupID, err := uuid.Parse(req.GroupId)
+	if err != nil {
+		return nil, apperrors.InvalidField("group_id", "must be a UUID")
+	}
+	if len(req.Roles) == 0 {
+		return nil, apperrors.MissingField("roles")
+	}
+
+	// Revoke roles
+	groupDTO, err := s.groupUseCase.RevokeRoles(ctx, groupID, req.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RevokeRolesResponse{
+		Group: mapper.GroupDTOToProto(groupDTO),
+	}, nil
+}
+
+// parseMembers parses and validates the group ID and member IDs of a membership request
+func parseMembers(groupID string, userIDs, groupIDs []string) (*dto.MembersDTO, error) {
+	parsedGroupID, err := uuid.Parse(groupID)
+	if err != nil {
+		return nil, apperrors.InvalidField("group_id", "must be a UUID")
+	}
+	if len(userIDs) == 0 && len(groupIDs) == 0 {
+		return nil, apperrors.New(apperrors.CodeInvalidArgument, apperrors.ReasonMissingField, "user_ids or group_ids are required").
+			WithViolation("user_ids", "user_ids or group_ids are required").
+			WithViolation("group_ids", "user_ids or group_ids are required")
+	}
+
+	membersDTO := &dto.MembersDTO{
+		GroupID:  parsedGroupID,
+		UserIDs:  make([]uuid.UUID, len(userIDs)),
+		GroupIDs: make([]uuid.UUID, len(groupIDs)),
+	}
+	for i, userID := range userIDs {
+		if membersDTO.UserIDs[i], err = uuid.Parse(userID); err != nil {
+			return nil, apperrors.InvalidField(fmt.Sprintf("user_ids[%d]", i), "must be a UUID")
+		}
+	}
+	for i, memberGroupID := range groupIDs {
+		if membersDTO.GroupIDs[i], err = uuid.Parse(memberGroupID); err != nil {
+			return nil, apperrors.InvalidField(fmt.Sprintf("group_ids[%d]", i), "must be a UUID")
+		}
+	}
+	return membersDTO, nil
+}
+
+// paginationParams extracts page and page size with defaults and limits
+func paginationParams(pagination *commonpb.PaginationRequest) (int, int) {
+	// Default pagination
+	page := 1
+	pageSize := 10
+
+	if pagination != nil {
+		if pagination.Page > 0 {
+			page = int(pagination.Page)
+		}
+		if pagination.PageSize > 0 {
+			pageSize = int(pagination.PageSize)
+		}
+		// Limit page size to prevent abuse
+		if pageSize > 100 {
+			pageSize = 100
+		}
+	}
+
+	return page, pageSize
+}
+
+// paginationResponse builds pagination metadata for list responses
+func paginationResponse(page, pageSize, totalItems, totalPages int) *commonpb.PaginationResponse {
+	return &commonpb.PaginationResponse{
+		Page:        int32(page),
+		PageSize:    int32(pageSize),
+		TotalItems:  int32(totalItems),
+		TotalPages:  int32(totalPages),
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}
+}
@@ -0,0 +1,270 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/database"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Recursive queries walk nested groups. UNION (rather than UNION ALL) discards
+// rows that were already visited, so the walk terminates even on cyclic data.
+const (
+	// descendantGroupsSQL selects every group nested, at any depth, in a group
+	descendantGroupsSQL = `
+WITH RECURSIVE descendants(id) AS (
+	SELECT member_id FROM group_members WHERE group_id = @group AND member_type = @groupType
+	UNION
+	SELECT gm.member_id FROM group_members gm
+	JOIN descendants d ON gm.group_id = d.id
+	WHERE gm.member_type = @groupType
+)
+SELECT id FROM descendants`
+
+	// effectiveUsersSQL selects every user in a group or in any group nested in it
+	effectiveUsersSQL = `
+WITH RECURSIVE tree(id) AS (
+	SELECT CAST(@group AS uuid)
+	UNION
+	SELECT gm.member_id FROM group_members gm
+	JOIN tree t ON gm.group_id = t.id
+	WHERE gm.member_type = @groupType
+)
+SELECT DISTINCT gm.member_id AS id FROM group_members gm
+JOIN tree t ON gm.group_id = t.id
+WHERE gm.member_type = @userType`
+
+	// ancestorGroupsSQL selects every group a user belongs to directly or through nesting
+	ancestorGroupsSQL = `
+WITH RECURSIVE ancestors(id) AS (
+	SELECT group_id FROM group_members WHERE member_type = @userType AND member_id = @user
+	UNION
+	SELECT gm.group_id FROM group_members gm
+	JOIN ancestors a ON gm.member_id = a.id
+	WHERE gm.member_type = @groupType
+)
+SELECT id FROM ancestors`
+)
+
+// groupMemberRepository implements repository.GroupMemberRepository
+type groupMemberRepository struct {
+	// We don't store the DB connection here, we get it from config singleton
+}
+
+// NewGroupMemberRepository creates a new instance of GroupMemberRepository
+func NewGroupMemberRepository() repository.GroupMemberRepository {
+	return &groupMemberRepository{}
+}
+
+// getDB gets the database connection, joining the transaction of the context if any
+func (r *groupMemberRepository) getDB(ctx context.Context) (*gorm.DB, error) {
+	return database.Conn(ctx)
+}
+
+// Add adds direct members to a group, ignoring existing memberships
+func (r *groupMemberRepository) Add(ctx context.Context, members []*entity.GroupMember) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	if err := db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&members).Error; err != nil {
+		return fmt.Errorf("failed to add group members: %w", err)
+	}
+	return nil
+}
+
+// Remove removes direct members from a group
+func (r *groupMemberRepository) Remove(ctx context.Context, members []*entity.GroupMember) (int64, error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var removed int64
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, member := range members {
+			result := tx.Where("group_id = ? AND member_type = ? AND member_id = ?",
+				member.GroupID, member.MemberType, member.MemberID).
+				Delete(&entity.GroupMember{})
+			if result.Error != nil {
+				return result.Error
+			}
+			removed += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove group members: %w", err)
+	}
+	return removed, nil
+}
+
+// ListDirect retrieves direct members of a group with pagination
+func (r *groupMemberRepository) ListDirect(ctx context.Context, groupID uuid.UUID, offset, limit int) ([]*entity.GroupMember, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var members []*entity.GroupMember
+	if err := db.WithContext(ctx).
+		Where("group_id = ?", groupID).
+		Order("created_at ASC, member_id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("failed to list group members: %w", err)
+	}
+	return members, nil
+}
+
+// CountDirect returns the number of direct members of a group
+func (r *groupMemberRepository) CountDirect(ctx context.Context, groupID uuid.UUID) (int64, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&entity.GroupMember{}).
+		Where("group_id = ?", groupID).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count group members: %w", err)
+	}
+	return count, nil
+}
+
+// ListEffectiveUserIDs retrieves users that belong to a group directly or through nested groups
+func (r *groupMemberRepository) ListEffectiveUserIDs(ctx context.Context, groupID uuid.UUID, offset, limit int) ([]uuid.UUID, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var ids []uuid.UUID
+	if err := db.WithContext(ctx).
+		Raw(effectiveUsersSQL+" ORDER BY id OFFSET @offset LIMIT @limit", r.namedArgs(groupID, uuid.Nil, map[string]interface{}{
+			"offset": offset,
+			"limit":  limit,
+		})).
+		Scan(&ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to list effective group members: %w", err)
+	}
+	return ids, nil
+}
+
+// CountEffectiveUsers returns the number of users that belong to a group directly or through nested groups
+func (r *groupMemberRepository) CountEffectiveUsers(ctx context.Context, groupID uuid.UUID) (int64, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Raw("SELECT COUNT(*) FROM ("+effectiveUsersSQL+") effective", r.namedArgs(groupID, uuid.Nil, nil)).
+		Scan(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count effective group members: %w", err)
+	}
+	return count, nil
+}
+
+// LockNesting takes a transaction-level advisory lock on the group nesting of the
+// current tenant. Two nestings may only close a cycle together when neither sees
+// the other, and they can involve disjoint groups, so the lock covers the tenant.
+func (r *groupMemberRepository) LockNesting(ctx context.Context) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	key := "group_nesting"
+	if organizationID, ok := tenant.OrganizationIDFromContext(ctx); ok {
+		key += ":" + organizationID.String()
+	}
+	if err := db.WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", key).Error; err != nil {
+		return fmt.Errorf("failed to lock group nesting: %w", err)
+	}
+	return nil
+}
+
+// DescendantGroupIDs returns the IDs of all groups nested, at any depth, in a group
+func (r *groupMemberRepository) DescendantGroupIDs(ctx context.Context, groupID uuid.UUID) ([]uuid.UUID, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var ids []uuid.UUID
+	if err := db.WithContext(ctx).
+		Raw(descendantGroupsSQL, r.namedArgs(groupID, uuid.Nil, nil)).
+		Scan(&ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to list nested groups: %w", err)
+	}
+	return ids, nil
+}
+
+// DirectGroupIDsOfUser returns the IDs of the groups a user is a direct member of
+func (r *groupMemberRepository) DirectGroupIDsOfUser(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var ids []uuid.UUID
+	if err := db.WithContext(ctx).
+		Model(&entity.GroupMember{}).
+		Where("member_type = ? AND member_id = ?", entity.MemberTypeUser, userID).
+		Pluck("group_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to list groups of user: %w", err)
+	}
+	return ids, nil
+}
+
+// EffectiveGroupIDsOfUser returns the IDs of the groups a user belongs to directly or through nesting
+func (r *groupMemberRepository) EffectiveGroupIDsOfUser(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var ids []uuid.UUID
+	if err := db.WithContext(ctx).
+		Raw(ancestorGroupsSQL, r.namedArgs(uuid.Nil, userID, nil)).
+		Scan(&ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to list effective groups of user: %w", err)
+	}
+	return ids, nil
+}
+
+// namedArgs builds the named arguments shared by the recursive queries
+func (r *groupMemberRepository) namedArgs(groupID, userID uuid.UUID, extra map[string]interface{}) map[string]interface{} {
+	args := map[string]interface{}{
+		"group":     groupID,
+		"user":      userID,
+		"groupType": entity.MemberTypeGroup,
+		"userType":  entity.MemberTypeUser,
+	}
+	for key, value := range extra {
+		args[key] = value
+	}
+	return args
+}
@@ -0,0 +1,181 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/group/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/database"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// groupRepository implements repository.GroupRepository
+type groupRepository struct {
+	// We don't store the DB connection here, we get it from config singleton
+}
+
+// NewGroupRepository creates a new instance of GroupRepository
+func NewGroupRepository() repository.GroupRepository {
+	return &groupRepository{}
+}
+
+// getDB gets the database connection, joining the transaction of the context if any
+func (r *groupRepository) getDB(ctx context.Context) (*gorm.DB, error) {
+	return database.Conn(ctx)
+}
+
+// scoped restricts queries to groups of the current tenant and preloads roles
+func (r *groupRepository) scoped(ctx context.Context, db *gorm.DB) *gorm.DB {
+	return db.WithContext(ctx).
+		Scopes(tenant.ScopeOrganization(ctx, "organization_id")).
+		Preload("Roles", func(db *gorm.DB) *gorm.DB {
+			return db.Order("role ASC")
+		})
+}
+
+// Create creates a new group together with its roles
+func (r *groupRepository) Create(ctx context.Context, group *entity.Group) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	if err := db.WithContext(ctx).Create(group).Error; err != nil {
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a group by ID, including its roles
+func (r *groupRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Group, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var group entity.Group
+	if err := r.scoped(ctx, db).First(&group, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrGroupNotFound
+		}
+		return nil, fmt.Errorf("failed to get group by ID: %w", err)
+	}
+	return &group, nil
+}
+
+// GetByName retrieves a group by name within the current tenant
+func (r *groupRepository) GetByName(ctx context.Context, name string) (*entity.Group, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := r.scoped(ctx, db).Where("name = ?", name)
+	if _, ok := tenant.OrganizationIDFromContext(ctx); !ok {
+		query = query.Where("organization_id IS NULL")
+	}
+
+	var group entity.Group
+	if err := query.First(&group).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrGroupNotFound
+		}
+		return nil, fmt.Errorf("failed to get group by name: %w", err)
+	}
+	return &group, nil
+}
+
+// GetByIDs retrieves groups by IDs, including their roles
+func (r *groupRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Group, error) {
+	if len(ids) == 0 {
+		return []*entity.Group{}, nil
+	}
+
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var groups []*entity.Group
+	if err := r.scoped(ctx, db).
+		Where("id IN ?", ids).
+		Order("name ASC").
+		Find(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed to get groups by IDs: %w", err)
+	}
+	return groups, nil
+}
+
+// Delete soft deletes a group and removes its memberships
+func (r *groupRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Scopes(tenant.ScopeOrganization(ctx, "organization_id")).
+			Delete(&entity.Group{}, "id = ?", id)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete group: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrGroupNotFound
+		}
+
+		// Drop the group's own members and its membership in parent groups
+		if err := tx.Where("group_id = ? OR (member_type = ? AND member_id = ?)", id, entity.MemberTypeGroup, id).
+			Delete(&entity.GroupMember{}).Error; err != nil {
+			return fmt.Errorf("failed to delete group memberships: %w", err)
+		}
+		return nil
+	})
+}
+
+// AddRoles assigns roles to a group, ignoring roles already assigned
+func (r *groupRepository) AddRoles(ctx context.Context, groupID uuid.UUID, roles []string) error {
+	if len(roles) == 0 {
+		return nil
+	}
+
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	groupRoles := make([]entity.GroupRole, len(roles))
+	for i, role := range roles {
+		groupRoles[i] = entity.GroupRole{GroupID: groupID, Role: role}
+	}
+
+	if err := db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&groupRoles).Error; err != nil {
+		return fmt.Errorf("failed to add group roles: %w", err)
+	}
+	return nil
+}
+
+// RemoveRoles revokes roles from a group
+func (r *groupRepository) RemoveRoles(ctx context.Context, groupID uuid.UUID, roles []string) error {
+	if len(roles) == 0 {
+		return nil
+	}
+
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	if err := db.WithContext(ctx).
+		Where("group_id = ? AND role IN ?", groupID, roles).
+		Delete(&entity.GroupRole{}).Error; err != nil {
+		return fmt.Errorf("failed to remove group roles: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+)
+
+// CreateOrganizationDTO represents the data transfer object for creating an organization
+type CreateOrganizationDTO struct {
+	Name                string
+	Slug                string
+	UserUniquenessScope string
+}
+
+// UpdateOrganizationDTO represents the data transfer object for updating an organization
+type UpdateOrganizationDTO struct {
+	ID                  uuid.UUID
+	Name                *string
+	Slug                *string
+	UserUniquenessScope *string
+}
+
+// OrganizationDTO represents the data transfer object for an organization
+type OrganizationDTO struct {
+	ID                  uuid.UUID
+	Name                string
+	Slug                string
+	UserUniquenessScope string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// MembershipDTO represents the data transfer object for an organization membership
+type MembershipDTO struct {
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+	Role           string
+	CreatedAt      time.Time
+}
+
+// AddMemberDTO represents the data transfer object for adding a member
+type AddMemberDTO struct {
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+	Role           string
+}
+
+// ListOrganizationsDTO represents the data transfer object for listing organizations
+type ListOrganizationsDTO struct {
+	Organizations []*OrganizationDTO
+	Page          int
+	PageSize      int
+	TotalItems    int
+	TotalPages    int
+}
+
+// ListMembersDTO represents the data transfer object for listing members
+type ListMembersDTO struct {
+	Memberships []*MembershipDTO
+	Page        int
+	PageSize    int
+	TotalItems  int
+	TotalPages  int
+}
+
+// UserOrganizationsDTO represents the organizations a user belongs to
+type UserOrganizationsDTO struct {
+	Organizations []*OrganizationDTO
+	Memberships   []*MembershipDTO
+}
+
+// ToEntity converts CreateOrganizationDTO to Organization entity
+func (dto *CreateOrganizationDTO) ToEntity() *entity.Organization {
+	return &entity.Organization{
+		Name:                dto.Name,
+		Slug:                dto.Slug,
+		UserUniquenessScope: tenant.UniquenessScope(dto.UserUniquenessScope),
+	}
+}
+
+// FromEntity creates an OrganizationDTO from Organization entity
+func FromEntity(organization *entity.Organization) *OrganizationDTO {
+	return &OrganizationDTO{
+		ID:                  organization.ID,
+		Name:                organization.Name,
+		Slug:                organization.Slug,
+		UserUniquenessScope: string(organization.UserUniquenessScope),
+		CreatedAt:           organization.CreatedAt,
+		UpdatedAt:           organization.UpdatedAt,
+	}
+}
+
+// FromMembershipEntity creates a MembershipDTO from Membership entity
+func FromMembershipEntity(membership *entity.Membership) *MembershipDTO {
+	return &MembershipDTO{
+		OrganizationID: membership.OrganizationID,
+		UserID:         membership.UserID,
+		Role:           string(membership.Role),
+		CreatedAt:      membership.CreatedAt,
+	}
+}
@@ -0,0 +1,150 @@
+package mapper
+
+import (
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/organization"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// OrganizationDTOToProto converts an OrganizationDTO to proto message
+func OrganizationDTOToProto(dto *dto.OrganizationDTO) *pb.Organization {
+	if dto == nil {
+		return nil
+	}
+
+	return &pb.Organization{
+		Id:                  dto.ID.String(),
+		Name:                dto.Name,
+		Slug:                dto.Slug,
+		UserUniquenessScope: UniquenessScopeToProto(dto.UserUniquenessScope),
+		CreatedAt:           timestamppb.New(dto.CreatedAt),
+		UpdatedAt:           timestamppb.New(dto.UpdatedAt),
+	}
+}
+
+// MembershipDTOToProto converts a MembershipDTO to proto message
+func MembershipDTOToProto(dto *dto.MembershipDTO) *pb.Membership {
+	if dto == nil {
+		return nil
+	}
+
+	return &pb.Membership{
+		OrganizationId: dto.OrganizationID.String(),
+		UserId:         dto.UserID.String(),
+		Role:           MembershipRoleToProto(dto.Role),
+		CreatedAt:      timestamppb.New(dto.CreatedAt),
+	}
+}
+
+// UniquenessScopeToProto converts a uniqueness scope string to proto enum
+func UniquenessScopeToProto(scope string) pb.UniquenessScope {
+	switch tenant.UniquenessScope(scope) {
+	case tenant.UniquenessGlobal:
+		return pb.UniquenessScope_UNIQUENESS_SCOPE_GLOBAL
+	case tenant.UniquenessOrganization:
+		return pb.UniquenessScope_UNIQUENESS_SCOPE_ORGANIZATION
+	default:
+		return pb.UniquenessScope_UNIQUENESS_SCOPE_UNSPECIFIED
+	}
+}
+
+// UniquenessScopeFromProto converts a proto enum to a uniqueness scope string
+func UniquenessScopeFromProto(scope pb.UniquenessScope) string {
+	switch scope {
+	case pb.UniquenessScope_UNIQUENESS_SCOPE_GLOBAL:
+		return string(tenant.UniquenessGlobal)
+	case pb.UniquenessScope_UNIQUENESS_SCOPE_ORGANIZATION:
+		return string(tenant.UniquenessOrganization)
+	default:
+		return ""
+	}
+}
+
+// MembershipRoleToProto converts a membership role string to proto enum
+func MembershipRoleToProto(role string) pb.MembershipRole {
+	switch entity.MembershipRole(role) {
+	case entity.MembershipRoleMember:
+		return pb.MembershipRole_MEMBERSHIP_ROLE_MEMBER
+	case entity.MembershipRoleAdmin:
+		return pb.MembershipRole_MEMBERSHIP_ROLE_ADMIN
+	case entity.MembershipRoleOwner:
+		return pb.MembershipRole_MEMBERSHIP_ROLE_OWNER
+	default:
+		return pb.MembershipRole_MEMBERSHIP_ROLE_UNSPECIFIED
+	}
+}
+
+// MembershipRoleFromProto converts a proto enum to a membership role string
+func MembershipRoleFromProto(role pb.MembershipRole) string {
+	switch role {
+	case pb.MembershipRole_MEMBERSHIP_ROLE_MEMBER:
+		return string(entity.MembershipRoleMember)
+	case pb.MembershipRole_MEMBERSHIP_ROLE_ADMIN:
+		return string(entity.MembershipRoleAdmin)
+	case pb.MembershipRole_MEMBERSHIP_ROLE_OWNER:
+		return string(entity.MembershipRoleOwner)
+	default:
+		return ""
+	}
+}
+
+// CreateOrganizationRequestToDTO converts CreateOrganizationRequest to CreateOrganizationDTO
+func CreateOrganizationRequestToDTO(req *pb.CreateOrganizationRequest) *dto.CreateOrganizationDTO {
+	if req == nil {
+		return nil
+	}
+
+	return &dto.CreateOrganizationDTO{
+		Name:                req.Name,
+		Slug:                req.Slug,
+		UserUniquenessScope: UniquenessScopeFromProto(req.UserUniquenessScope),
+	}
+}
+
+// UpdateOrganizationRequestToDTO converts UpdateOrganizationRequest to UpdateOrganizationDTO
+func UpdateOrganizationRequestToDTO(req *pb.UpdateOrganizationRequest) (*dto.UpdateOrganizationDTO, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidOrganizationID, err)
+	}
+
+	dto := &dto.UpdateOrganizationDTO{
+		ID: id,
+	}
+
+	var scope *string
+	if req.UserUniquenessScope != nil {
+		value := UniquenessScopeFromProto(*req.UserUniquenessScope)
+		scope = &value
+	}
+
+	// Check field mask to determine which fields to update
+	if req.UpdateMask != nil {
+		for _, path := range req.UpdateMask.Paths {
+			switch path {
+			case "name":
+				dto.Name = req.Name
+			case "slug":
+				dto.Slug = req.Slug
+			case "user_uniqueness_scope":
+				dto.UserUniquenessScope = scope
+			}
+		}
+	} else {
+		// If no field mask, update all provided fields
+		dto.Name = req.Name
+		dto.Slug = req.Slug
+		dto.UserUniquenessScope = scope
+	}
+
+	return dto, nil
+}
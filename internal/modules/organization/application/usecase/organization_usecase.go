@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/service"
+	"github.com/gigi434/sample-grpc-server/internal/shared/auth"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+)
+
+// OrganizationUseCase handles organization-related business logic
+type OrganizationUseCase struct {
+	organizationRepo    repository.OrganizationRepository
+	membershipRepo      repository.MembershipRepository
+	organizationService *service.OrganizationService
+	administrators      repository.Administrators
+}
+
+// NewOrganizationUseCase creates a new instance of OrganizationUseCase
+func NewOrganizationUseCase(organizationRepo repository.OrganizationRepository, membershipRepo repository.MembershipRepository, organizationService *service.OrganizationService, administrators repository.Administrators) *OrganizationUseCase {
+	return &OrganizationUseCase{
+		organizationRepo:    organizationRepo,
+		membershipRepo:      membershipRepo,
+		organizationService: organizationService,
+		administrators:      administrators,
+	}
+}
+
+// requirePlatformAdmin returns ErrOrganizationAdminRequired unless the caller is a
+// platform administrator
+func (uc *OrganizationUseCase) requirePlatformAdmin(ctx context.Context) error {
+	isAdmin, err := uc.administrators.IsAdmin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check caller: %w", err)
+	}
+	if !isAdmin {
+		return entity.ErrOrganizationAdminRequired
+	}
+	return nil
+}
+
+// requireOrganizationAdmin returns ErrOrganizationAdminRequired unless the caller is
+// a platform administrator or an owner or admin of the organization. It returns the
+// role the caller acts with, the owner role for platform administrators, so that
+// members cannot grant or revoke roles above their own.
+func (uc *OrganizationUseCase) requireOrganizationAdmin(ctx context.Context, organizationID uuid.UUID) (entity.MembershipRole, error) {
+	isAdmin, err := uc.administrators.IsAdmin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to check caller: %w", err)
+	}
+	if isAdmin {
+		return entity.MembershipRoleOwner, nil
+	}
+
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return "", entity.ErrOrganizationAdminRequired
+	}
+	role, err := uc.organizationService.MemberRole(ctx, organizationID, claims.UserID)
+	if err != nil {
+		return "", err
+	}
+	if role.Rank() < entity.MembershipRoleAdmin.Rank() {
+		return "", entity.ErrOrganizationAdminRequired
+	}
+	return role, nil
+}
+
+// CreateOrganization creates a new organization
+func (uc *OrganizationUseCase) CreateOrganization(ctx context.Context, createDTO *dto.CreateOrganizationDTO) (*dto.OrganizationDTO, error) {
+	// Organizations are tenants, so only platform administrators create them
+	if err := uc.requirePlatformAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	organization := createDTO.ToEntity()
+
+	if err := uc.organizationService.CreateOrganization(ctx, organization); err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	return dto.FromEntity(organization), nil
+}
+
+// GetOrganization retrieves an organization by ID
+func (uc *OrganizationUseCase) GetOrganization(ctx context.Context, id string) (*dto.OrganizationDTO, error) {
+	organizationID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidOrganizationID, err)
+	}
+
+	organization, err := uc.organizationRepo.GetByID(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	return dto.FromEntity(organization), nil
+}
+
+// ListOrganizations retrieves a list of organizations with pagination. Platform
+// administrators see every organization; other callers see the ones they belong to.
+func (uc *OrganizationUseCase) ListOrganizations(ctx context.Context, page, pageSize int) (*dto.ListOrganizationsDTO, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	isAdmin, err := uc.administrators.IsAdmin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check caller: %w", err)
+	}
+
+	var organizations []*entity.Organization
+	var totalCount int64
+	if isAdmin {
+		organizations, err = uc.organizationRepo.List(ctx, offset, pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list organizations: %w", err)
+		}
+
+		totalCount, err = uc.organizationRepo.Count(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count organizations: %w", err)
+		}
+	} else if claims, ok := auth.ClaimsFromContext(ctx); ok {
+		// A user belongs to few organizations, so they are paginated in memory
+		organizations, err = uc.organizationRepo.ListByUser(ctx, claims.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list organizations: %w", err)
+		}
+
+		totalCount = int64(len(organizations))
+		organizations = organizations[min(offset, len(organizations)):min(offset+pageSize, len(organizations))]
+	}
+
+	organizationDTOs := make([]*dto.OrganizationDTO, len(organizations))
+	for i, organization := range organizations {
+		organizationDTOs[i] = dto.FromEntity(organization)
+	}
+
+	return &dto.ListOrganizationsDTO{
+		Organizations: organizationDTOs,
+		Page:          page,
+		PageSize:      pageSize,
+		TotalItems:    int(totalCount),
+		TotalPages:    int(math.Ceil(float64(totalCount) / float64(pageSize))),
+	}, nil
+}
+
+// UpdateOrganization updates an existing organization
+func (uc *OrganizationUseCase) UpdateOrganization(ctx context.Context, updateDTO *dto.UpdateOrganizationDTO) (*dto.OrganizationDTO, error) {
+	if _, err := uc.requireOrganizationAdmin(ctx, updateDTO.ID); err != nil {
+		return nil, err
+	}
+
+	organization, err := uc.organizationRepo.GetByID(ctx, updateDTO.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	if updateDTO.Name != nil {
+		organization.Name = *updateDTO.Name
+	}
+	if updateDTO.Slug != nil {
+		organization.Slug = *updateDTO.Slug
+	}
+	if updateDTO.UserUniquenessScope != nil {
+		organization.UserUniquenessScope = tenant.UniquenessScope(*updateDTO.UserUniquenessScope)
+	}
+
+	if err := uc.organizationService.UpdateOrganization(ctx, organization); err != nil {
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+
+	return dto.FromEntity(organization), nil
+}
+
+// DeleteOrganization deletes an organization (soft delete)
+func (uc *OrganizationUseCase) DeleteOrganization(ctx context.Context, id string) error {
+	organizationID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", entity.ErrInvalidOrganizationID, err)
+	}
+
+	if _, err := uc.requireOrganizationAdmin(ctx, organizationID); err != nil {
+		return err
+	}
+
+	if err := uc.organizationRepo.Delete(ctx, organizationID); err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+
+	return nil
+}
+
+// AddMember adds an existing user to an organization
+func (uc *OrganizationUseCase) AddMember(ctx context.Context, addDTO *dto.AddMemberDTO) (*dto.MembershipDTO, error) {
+	callerRole, err := uc.requireOrganizationAdmin(ctx, addDTO.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	membership := &entity.Membership{
+		OrganizationID: addDTO.OrganizationID,
+		UserID:         addDTO.UserID,
+		Role:           entity.MembershipRole(addDTO.Role),
+	}
+	if membership.Role.Rank() > callerRole.Rank() {
+		return nil, fmt.Errorf("%w: cannot grant the %s role", entity.ErrOrganizationAdminRequired, membership.Role)
+	}
+
+	if err := uc.organizationService.AddMember(ctx, membership); err != nil {
+		return nil, fmt.Errorf("failed to add member: %w", err)
+	}
+
+	return dto.FromMembershipEntity(membership), nil
+}
+
+// RemoveMember removes a user from an organization
+func (uc *OrganizationUseCase) RemoveMember(ctx context.Context, organizationID, userID uuid.UUID) error {
+	callerRole, err := uc.requireOrganizationAdmin(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	memberRole, err := uc.organizationService.MemberRole(ctx, organizationID, userID)
+	if err != nil {
+		return err
+	}
+	if memberRole.Rank() > callerRole.Rank() {
+		return fmt.Errorf("%w: cannot remove a member with the %s role", entity.ErrOrganizationAdminRequired, memberRole)
+	}
+
+	if err := uc.membershipRepo.Delete(ctx, organizationID, userID); err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+
+	return nil
+}
+
+// ListMembers lists the members of an organization with pagination
+func (uc *OrganizationUseCase) ListMembers(ctx context.Context, organizationID uuid.UUID, page, pageSize int) (*dto.ListMembersDTO, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	// Check if organization exists
+	if _, err := uc.organizationRepo.GetByID(ctx, organizationID); err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	memberships, err := uc.membershipRepo.ListByOrganization(ctx, organizationID, offset, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+
+	totalCount, err := uc.membershipRepo.CountByOrganization(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count members: %w", err)
+	}
+
+	membershipDTOs := make([]*dto.MembershipDTO, len(memberships))
+	for i, membership := range memberships {
+		membershipDTOs[i] = dto.FromMembershipEntity(membership)
+	}
+
+	return &dto.ListMembersDTO{
+		Memberships: membershipDTOs,
+		Page:        page,
+		PageSize:    pageSize,
+		TotalItems:  int(totalCount),
+		TotalPages:  int(math.Ceil(float64(totalCount) / float64(pageSize))),
+	}, nil
+}
+
+// ListUserOrganizations lists the organizations a user belongs to
+func (uc *OrganizationUseCase) ListUserOrganizations(ctx context.Context, userID uuid.UUID) (*dto.UserOrganizationsDTO, error) {
+	organizations, err := uc.organizationRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user organizations: %w", err)
+	}
+
+	memberships, err := uc.membershipRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user memberships: %w", err)
+	}
+
+	membershipsByOrganization := make(map[uuid.UUID]*entity.Membership, len(memberships))
+	for _, membership := range memberships {
+		membershipsByOrganization[membership.OrganizationID] = membership
+	}
+
+	// Deleted organizations are excluded, so align memberships with organizations
+	result := &dto.UserOrganizationsDTO{
+		Organizations: make([]*dto.OrganizationDTO, 0, len(organizations)),
+		Memberships:   make([]*dto.MembershipDTO, 0, len(organizations)),
+	}
+	for _, organization := range organizations {
+		membership, ok := membershipsByOrganization[organization.ID]
+		if !ok {
+			continue
+		}
+		result.Organizations = append(result.Organizations, dto.FromEntity(organization))
+		result.Memberships = append(result.Memberships, dto.FromMembershipEntity(membership))
+	}
+
+	return result, nil
+}
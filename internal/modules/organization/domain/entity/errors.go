@@ -0,0 +1,33 @@
+package entity
+
+import "errors"
+
+var (
+	// ErrOrganizationNotFound is returned when an organization is not found
+	ErrOrganizationNotFound = errors.New("organization not found")
+
+	// ErrOrganizationAlreadyExists is returned when an organization slug is already in use
+	ErrOrganizationAlreadyExists = errors.New("organization already exists")
+
+	// ErrInvalidOrganization is returned when organization data is invalid
+	ErrInvalidOrganization = errors.New("invalid organization")
+
+	// ErrInvalidOrganizationID is returned when an organization ID is invalid
+	ErrInvalidOrganizationID = errors.New("invalid organization ID")
+
+	// ErrMembershipNotFound is returned when a user is not a member of an organization
+	ErrMembershipNotFound = errors.New("membership not found")
+
+	// ErrMembershipAlreadyExists is returned when a user is already a member of an organization
+	ErrMembershipAlreadyExists = errors.New("membership already exists")
+
+	// ErrInvalidMembershipRole is returned when a membership role is invalid
+	ErrInvalidMembershipRole = errors.New("invalid membership role")
+
+	// ErrOrganizationAdminRequired is returned when a caller who is neither a platform
+	// administrator nor an owner or admin of an organization attempts to change it
+	ErrOrganizationAdminRequired = errors.New("organization administrator privileges required")
+
+	// ErrMemberUserNotFound is returned when adding a user that does not exist
+	ErrMemberUserNotFound = errors.New("user not found")
+)
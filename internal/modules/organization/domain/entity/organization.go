@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Organization represents a tenant hosted on the deployment
+type Organization struct {
+	ID                  uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name                string                 `gorm:"type:varchar(255);not null" json:"name"`
+	Slug                string                 `gorm:"type:varchar(100);uniqueIndex;not null" json:"slug"`
+	UserUniquenessScope tenant.UniquenessScope `gorm:"type:varchar(20);not null;default:'global'" json:"user_uniqueness_scope"`
+	CreatedAt           time.Time              `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time              `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt           gorm.DeletedAt         `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// TableName specifies the table name for Organization entity
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+// BeforeCreate hook to set UUID before creating
+func (o *Organization) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	return nil
+}
+
+var slugRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Validate validates the organization entity
+func (o *Organization) Validate() error {
+	o.Name = strings.TrimSpace(o.Name)
+	if o.Name == "" || len(o.Name) > 255 {
+		return fmt.Errorf("%w: name must be between 1 and 255 characters", ErrInvalidOrganization)
+	}
+	if len(o.Slug) > 100 || !slugRegex.MatchString(o.Slug) {
+		return fmt.Errorf("%w: slug must contain only lowercase letters, numbers, and hyphens", ErrInvalidOrganization)
+	}
+	if o.UserUniquenessScope == "" {
+		o.UserUniquenessScope = tenant.UniquenessGlobal
+	}
+	if !o.UserUniquenessScope.IsValid() {
+		return fmt.Errorf("%w: unsupported user uniqueness scope %q", ErrInvalidOrganization, o.UserUniquenessScope)
+	}
+	return nil
+}
+
+// MembershipRole represents a user's role within an organization
+type MembershipRole string
+
+const (
+	MembershipRoleMember MembershipRole = "member"
+	MembershipRoleAdmin  MembershipRole = "admin"
+	MembershipRoleOwner  MembershipRole = "owner"
+)
+
+// IsValid reports whether the membership role is supported
+func (r MembershipRole) IsValid() bool {
+	switch r {
+	case MembershipRoleMember, MembershipRoleAdmin, MembershipRoleOwner:
+		return true
+	}
+	return false
+}
+
+// Rank orders membership roles by privilege, higher meaning more privileged
+func (r MembershipRole) Rank() int {
+	switch r {
+	case MembershipRoleMember:
+		return 1
+	case MembershipRoleAdmin:
+		return 2
+	case MembershipRoleOwner:
+		return 3
+	}
+	return 0
+}
+
+// Membership links a user to an organization
+type Membership struct {
+	OrganizationID uuid.UUID      `gorm:"type:uuid;primaryKey" json:"organization_id"`
+	UserID         uuid.UUID      `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	Role           MembershipRole `gorm:"type:varchar(20);not null;default:'member'" json:"role"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for Membership entity
+func (Membership) TableName() string {
+	return tenant.MembershipTable
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/entity"
+	"github.com/google/uuid"
+)
+
+// OrganizationRepository defines the interface for organization data operations
+type OrganizationRepository interface {
+	// Create creates a new organization
+	Create(ctx context.Context, organization *entity.Organization) error
+
+	// GetByID retrieves an organization by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Organization, error)
+
+	// GetBySlug retrieves an organization by slug
+	GetBySlug(ctx context.Context, slug string) (*entity.Organization, error)
+
+	// Update updates an existing organization
+	Update(ctx context.Context, organization *entity.Organization) error
+
+	// Delete soft deletes an organization
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// List retrieves organizations with pagination
+	List(ctx context.Context, offset, limit int) ([]*entity.Organization, error)
+
+	// Count returns the total number of organizations
+	Count(ctx context.Context) (int64, error)
+
+	// ListByUser retrieves the organizations a user belongs to
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Organization, error)
+}
+
+// MembershipRepository defines the interface for organization membership data operations
+type MembershipRepository interface {
+	// Create adds a user to an organization
+	Create(ctx context.Context, membership *entity.Membership) error
+
+	// Get retrieves a membership
+	Get(ctx context.Context, organizationID, userID uuid.UUID) (*entity.Membership, error)
+
+	// UpdateRole changes a member's role within an organization
+	UpdateRole(ctx context.Context, organizationID, userID uuid.UUID, role entity.MembershipRole) error
+
+	// Delete removes a user from an organization
+	Delete(ctx context.Context, organizationID, userID uuid.UUID) error
+
+	// ListByOrganization retrieves memberships of an organization with pagination
+	ListByOrganization(ctx context.Context, organizationID uuid.UUID, offset, limit int) ([]*entity.Membership, error)
+
+	// CountByOrganization returns the number of members of an organization
+	CountByOrganization(ctx context.Context, organizationID uuid.UUID) (int64, error)
+
+	// ListByUser retrieves all memberships of a user
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Membership, error)
+}
+
+// UserDirectory is the view of the user module the organization module depends on
+type UserDirectory interface {
+	// Exists checks if a user exists by ID
+	Exists(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// Administrators is the view of the user module's authorization the organization module depends on
+type Administrators interface {
+	// IsAdmin reports whether the caller is an administrator. Organization requests
+	// carry no tenant, so this is a platform-wide administrator.
+	IsAdmin(ctx context.Context) (bool, error)
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+)
+
+// OrganizationService provides domain services for organization operations
+type OrganizationService struct {
+	organizationRepo repository.OrganizationRepository
+	membershipRepo   repository.MembershipRepository
+	userDirectory    repository.UserDirectory
+}
+
+// NewOrganizationService creates a new instance of OrganizationService
+func NewOrganizationService(organizationRepo repository.OrganizationRepository, membershipRepo repository.MembershipRepository, userDirectory repository.UserDirectory) *OrganizationService {
+	return &OrganizationService{
+		organizationRepo: organizationRepo,
+		membershipRepo:   membershipRepo,
+		userDirectory:    userDirectory,
+	}
+}
+
+// CreateOrganization validates and creates a new organization
+func (s *OrganizationService) CreateOrganization(ctx context.Context, organization *entity.Organization) error {
+	if err := organization.Validate(); err != nil {
+		return err
+	}
+
+	// Check if slug is already in use
+	existing, err := s.organizationRepo.GetBySlug(ctx, organization.Slug)
+	if err != nil && !errors.Is(err, entity.ErrOrganizationNotFound) {
+		return fmt.Errorf("failed to check organization existence: %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: slug already in use", entity.ErrOrganizationAlreadyExists)
+	}
+
+	if err := s.organizationRepo.Create(ctx, organization); err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateOrganization validates and saves changes to an organization
+func (s *OrganizationService) UpdateOrganization(ctx context.Context, organization *entity.Organization) error {
+	if err := organization.Validate(); err != nil {
+		return err
+	}
+
+	// Check if slug is taken by another organization
+	existing, err := s.organizationRepo.GetBySlug(ctx, organization.Slug)
+	if err != nil && !errors.Is(err, entity.ErrOrganizationNotFound) {
+		return fmt.Errorf("failed to check organization existence: %w", err)
+	}
+	if existing != nil && existing.ID != organization.ID {
+		return fmt.Errorf("%w: slug already in use", entity.ErrOrganizationAlreadyExists)
+	}
+
+	if err := s.organizationRepo.Update(ctx, organization); err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+
+	return nil
+}
+
+// AddMember adds an existing user to an organization
+func (s *OrganizationService) AddMember(ctx context.Context, membership *entity.Membership) error {
+	if membership.Role == "" {
+		membership.Role = entity.MembershipRoleMember
+	}
+	if !membership.Role.IsValid() {
+		return fmt.Errorf("%w: %s", entity.ErrInvalidMembershipRole, membership.Role)
+	}
+
+	// Check if organization exists
+	if _, err := s.organizationRepo.GetByID(ctx, membership.OrganizationID); err != nil {
+		return fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	// The user may belong to another tenant, so look it up across all tenants
+	userExists, err := s.userDirectory.Exists(tenant.Unscoped(ctx), membership.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to check user existence: %w", err)
+	}
+	if !userExists {
+		return entity.ErrMemberUserNotFound
+	}
+
+	// Check if user is already a member
+	existing, err := s.membershipRepo.Get(ctx, membership.OrganizationID, membership.UserID)
+	if err != nil && !errors.Is(err, entity.ErrMembershipNotFound) {
+		return fmt.Errorf("failed to check membership existence: %w", err)
+	}
+	if existing != nil {
+		return entity.ErrMembershipAlreadyExists
+	}
+
+	if err := s.membershipRepo.Create(ctx, membership); err != nil {
+		return fmt.Errorf("failed to add member: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateMemberRoles checks that an organization exists and that every role is a
+// membership role, so that they can later be granted with GrantMemberRoles
+func (s *OrganizationService) ValidateMemberRoles(ctx context.Context, organizationID uuid.UUID, roles []string) error {
+	for _, role := range roles {
+		if !entity.MembershipRole(role).IsValid() {
+			return fmt.Errorf("%w: %s", entity.ErrInvalidMembershipRole, role)
+		}
+	}
+
+	// Check if organization exists
+	if _, err := s.organizationRepo.GetByID(ctx, organizationID); err != nil {
+		return fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	return nil
+}
+
+// GrantMemberRoles gives an existing member the most privileged of the given roles.
+// A member holds a single role, so lower roles are implied by higher ones.
+func (s *OrganizationService) GrantMemberRoles(ctx context.Context, organizationID, userID uuid.UUID, roles []string) error {
+	role := entity.MembershipRoleMember
+	for _, candidate := range roles {
+		if entity.MembershipRole(candidate).Rank() > role.Rank() {
+			role = entity.MembershipRole(candidate)
+		}
+	}
+
+	membership, err := s.membershipRepo.Get(ctx, organizationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get membership: %w", err)
+	}
+	if membership.Role == role {
+		return nil
+	}
+
+	if err := s.membershipRepo.UpdateRole(ctx, organizationID, userID, role); err != nil {
+		return fmt.Errorf("failed to grant member role: %w", err)
+	}
+
+	return nil
+}
+
+// IsMember reports whether a user belongs to an organization
+func (s *OrganizationService) IsMember(ctx context.Context, organizationID, userID uuid.UUID) (bool, error) {
+	if _, err := s.membershipRepo.Get(ctx, organizationID, userID); err != nil {
+		if errors.Is(err, entity.ErrMembershipNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get membership: %w", err)
+	}
+	return true, nil
+}
+
+// MemberRole returns a user's role within an organization, or an empty role when
+// the user does not belong to it
+func (s *OrganizationService) MemberRole(ctx context.Context, organizationID, userID uuid.UUID) (entity.MembershipRole, error) {
+	membership, err := s.membershipRepo.Get(ctx, organizationID, userID)
+	if err != nil {
+		if errors.Is(err, entity.ErrMembershipNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get membership: %w", err)
+	}
+	return membership.Role, nil
+}
+
+// IsOrganizationAdmin reports whether a user is an owner or admin of an organization
+func (s *OrganizationService) IsOrganizationAdmin(ctx context.Context, organizationID, userID uuid.UUID) (bool, error) {
+	role, err := s.MemberRole(ctx, organizationID, userID)
+	if err != nil {
+		return false, err
+	}
+	return role.Rank() >= entity.MembershipRoleAdmin.Rank(), nil
+}
+
+// UniquenessScope returns where user identifiers must be unique for an organization
+func (s *OrganizationService) UniquenessScope(ctx context.Context, organizationID uuid.UUID) (tenant.UniquenessScope, error) {
+	organization, err := s.organizationRepo.GetByID(ctx, organizationID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get organization: %w", err)
+	}
+	return organization.UserUniquenessScope, nil
+}
@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/entity"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+)
+
+// errorRules maps the organization module's domain errors to application errors
+var errorRules = apperrors.Rules(
+	// Invalid arguments
+	apperrors.Rule{Err: entity.ErrInvalidOrganization, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_ORGANIZATION",
+		Translations: map[string]string{"ja": "組織が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidOrganizationID, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_ORGANIZATION_ID",
+		Translations: map[string]string{"ja": "組織IDが正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidMembershipRole, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_MEMBERSHIP_ROLE",
+		Translations: map[string]string{"ja": "メンバーのロールが正しくありません"}},
+
+	// Missing resources
+	apperrors.Rule{Err: entity.ErrOrganizationNotFound, Code: apperrors.CodeNotFound, Reason: "ORGANIZATION_NOT_FOUND",
+		Translations: map[string]string{"ja": "組織が見つかりません"}},
+	apperrors.Rule{Err: entity.ErrMembershipNotFound, Code: apperrors.CodeNotFound, Reason: "MEMBERSHIP_NOT_FOUND",
+		Translations: map[string]string{"ja": "組織のメンバーではありません"}},
+	apperrors.Rule{Err: entity.ErrMemberUserNotFound, Code: apperrors.CodeNotFound, Reason: "ORGANIZATION_MEMBER_USER_NOT_FOUND",
+		Translations: map[string]string{"ja": "追加するユーザーが見つかりません"}},
+
+	// Authorization
+	apperrors.Rule{Err: entity.ErrOrganizationAdminRequired, Code: apperrors.CodePermissionDenied, Reason: "ORGANIZATION_ADMIN_REQUIRED",
+		Translations: map[string]string{"ja": "組織の管理者権限が必要です"}},
+
+	// Conflicts with existing resources
+	apperrors.Rule{Err: entity.ErrOrganizationAlreadyExists, Code: apperrors.CodeAlreadyExists, Reason: "ORGANIZATION_ALREADY_EXISTS",
+		Translations: map[string]string{"ja": "組織は既に存在します"}},
+	apperrors.Rule{Err: entity.ErrMembershipAlreadyExists, Code: apperrors.CodeAlreadyExists, Reason: "MEMBERSHIP_ALREADY_EXISTS",
+		Translations: map[string]string{"ja": "ユーザーは既に組織のメンバーです"}},
+)
+
+// ErrorTranslator converts the organization module's domain errors to application errors
+func ErrorTranslator() apperrors.Translator {
+	return errorRules
+}
@@ -0,0 +1,275 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/application/mapper"
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/application/usecase"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+	commonpb "github.com/gigi434/sample-grpc-server/pkg/generated/common"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/organization"
+	"github.com/google/uuid"
+)
+
+// OrganizationServiceServer implements the OrganizationService gRPC server
+type OrganizationServiceServer struct {
+	pb.UnimplementedOrganizationServiceServer
+	organizationUseCase *usecase.OrganizationUseCase
+}
+
+// NewOrganizationServiceServer creates a new OrganizationServiceServer instance
+func NewOrganizationServiceServer(organizationUseCase *usecase.OrganizationUseCase) *OrganizationServiceServer {
+	return &OrganizationServiceServer{
+		organizationUseCase: organizationUseCase,
+	}
+}
+
+// CreateOrganization creates a new organization
+func (s *OrganizationServiceServer) CreateOrganization(ctx context.Context, req *pb.CreateOrganizationRequest) (*pb.CreateOrganizationResponse, error) {
+	// Validate request
+	if req.Name == "" {
+		return nil, apperrors.MissingField("name")
+	}
+	if req.Slug == "" {
+		return nil, apperrors.MissingField("slug")
+	}
+
+	// Create organization
+	organizationDTO, err := s.organizationUseCase.CreateOrganization(ctx, mapper.CreateOrganizationRequestToDTO(req))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateOrganizationResponse{
+		Organization: mapper.OrganizationDTOToProto(organizationDTO),
+	}, nil
+}
+
+// GetOrganization retrieves an organization by ID
+func (s *OrganizationServiceServer) GetOrganization(ctx context.Context, req *pb.GetOrganizationRequest) (*pb.GetOrganizationResponse, error) {
+	// Validate request
+	if req.Id == "" {
+		return nil, apperrors.MissingField("id")
+	}
+
+	// Get organization
+	organizationDTO, err := s.organizationUseCase.GetOrganization(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetOrganizationResponse{
+		Organization: mapper.OrganizationDTOToProto(organizationDTO),
+	}, nil
+}
+
+// ListOrganizations retrieves a list of organizations with pagination
+func (s *OrganizationServiceServer) ListOrganizations(ctx context.Context, req *pb.ListOrganizationsRequest) (*pb.ListOrganizationsResponse, error) {
+	page, pageSize := paginationParams(req.Pagination)
+
+	// List organizations
+	listDTO, err := s.organizationUseCase.ListOrganizations(ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to proto
+	organizations := make([]*pb.Organization, len(listDTO.Organizations))
+	for i, organizationDTO := range listDTO.Organizations {
+		organizations[i] = mapper.OrganizationDTOToProto(organizationDTO)
+	}
+
+	return &pb.ListOrganizationsResponse{
+		Organizations: organizations,
+		Pagination:    paginationResponse(listDTO.Page, listDTO.PageSize, listDTO.TotalItems, listDTO.TotalPages),
+	}, nil
+}
+
+// UpdateOrganization updates an existing organization
+func (s *OrganizationServiceServer) UpdateOrganization(ctx context.Context, req *pb.UpdateOrganizationRequest) (*pb.UpdateOrganizationResponse, error) {
+	// Validate request
+	if req.Id == "" {
+		return nil, apperrors.MissingField("id")
+	}
+
+	// Convert request to DTO
+	updateDTO, err := mapper.UpdateOrganizationRequestToDTO(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update organization
+	organizationDTO, err := s.organizationUseCase.UpdateOrganization(ctx, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateOrganizationResponse{
+		Organization: mapper.OrganizationDTOToProto(organizationDTO),
+	}, nil
+}
+
+// DeleteOrganization deletes an organization
+func (s *OrganizationServiceServer) DeleteOrganization(ctx context.Context, req *pb.DeleteOrganizationRequest) (*pb.DeleteOrganizationResponse, error) {
+	// Validate request
+	if req.Id == "" {
+		return nil, apperrors.MissingField("id")
+	}
+
+	// Delete organization
+	if err := s.organizationUseCase.DeleteOrganization(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteOrganizationResponse{
+		Success: true,
+		Message: "Organization deleted successfully",
+	}, nil
+}
+
+// AddMember adds an existing user to an organization
+func (s *OrganizationServiceServer) AddMember(ctx context.Context, req *pb.AddMemberRequest) (*pb.AddMemberResponse, error) {
+	// Validate request
+	organizationID, userID, err := parseMembershipIDs(req.OrganizationId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add member
+	membershipDTO, err := s.organizationUseCase.AddMember(ctx, &dto.AddMemberDTO{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Role:           mapper.MembershipRoleFromProto(req.Role),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AddMemberResponse{
+		Membership: mapper.MembershipDTOToProto(membershipDTO),
+	}, nil
+}
+
+// RemoveMember removes a user from an organization
+func (s *OrganizationServiceServer) RemoveMember(ctx context.Context, req *pb.RemoveMemberRequest) (*pb.RemoveMemberResponse, error) {
+	// Validate request
+	organizationID, userID, err := parseMembershipIDs(req.OrganizationId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove member
+	if err := s.organizationUseCase.RemoveMember(ctx, organizationID, userID); err != nil {
+		return nil, err
+	}
+
+	return &pb.RemoveMemberResponse{
+		Success: true,
+		Message: "Member removed successfully",
+	}, nil
+}
+
+// ListMembers lists the members of an organization
+func (s *OrganizationServiceServer) ListMembers(ctx context.Context, req *pb.ListMembersRequest) (*pb.ListMembersResponse, error) {
+	// Validate request
+	organizationID, err := uuid.Parse(req.OrganizationId)
+	if err != nil {
+		return nil, apperrors.InvalidField("organization_id", "must be a UUID")
+	}
+
+	page, pageSize := paginationParams(req.Pagination)
+
+	// List members
+	listDTO, err := s.organizationUseCase.ListMembers(ctx, organizationID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to proto
+	memberships := make([]*pb.Membership, len(listDTO.Memberships))
+	for i, membershipDTO := range listDTO.Memberships {
+		memberships[i] = mapper.MembershipDTOToProto(membershipDTO)
+	}
+
+	return &pb.ListMembersResponse{
+		Memberships: memberships,
+		Pagination:  paginationResponse(listDTO.Page, listDTO.PageSize, listDTO.TotalItems, listDTO.TotalPages),
+	}, nil
+}
+
+// ListUserOrganizations lists the organizations a user belongs to
+func (s *OrganizationServiceServer) ListUserOrganizations(ctx context.Context, req *pb.ListUserOrganizationsRequest) (*pb.ListUserOrganizationsResponse, error) {
+	// Validate request
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, apperrors.InvalidField("user_id", "must be a UUID")
+	}
+
+	// List organizations
+	resultDTO, err := s.organizationUseCase.ListUserOrganizations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to proto
+	response := &pb.ListUserOrganizationsResponse{
+		Organizations: make([]*pb.Organization, len(resultDTO.Organizations)),
+		Memberships:   make([]*pb.Membership, len(resultDTO.Memberships)),
+	}
+	for i, organizationDTO := range resultDTO.Organizations {
+		response.Organizations[i] = mapper.OrganizationDTOToProto(organizationDTO)
+	}
+	for i, membershipDTO := range resultDTO.Memberships {
+		response.Memberships[i] = mapper.MembershipDTOToProto(membershipDTO)
+	}
+
+	return response, nil
+}
+
+// parseMembershipIDs parses and validates organization and user IDs
+func parseMembershipIDs(organizationID, userID string) (uuid.UUID, uuid.UUID, error) {
+	parsedOrganizationID, err := uuid.Parse(organizationID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperrors.InvalidField("organization_id", "must be a UUID")
+	}
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperrors.InvalidField("user_id", "must be a UUID")
+	}
+	return parsedOrganizationID, parsedUserID, nil
+}
+
+// paginationParams extracts page and page size with defaults and limits
+func paginationParams(pagination *commonpb.PaginationRequest) (int, int) {
+	// Default pagination
+	page := 1
+	pageSize := 10
+
+	if pagination != nil {
+		if pagination.Page > 0 {
+			page = int(pagination.Page)
+		}
+		if pagination.PageSize > 0 {
+			pageSize = int(pagination.PageSize)
+		}
+		// Limit page size to prevent abuse
+		if pageSize > 100 {
+			pageSize = 100
+		}
+	}
+
+	return page, pageSize
+}
+
+// paginationResponse builds pagination metadata for list responses
+func paginationResponse(page, pageSize, totalItems, totalPages int) *commonpb.PaginationResponse {
+	return &commonpb.PaginationResponse{
+		Page:        int32(page),
+		PageSize:    int32(pageSize),
+		TotalItems:  int32(totalItems),
+		TotalPages:  int32(totalPages),
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}
+}
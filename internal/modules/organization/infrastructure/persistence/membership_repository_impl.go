@@ -0,0 +1,151 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// membershipRepository implements repository.MembershipRepository
+type membershipRepository struct {
+	// We don't store the DB connection here, we get it from config singleton
+}
+
+// NewMembershipRepository creates a new instance of MembershipRepository
+func NewMembershipRepository() repository.MembershipRepository {
+	return &membershipRepository{}
+}
+
+// getDB gets the database connection, joining the transaction of the context if any
+func (r *membershipRepository) getDB(ctx context.Context) (*gorm.DB, error) {
+	return database.Conn(ctx)
+}
+
+// Create adds a user to an organization
+func (r *membershipRepository) Create(ctx context.Context, membership *entity.Membership) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	if err := db.WithContext(ctx).Create(membership).Error; err != nil {
+		return fmt.Errorf("failed to create membership: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves a membership
+func (r *membershipRepository) Get(ctx context.Context, organizationID, userID uuid.UUID) (*entity.Membership, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var membership entity.Membership
+	if err := db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&membership).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrMembershipNotFound
+		}
+		return nil, fmt.Errorf("failed to get membership: %w", err)
+	}
+	return &membership, nil
+}
+
+// UpdateRole changes a member's role within an organization
+func (r *membershipRepository) UpdateRole(ctx context.Context, organizationID, userID uuid.UUID, role entity.MembershipRole) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	result := db.WithContext(ctx).
+		Model(&entity.Membership{}).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Update("role", role)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update membership role: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrMembershipNotFound
+	}
+	return nil
+}
+
+// Delete removes a user from an organization
+func (r *membershipRepository) Delete(ctx context.Context, organizationID, userID uuid.UUID) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	result := db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Delete(&entity.Membership{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete membership: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrMembershipNotFound
+	}
+	return nil
+}
+
+// ListByOrganization retrieves memberships of an organization with pagination
+func (r *membershipRepository) ListByOrganization(ctx context.Context, organizationID uuid.UUID, offset, limit int) ([]*entity.Membership, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var memberships []*entity.Membership
+	if err := db.WithContext(ctx).
+		Where("organization_id = ?", organizationID).
+		Order("created_at ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&memberships).Error; err != nil {
+		return nil, fmt.Errorf("failed to list memberships: %w", err)
+	}
+	return memberships, nil
+}
+
+// CountByOrganization returns the number of members of an organization
+func (r *membershipRepository) CountByOrganization(ctx context.Context, organizationID uuid.UUID) (int64, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&entity.Membership{}).
+		Where("organization_id = ?", organizationID).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count memberships: %w", err)
+	}
+	return count, nil
+}
+
+// ListByUser retrieves all memberships of a user
+func (r *membershipRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Membership, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var memberships []*entity.Membership
+	if err := db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Find(&memberships).Error; err != nil {
+		return nil, fmt.Errorf("failed to list memberships by user: %w", err)
+	}
+	return memberships, nil
+}
@@ -0,0 +1,155 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/database"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// organizationRepository implements repository.OrganizationRepository
+type organizationRepository struct {
+	// We don't store the DB connection here, we get it from config singleton
+}
+
+// NewOrganizationRepository creates a new instance of OrganizationRepository
+func NewOrganizationRepository() repository.OrganizationRepository {
+	return &organizationRepository{}
+}
+
+// getDB gets the database connection, joining the transaction of the context if any
+func (r *organizationRepository) getDB(ctx context.Context) (*gorm.DB, error) {
+	return database.Conn(ctx)
+}
+
+// Create creates a new organization
+func (r *organizationRepository) Create(ctx context.Context, organization *entity.Organization) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	if err := db.WithContext(ctx).Create(organization).Error; err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an organization by ID
+func (r *organizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Organization, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var organization entity.Organization
+	if err := db.WithContext(ctx).First(&organization, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization by ID: %w", err)
+	}
+	return &organization, nil
+}
+
+// GetBySlug retrieves an organization by slug
+func (r *organizationRepository) GetBySlug(ctx context.Context, slug string) (*entity.Organization, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var organization entity.Organization
+	if err := db.WithContext(ctx).Where("slug = ?", slug).First(&organization).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization by slug: %w", err)
+	}
+	return &organization, nil
+}
+
+// Update updates an existing organization
+func (r *organizationRepository) Update(ctx context.Context, organization *entity.Organization) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	if err := db.WithContext(ctx).Save(organization).Error; err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+	return nil
+}
+
+// Delete soft deletes an organization
+func (r *organizationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	result := db.WithContext(ctx).Delete(&entity.Organization{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete organization: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrOrganizationNotFound
+	}
+	return nil
+}
+
+// List retrieves organizations with pagination
+func (r *organizationRepository) List(ctx context.Context, offset, limit int) ([]*entity.Organization, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var organizations []*entity.Organization
+	if err := db.WithContext(ctx).
+		Order("name ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&organizations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	return organizations, nil
+}
+
+// Count returns the total number of organizations
+func (r *organizationRepository) Count(ctx context.Context) (int64, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var count int64
+	if err := db.WithContext(ctx).Model(&entity.Organization{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count organizations: %w", err)
+	}
+	return count, nil
+}
+
+// ListByUser retrieves the organizations a user belongs to
+func (r *organizationRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Organization, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var organizations []*entity.Organization
+	if err := db.WithContext(ctx).
+		Where("id IN (SELECT organization_id FROM "+tenant.MembershipTable+" WHERE user_id = ?)", userID).
+		Order("name ASC").
+		Find(&organizations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list organizations by user: %w", err)
+	}
+	return organizations, nil
+}
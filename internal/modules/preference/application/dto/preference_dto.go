@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/domain/entity"
+	"github.com/google/uuid"
+)
+
+// PreferencesDTO represents the data transfer object for a user's preferences
+type PreferencesDTO struct {
+	UserID uuid.UUID
+	Values map[string]interface{}
+
+	// UpdatedAt is nil when the user never changed a preference
+	UpdatedAt *time.Time
+}
+
+// UpdatePreferencesDTO represents the data transfer object for updating preferences
+type UpdatePreferencesDTO struct {
+	UserID string
+
+	// Keys lists the preferences to update; a listed key without a value is reset.
+	// When empty, every key present in Values is updated.
+	Keys []string
+
+	Values map[string]interface{}
+}
+
+// ResetPreferencesDTO represents the data transfer object for resetting preferences
+type ResetPreferencesDTO struct {
+	UserID string
+
+	// Keys lists the preferences to reset; all preferences are reset when empty
+	Keys []string
+}
+
+// FromEntity creates a PreferencesDTO from Preferences entity
+func FromEntity(preferences *entity.Preferences) *PreferencesDTO {
+	dto := &PreferencesDTO{
+		UserID: preferences.UserID,
+		Values: preferences.Values,
+	}
+	if !preferences.UpdatedAt.IsZero() {
+		dto.UpdatedAt = &preferences.UpdatedAt
+	}
+	return dto
+}
@@ -0,0 +1,61 @@
+package mapper
+
+import (
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/application/dto"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/preference"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// PreferencesDTOToProto converts a PreferencesDTO to proto message
+func PreferencesDTOToProto(dto *dto.PreferencesDTO) *pb.Preferences {
+	if dto == nil {
+		return nil
+	}
+
+	values, err := structpb.NewStruct(dto.Values)
+	if err != nil {
+		// Values are normalized against the registry, so this only happens for a bad definition
+		values = &structpb.Struct{}
+	}
+
+	preferences := &pb.Preferences{
+		UserId: dto.UserID.String(),
+		Values: values,
+	}
+	if dto.UpdatedAt != nil {
+		preferences.UpdatedAt = timestamppb.New(*dto.UpdatedAt)
+	}
+	return preferences
+}
+
+// UpdatePreferencesRequestToDTO converts UpdatePreferencesRequest to UpdatePreferencesDTO
+func UpdatePreferencesRequestToDTO(req *pb.UpdatePreferencesRequest) *dto.UpdatePreferencesDTO {
+	if req == nil {
+		return nil
+	}
+
+	updateDTO := &dto.UpdatePreferencesDTO{
+		UserID: req.UserId,
+		Values: map[string]interface{}{},
+	}
+	if req.UpdateMask != nil {
+		updateDTO.Keys = req.UpdateMask.Paths
+	}
+	if req.Values != nil {
+		updateDTO.Values = req.Values.AsMap()
+	}
+	return updateDTO
+}
+
+// ResetPreferencesRequestToDTO converts ResetPreferencesRequest to ResetPreferencesDTO
+func ResetPreferencesRequestToDTO(req *pb.ResetPreferencesRequest) *dto.ResetPreferencesDTO {
+	if req == nil {
+		return nil
+	}
+
+	return &dto.ResetPreferencesDTO{
+		UserID: req.UserId,
+		Keys:   req.Keys,
+	}
+}
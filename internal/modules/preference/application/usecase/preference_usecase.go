@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/domain/service"
+	"github.com/gigi434/sample-grpc-server/internal/shared/auth"
+	"github.com/google/uuid"
+)
+
+// PreferenceUseCase handles preference-related business logic
+type PreferenceUseCase struct {
+	preferenceService *service.PreferenceService
+	administrators    repository.Administrators
+}
+
+// NewPreferenceUseCase creates a new instance of PreferenceUseCase
+func NewPreferenceUseCase(preferenceService *service.PreferenceService, administrators repository.Administrators) *PreferenceUseCase {
+	return &PreferenceUseCase{
+		preferenceService: preferenceService,
+		administrators:    administrators,
+	}
+}
+
+// GetPreferences retrieves all preferences of a user
+func (uc *PreferenceUseCase) GetPreferences(ctx context.Context, id string) (*dto.PreferencesDTO, error) {
+	userID, err := parseUserID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.requireSelfOrAdmin(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	preferences, err := uc.preferenceService.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromEntity(preferences), nil
+}
+
+// UpdatePreferences changes preferences of a user
+func (uc *PreferenceUseCase) UpdatePreferences(ctx context.Context, updateDTO *dto.UpdatePreferencesDTO) (*dto.PreferencesDTO, error) {
+	userID, err := parseUserID(updateDTO.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.requireSelfOrAdmin(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// Without a mask, update every key present in the request
+	keys := updateDTO.Keys
+	if len(keys) == 0 {
+		for key := range updateDTO.Values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	} else {
+		keys, err = uc.preferenceService.Registry().ParseUpdateMask(keys)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	preferences, err := uc.preferenceService.UpdatePreferences(ctx, userID, keys, entity.Values(updateDTO.Values))
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromEntity(preferences), nil
+}
+
+// ResetPreferences restores preferences of a user to their defaults
+func (uc *PreferenceUseCase) ResetPreferences(ctx context.Context, resetDTO *dto.ResetPreferencesDTO) (*dto.PreferencesDTO, error) {
+	userID, err := parseUserID(resetDTO.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.requireSelfOrAdmin(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	preferences, err := uc.preferenceService.ResetPreferences(ctx, userID, resetDTO.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromEntity(preferences), nil
+}
+
+// requireSelfOrAdmin returns ErrAdminRequired unless the caller is the user whose
+// preferences are accessed or an administrator
+func (uc *PreferenceUseCase) requireSelfOrAdmin(ctx context.Context, userID uuid.UUID) error {
+	if claims, ok := auth.ClaimsFromContext(ctx); ok && claims.UserID == userID {
+		return nil
+	}
+
+	isAdmin, err := uc.administrators.IsAdmin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check caller: %w", err)
+	}
+	if !isAdmin {
+		return entity.ErrAdminRequired
+	}
+	return nil
+}
+
+// parseUserID parses a user ID from a request
+func parseUserID(id string) (uuid.UUID, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %v", entity.ErrInvalidUserID, err)
+	}
+	return userID, nil
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "errors"
+
+var (
+	// ErrUnknownPreference is returned when a preference key is not declared in the registry
+	ErrUnknownPreference = errors.New("unknown preference")
+
+	// ErrInvalidPreference is returned when a preference value does not match its definition
+	ErrInvalidPreference = errors.New("invalid preference value")
+
+	// ErrInvalidUpdateMask is returned when an update mask names an unknown preference
+	ErrInvalidUpdateMask = errors.New("invalid update mask")
+
+	// ErrInvalidUserID is returned when a user ID is invalid
+	ErrInvalidUserID = errors.New("invalid user ID")
+
+	// ErrAdminRequired is returned when a caller who is not an administrator
+	// accesses the preferences of another user
+	ErrAdminRequired = errors.New("administrator privileges required")
+
+	// ErrUserNotFound is returned when the preferences' user does not exist
+	ErrUserNotFound = errors.New("user not found")
+)
@@ -0,0 +1,230 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PreferenceType represents the value type of a preference
+type PreferenceType string
+
+const (
+	PreferenceTypeString  PreferenceType = "string"
+	PreferenceTypeInteger PreferenceType = "integer"
+	PreferenceTypeBoolean PreferenceType = "boolean"
+)
+
+// Definition declares a preference, its type, default value and constraints
+type Definition struct {
+	Key     string
+	Type    PreferenceType
+	Default interface{}
+
+	// AllowedValues restricts string preferences to a fixed set of values
+	AllowedValues []string
+
+	// Min and Max bound integer preferences
+	Min int64
+	Max int64
+
+	// Check validates string values beyond their type, e.g. time zone names
+	Check func(value string) error
+}
+
+// Normalize checks a value against the definition and returns it in canonical form.
+// Integers arrive as float64 from JSON and protobuf and are returned as int64.
+func (d *Definition) Normalize(value interface{}) (interface{}, error) {
+	switch d.Type {
+	case PreferenceTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s must be a string", ErrInvalidPreference, d.Key)
+		}
+		if len(d.AllowedValues) > 0 && !contains(d.AllowedValues, s) {
+			return nil, fmt.Errorf("%w: %s must be one of %v", ErrInvalidPreference, d.Key, d.AllowedValues)
+		}
+		if d.Check != nil {
+			if err := d.Check(s); err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidPreference, d.Key, err)
+			}
+		}
+		return s, nil
+	case PreferenceTypeInteger:
+		var n int64
+		switch v := value.(type) {
+		case float64:
+			if v != math.Trunc(v) {
+				return nil, fmt.Errorf("%w: %s must be an integer", ErrInvalidPreference, d.Key)
+			}
+			n = int64(v)
+		case int64:
+			n = v
+		case int:
+			n = int64(v)
+		default:
+			return nil, fmt.Errorf("%w: %s must be an integer", ErrInvalidPreference, d.Key)
+		}
+		if n < d.Min || n > d.Max {
+			return nil, fmt.Errorf("%w: %s must be between %d and %d", ErrInvalidPreference, d.Key, d.Min, d.Max)
+		}
+		return n, nil
+	case PreferenceTypeBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s must be a boolean", ErrInvalidPreference, d.Key)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("%w: %s has unsupported type %q", ErrInvalidPreference, d.Key, d.Type)
+	}
+}
+
+// Registry holds the preference definitions in declaration order
+type Registry struct {
+	definitions []*Definition
+	byKey       map[string]*Definition
+}
+
+// NewRegistry creates a registry from preference definitions.
+// It panics on duplicate keys or invalid defaults, which are programming errors.
+func NewRegistry(definitions ...*Definition) *Registry {
+	registry := &Registry{byKey: make(map[string]*Definition, len(definitions))}
+	for _, definition := range definitions {
+		if _, exists := registry.byKey[definition.Key]; exists {
+			panic(fmt.Sprintf("duplicate preference %q", definition.Key))
+		}
+		if _, err := definition.Normalize(definition.Default); err != nil {
+			panic(fmt.Sprintf("invalid default for preference %q: %v", definition.Key, err))
+		}
+		registry.definitions = append(registry.definitions, definition)
+		registry.byKey[definition.Key] = definition
+	}
+	return registry
+}
+
+// Lookup returns the definition of a preference key
+func (r *Registry) Lookup(key string) (*Definition, error) {
+	definition, ok := r.byKey[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPreference, key)
+	}
+	return definition, nil
+}
+
+// Keys returns every preference key in declaration order
+func (r *Registry) Keys() []string {
+	keys := make([]string, len(r.definitions))
+	for i, definition := range r.definitions {
+		keys[i] = definition.Key
+	}
+	return keys
+}
+
+// UpdateMaskWildcard selects every preference
+const UpdateMaskWildcard = "*"
+
+// ParseUpdateMask resolves update mask paths to preference keys.
+// The wildcard expands to all preferences; unknown keys are rejected.
+func (r *Registry) ParseUpdateMask(paths []string) ([]string, error) {
+	seen := make(map[string]bool, len(paths))
+	keys := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if path == UpdateMaskWildcard {
+			if len(paths) > 1 {
+				return nil, fmt.Errorf("%w: %q cannot be combined with other paths", ErrInvalidUpdateMask, UpdateMaskWildcard)
+			}
+			return r.Keys(), nil
+		}
+
+		if _, ok := r.byKey[path]; !ok {
+			return nil, fmt.Errorf("%w: unknown preference %q", ErrInvalidUpdateMask, path)
+		}
+		if !seen[path] {
+			seen[path] = true
+			keys = append(keys, path)
+		}
+	}
+	return keys, nil
+}
+
+// Resolve returns a value for every declared preference, using the stored value
+// when it is still valid and the default otherwise. Stored keys that are no
+// longer declared are ignored.
+func (r *Registry) Resolve(stored Values) Values {
+	resolved := make(Values, len(r.definitions))
+	for _, definition := range r.definitions {
+		resolved[definition.Key], _ = definition.Normalize(definition.Default)
+		if value, ok := stored[definition.Key]; ok {
+			if normalized, err := definition.Normalize(value); err == nil {
+				resolved[definition.Key] = normalized
+			}
+		}
+	}
+	return resolved
+}
+
+// Preferences holds the preference values a user has changed from their defaults
+type Preferences struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primary_key" json:"user_id"`
+	Values    Values    `gorm:"column:preference_values;type:jsonb;not null;default:'{}'" json:"values"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for Preferences entity
+func (Preferences) TableName() string {
+	return "user_preferences"
+}
+
+// Values holds preference values by key, stored as JSONB
+type Values map[string]interface{}
+
+// Value implements driver.Valuer
+func (v Values) Value() (driver.Value, error) {
+	if v == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (v *Values) Scan(value interface{}) error {
+	var data []byte
+	switch src := value.(type) {
+	case nil:
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("unsupported JSON value type %T", value)
+	}
+
+	result := Values{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &result); err != nil {
+			return err
+		}
+	}
+	*v = result
+	return nil
+}
+
+// contains reports whether values contains s
+func contains(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
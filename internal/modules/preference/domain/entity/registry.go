@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/language"
+)
+
+// Preference keys
+const (
+	PreferenceEmailNotifications    = "email_notifications"
+	PreferencePushNotifications     = "push_notifications"
+	PreferenceNotificationDigest    = "notification_digest"
+	PreferenceTheme                 = "theme"
+	PreferenceLanguage              = "language"
+	PreferenceTimeZone              = "time_zone"
+	PreferencePageSize              = "page_size"
+	PreferenceDefaultOrganizationID = "default_organization_id"
+)
+
+// DefaultRegistry declares every user preference and its default value.
+// Add new preferences here; clients receive the default until a user changes it.
+var DefaultRegistry = NewRegistry(
+	&Definition{
+		Key:     PreferenceEmailNotifications,
+		Type:    PreferenceTypeBoolean,
+		Default: true,
+	},
+	&Definition{
+		Key:     PreferencePushNotifications,
+		Type:    PreferenceTypeBoolean,
+		Default: true,
+	},
+	&Definition{
+		Key:           PreferenceNotificationDigest,
+		Type:          PreferenceTypeString,
+		Default:       "daily",
+		AllowedValues: []string{"off", "daily", "weekly"},
+	},
+	&Definition{
+		Key:           PreferenceTheme,
+		Type:          PreferenceTypeString,
+		Default:       "system",
+		AllowedValues: []string{"system", "light", "dark"},
+	},
+	&Definition{
+		// An empty language follows the user's locale
+		Key:     PreferenceLanguage,
+		Type:    PreferenceTypeString,
+		Default: "",
+		Check:   checkLanguage,
+	},
+	&Definition{
+		Key:     PreferenceTimeZone,
+		Type:    PreferenceTypeString,
+		Default: "UTC",
+		Check:   checkTimeZone,
+	},
+	&Definition{
+		Key:     PreferencePageSize,
+		Type:    PreferenceTypeInteger,
+		Default: int64(20),
+		Min:     10,
+		Max:     100,
+	},
+	&Definition{
+		// An empty organization ID means no default organization
+		Key:     PreferenceDefaultOrganizationID,
+		Type:    PreferenceTypeString,
+		Default: "",
+		Check:   checkOptionalUUID,
+	},
+)
+
+// checkLanguage accepts an empty value or a BCP 47 language tag
+func checkLanguage(value string) error {
+	if value == "" {
+		return nil
+	}
+	_, err := language.Parse(value)
+	return err
+}
+
+// checkTimeZone accepts IANA time zone names such as "Asia/Tokyo"
+func checkTimeZone(value string) error {
+	_, err := time.LoadLocation(value)
+	return err
+}
+
+// checkOptionalUUID accepts an empty value or a UUID
+func checkOptionalUUID(value string) error {
+	if value == "" {
+		return nil
+	}
+	_, err := uuid.Parse(value)
+	return err
+}
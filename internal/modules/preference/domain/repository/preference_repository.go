@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/domain/entity"
+	"github.com/google/uuid"
+)
+
+// PreferenceRepository defines the interface for preference data operations
+type PreferenceRepository interface {
+	// Get retrieves a user's stored preferences, returning nil if the user has none
+	Get(ctx context.Context, userID uuid.UUID) (*entity.Preferences, error)
+
+	// Merge sets and removes stored values of a user in a single statement, leaving
+	// other values as they are, and returns the resulting preferences. Concurrent
+	// merges of different keys do not overwrite each other.
+	Merge(ctx context.Context, userID uuid.UUID, set entity.Values, remove []string) (*entity.Preferences, error)
+
+	// Delete removes a user's stored preferences
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
+
+// Administrators is the view of the user module's authorization the preference module depends on
+type Administrators interface {
+	// IsAdmin reports whether the caller is an administrator of the current tenant
+	IsAdmin(ctx context.Context) (bool, error)
+}
+
+// UserDirectory is the view of the user module the preference module depends on
+type UserDirectory interface {
+	// Exists checks if a user exists by ID
+	Exists(ctx context.Context, id uuid.UUID) (bool, error)
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/domain/repository"
+	"github.com/google/uuid"
+)
+
+// PreferenceService provides domain services for user preferences.
+// Only values that differ from their defaults are stored, so changing a default
+// in the registry applies to every user who has not chosen a value.
+type PreferenceService struct {
+	preferenceRepo repository.PreferenceRepository
+	userDirectory  repository.UserDirectory
+	registry       *entity.Registry
+}
+
+// NewPreferenceService creates a new instance of PreferenceService
+func NewPreferenceService(preferenceRepo repository.PreferenceRepository, userDirectory repository.UserDirectory, registry *entity.Registry) *PreferenceService {
+	return &PreferenceService{
+		preferenceRepo: preferenceRepo,
+		userDirectory:  userDirectory,
+		registry:       registry,
+	}
+}
+
+// Registry returns the preference definitions the service validates against
+func (s *PreferenceService) Registry() *entity.Registry {
+	return s.registry
+}
+
+// GetPreferences returns every preference of a user, filling in defaults
+func (s *PreferenceService) GetPreferences(ctx context.Context, userID uuid.UUID) (*entity.Preferences, error) {
+	stored, err := s.storedPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolved(stored), nil
+}
+
+// UpdatePreferences sets the listed preferences to the given values. A listed key
+// without a value is reset to its default. It returns every preference of the user.
+func (s *PreferenceService) UpdatePreferences(ctx context.Context, userID uuid.UUID, keys []string, values entity.Values) (*entity.Preferences, error) {
+	if err := s.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// Only the listed keys are written, so that concurrent updates of other keys are kept
+	set := entity.Values{}
+	var remove []string
+	for _, key := range keys {
+		definition, err := s.registry.Lookup(key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, ok := values[key]
+		if !ok || value == nil {
+			remove = append(remove, key)
+			continue
+		}
+
+		normalized, err := definition.Normalize(value)
+		if err != nil {
+			return nil, err
+		}
+		defaultValue, _ := definition.Normalize(definition.Default)
+		if reflect.DeepEqual(normalized, defaultValue) {
+			remove = append(remove, key)
+		} else {
+			set[key] = normalized
+		}
+	}
+
+	stored, err := s.preferenceRepo.Merge(ctx, userID, set, remove)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update preferences: %w", err)
+	}
+
+	return s.resolved(stored), nil
+}
+
+// ResetPreferences resets the given preferences, or all of them when no keys are
+// given, to their defaults. It returns every preference of the user.
+func (s *PreferenceService) ResetPreferences(ctx context.Context, userID uuid.UUID, keys []string) (*entity.Preferences, error) {
+	if err := s.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		if err := s.preferenceRepo.Delete(ctx, userID); err != nil {
+			return nil, fmt.Errorf("failed to reset preferences: %w", err)
+		}
+		return s.resolved(&entity.Preferences{UserID: userID}), nil
+	}
+
+	for _, key := range keys {
+		if _, err := s.registry.Lookup(key); err != nil {
+			return nil, err
+		}
+	}
+
+	stored, err := s.preferenceRepo.Merge(ctx, userID, entity.Values{}, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset preferences: %w", err)
+	}
+
+	return s.resolved(stored), nil
+}
+
+// checkUser returns ErrUserNotFound unless the user exists
+func (s *PreferenceService) checkUser(ctx context.Context, userID uuid.UUID) error {
+	exists, err := s.userDirectory.Exists(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check user existence: %w", err)
+	}
+	if !exists {
+		return entity.ErrUserNotFound
+	}
+	return nil
+}
+
+// storedPreferences checks that the user exists and returns the values they have
+// changed, which are empty for a user who never changed a preference
+func (s *PreferenceService) storedPreferences(ctx context.Context, userID uuid.UUID) (*entity.Preferences, error) {
+	if err := s.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.preferenceRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+	if stored == nil {
+		stored = &entity.Preferences{UserID: userID}
+	}
+	if stored.Values == nil {
+		stored.Values = entity.Values{}
+	}
+	return stored, nil
+}
+
+// resolved returns a copy of the stored preferences with defaults filled in
+func (s *PreferenceService) resolved(stored *entity.Preferences) *entity.Preferences {
+	return &entity.Preferences{
+		UserID:    stored.UserID,
+		Values:    s.registry.Resolve(stored.Values),
+		CreatedAt: stored.CreatedAt,
+		UpdatedAt: stored.UpdatedAt,
+	}
+}
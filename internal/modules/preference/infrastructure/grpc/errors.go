@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/domain/entity"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+)
+
+// errorRules maps the preference module's domain errors to application errors
+var errorRules = apperrors.Rules(
+	// Invalid arguments
+	apperrors.Rule{Err: entity.ErrInvalidUserID, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_USER_ID",
+		Translations: map[string]string{"ja": "ユーザーIDが正しくありません"}},
+	apperrors.Rule{Err: entity.ErrUnknownPreference, Code: apperrors.CodeInvalidArgument, Reason: "UNKNOWN_PREFERENCE",
+		Translations: map[string]string{"ja": "存在しない設定項目です"}},
+	apperrors.Rule{Err: entity.ErrInvalidPreference, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_PREFERENCE",
+		Translations: map[string]string{"ja": "設定値が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidUpdateMask, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_UPDATE_MASK",
+		Translations: map[string]string{"ja": "更新マスクが正しくありません"}},
+
+	// Authorization
+	apperrors.Rule{Err: entity.ErrAdminRequired, Code: apperrors.CodePermissionDenied, Reason: "ADMIN_REQUIRED",
+		Translations: map[string]string{"ja": "管理者権限が必要です"}},
+
+	// Missing resources
+	apperrors.Rule{Err: entity.ErrUserNotFound, Code: apperrors.CodeNotFound, Reason: "USER_NOT_FOUND",
+		Translations: map[string]string{"ja": "ユーザーが見つかりません"}},
+)
+
+// ErrorTranslator converts the preference module's domain errors to application errors
+func ErrorTranslator() apperrors.Translator {
+	return errorRules
+}
@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/application/mapper"
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/application/usecase"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/preference"
+)
+
+// PreferenceServiceServer implements the PreferenceService gRPC server
+type PreferenceServiceServer struct {
+	pb.UnimplementedPreferenceServiceServer
+	preferenceUseCase *usecase.PreferenceUseCase
+}
+
+// NewPreferenceServiceServer creates a new PreferenceServiceServer instance
+func NewPreferenceServiceServer(preferenceUseCase *usecase.PreferenceUseCase) *PreferenceServiceServer {
+	return &PreferenceServiceServer{
+		preferenceUseCase: preferenceUseCase,
+	}
+}
+
+// GetPreferences retrieves all preferences of a user
+func (s *PreferenceServiceServer) GetPreferences(ctx context.Context, req *pb.GetPreferencesRequest) (*pb.GetPreferencesResponse, error) {
+	// Validate request
+	if req.UserId == "" {
+		return nil, apperrors.MissingField("user_id")
+	}
+
+	// Get preferences
+	preferencesDTO, err := s.preferenceUseCase.GetPreferences(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTO to proto
+	return &pb.GetPreferencesResponse{
+		Preferences: mapper.PreferencesDTOToProto(preferencesDTO),
+	}, nil
+}
+
+// UpdatePreferences changes preferences of a user
+func (s *PreferenceServiceServer) UpdatePreferences(ctx context.Context, req *pb.UpdatePreferencesRequest) (*pb.UpdatePreferencesResponse, error) {
+	// Validate request
+	if req.UserId == "" {
+		return nil, apperrors.MissingField("user_id")
+	}
+
+	// Update preferences
+	preferencesDTO, err := s.preferenceUseCase.UpdatePreferences(ctx, mapper.UpdatePreferencesRequestToDTO(req))
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTO to proto
+	return &pb.UpdatePreferencesResponse{
+		Preferences: mapper.PreferencesDTOToProto(preferencesDTO),
+	}, nil
+}
+
+// ResetPreferences restores preferences of a user to their defaults
+func (s *PreferenceServiceServer) ResetPreferences(ctx context.Context, req *pb.ResetPreferencesRequest) (*pb.ResetPreferencesResponse, error) {
+	// Validate request
+	if req.UserId == "" {
+		return nil, apperrors.MissingField("user_id")
+	}
+
+	// Reset preferences
+	preferencesDTO, err := s.preferenceUseCase.ResetPreferences(ctx, mapper.ResetPreferencesRequestToDTO(req))
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTO to proto
+	return &pb.ResetPreferencesResponse{
+		Preferences: mapper.PreferencesDTOToProto(preferencesDTO),
+	}, nil
+}
@@ -0,0 +1,88 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/preference/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// preferenceRepository implements repository.PreferenceRepository
+type preferenceRepository struct {
+	// We don't store the DB connection here, we get it from config singleton
+}
+
+// NewPreferenceRepository creates a new instance of PreferenceRepository
+func NewPreferenceRepository() repository.PreferenceRepository {
+	return &preferenceRepository{}
+}
+
+// getDB gets the database connection, joining the transaction of the context if any
+func (r *preferenceRepository) getDB(ctx context.Context) (*gorm.DB, error) {
+	return database.Conn(ctx)
+}
+
+// Get retrieves a user's stored preferences, returning nil if the user has none
+func (r *preferenceRepository) Get(ctx context.Context, userID uuid.UUID) (*entity.Preferences, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var preferences entity.Preferences
+	if err := db.WithContext(ctx).First(&preferences, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+	return &preferences, nil
+}
+
+// Merge sets and removes stored values of a user in a single statement. The
+// values are merged into the stored JSONB document by the database, so
+// concurrent merges of different keys do not overwrite each other.
+func (r *preferenceRepository) Merge(ctx context.Context, userID uuid.UUID, set entity.Values, remove []string) (*entity.Preferences, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	// Removing each key with its own operator keeps keys out of the SQL text
+	values := "user_preferences.preference_values" + strings.Repeat(" - ?::text", len(remove))
+	now := time.Now()
+	args := []interface{}{userID, set, now, now}
+	for _, key := range remove {
+		args = append(args, key)
+	}
+
+	var preferences entity.Preferences
+	if err := db.WithContext(ctx).Raw(`INSERT INTO user_preferences (user_id, preference_values, created_at, updated_at)
+VALUES (?, ?::jsonb, ?, ?)
+ON CONFLICT (user_id) DO UPDATE
+SET preference_values = (`+values+`) || EXCLUDED.preference_values, updated_at = EXCLUDED.updated_at
+RETURNING *`, args...).Scan(&preferences).Error; err != nil {
+		return nil, fmt.Errorf("failed to merge preferences: %w", err)
+	}
+	return &preferences, nil
+}
+
+// Delete removes a user's stored preferences
+func (r *preferenceRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	if err := db.WithContext(ctx).Delete(&entity.Preferences{}, "user_id = ?", userID).Error; err != nil {
+		return fmt.Errorf("failed to delete preferences: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/google/uuid"
+)
+
+// AttributeDefinitionDTO represents the data transfer object for an attribute definition
+type AttributeDefinitionDTO struct {
+	ID            uuid.UUID
+	Key           string
+	DisplayName   string
+	Type          string
+	Required      bool
+	Unique        bool
+	Indexed       bool
+	AllowedValues []string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// CreateAttributeDefinitionDTO represents the data transfer object for creating an attribute definition
+type CreateAttributeDefinitionDTO struct {
+	Key           string
+	DisplayName   string
+	Type          string
+	Required      bool
+	Unique        bool
+	Indexed       bool
+	AllowedValues []string
+}
+
+// UpdateAttributeDefinitionDTO represents the data transfer object for updating an attribute definition
+type UpdateAttributeDefinitionDTO struct {
+	ID            uuid.UUID
+	DisplayName   *string
+	Required      *bool
+	Unique        *bool
+	Indexed       *bool
+	AllowedValues []string // nil means not provided
+}
+
+// ToEntity converts CreateAttributeDefinitionDTO to AttributeDefinition entity
+func (dto *CreateAttributeDefinitionDTO) ToEntity() *entity.AttributeDefinition {
+	return &entity.AttributeDefinition{
+		Key:           dto.Key,
+		DisplayName:   dto.DisplayName,
+		Type:          entity.AttributeType(dto.Type),
+		Required:      dto.Required,
+		Unique:        dto.Unique,
+		Indexed:       dto.Indexed,
+		AllowedValues: entity.StringList(dto.AllowedValues),
+	}
+}
+
+// FromAttributeDefinitionEntity creates an AttributeDefinitionDTO from AttributeDefinition entity
+func FromAttributeDefinitionEntity(definition *entity.AttributeDefinition) *AttributeDefinitionDTO {
+	return &AttributeDefinitionDTO{
+		ID:            definition.ID,
+		Key:           definition.Key,
+		DisplayName:   definition.DisplayName,
+		Type:          string(definition.Type),
+		Required:      definition.Required,
+		Unique:        definition.Unique,
+		Indexed:       definition.Indexed,
+		AllowedValues: []string(definition.AllowedValues),
+		CreatedAt:     definition.CreatedAt,
+		UpdatedAt:     definition.UpdatedAt,
+	}
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/google/uuid"
+)
+
+// IdentifierRuleDTO represents the data transfer object for an identifier rule
+type IdentifierRuleDTO struct {
+	ID        uuid.UUID
+	Kind      string
+	Value     string
+	CreatedAt time.Time
+}
+
+// CreateIdentifierRuleDTO represents the data transfer object for creating an identifier rule
+type CreateIdentifierRuleDTO struct {
+	Kind  string
+	Value string
+}
+
+// ToEntity converts CreateIdentifierRuleDTO to IdentifierRule entity
+func (dto *CreateIdentifierRuleDTO) ToEntity() *entity.IdentifierRule {
+	return &entity.IdentifierRule{
+		Kind:  entity.IdentifierRuleKind(dto.Kind),
+		Value: dto.Value,
+	}
+}
+
+// FromIdentifierRuleEntity creates an IdentifierRuleDTO from IdentifierRule entity
+func FromIdentifierRuleEntity(rule *entity.IdentifierRule) *IdentifierRuleDTO {
+	return &IdentifierRuleDTO{
+		ID:        rule.ID,
+		Kind:      string(rule.Kind),
+		Value:     rule.Value,
+		CreatedAt: rule.CreatedAt,
+	}
+}
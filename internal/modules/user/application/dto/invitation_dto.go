@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/google/uuid"
+)
+
+// InvitationDTO represents the data transfer object for an invitation
+type InvitationDTO struct {
+	ID             uuid.UUID
+	Email          string
+	OrganizationID *uuid.UUID
+	Roles          []string
+	Status         string
+	ExpiresAt      time.Time
+	AcceptedAt     *time.Time
+	CreatedAt      time.Time
+}
+
+// InviteUserDTO represents the data transfer object for inviting a user
+type InviteUserDTO struct {
+	Email          string
+	Roles          []string
+	OrganizationID *uuid.UUID
+}
+
+// InviteUserResultDTO represents a created invitation together with its token
+type InviteUserResultDTO struct {
+	Invitation *InvitationDTO
+	Token      string
+}
+
+// AcceptInvitationDTO represents the data transfer object for accepting an invitation
+type AcceptInvitationDTO struct {
+	Token     string
+	Username  string
+	Password  string
+	FirstName string
+	LastName  string
+}
+
+// ListInvitationsDTO represents the data transfer object for listing invitations
+type ListInvitationsDTO struct {
+	Invitations []*InvitationDTO
+	Page        int
+	PageSize    int
+	TotalItems  int
+	TotalPages  int
+}
+
+// ToEntity converts InviteUserDTO to Invitation entity
+func (dto *InviteUserDTO) ToEntity() *entity.Invitation {
+	return &entity.Invitation{
+		Email:          dto.Email,
+		Roles:          entity.StringList(dto.Roles),
+		OrganizationID: dto.OrganizationID,
+	}
+}
+
+// ToEntity converts AcceptInvitationDTO to User entity
+func (dto *AcceptInvitationDTO) ToEntity() *entity.User {
+	return &entity.User{
+		Username:  dto.Username,
+		FirstName: dto.FirstName,
+		LastName:  dto.LastName,
+		IsActive:  true,
+	}
+}
+
+// FromInvitationEntity creates an InvitationDTO from Invitation entity
+func FromInvitationEntity(invitation *entity.Invitation) *InvitationDTO {
+	return &InvitationDTO{
+		ID:             invitation.ID,
+		Email:          invitation.Email,
+		OrganizationID: invitation.OrganizationID,
+		Roles:          []string(invitation.Roles),
+		Status:         string(invitation.GetStatus(time.Now())),
+		ExpiresAt:      invitation.ExpiresAt,
+		AcceptedAt:     invitation.AcceptedAt,
+		CreatedAt:      invitation.CreatedAt,
+	}
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/google/uuid"
+)
+
+// LoginEventDTO represents the data transfer object for a login event
+type LoginEventDTO struct {
+	ID            uuid.UUID
+	UserID        *uuid.UUID
+	Identifier    string
+	Method        string
+	Success       bool
+	FailureReason string
+	IPAddress     string
+	UserAgent     string
+	CreatedAt     time.Time
+}
+
+// ListLoginEventsDTO represents the data transfer object for listing login events
+type ListLoginEventsDTO struct {
+	Events     []*LoginEventDTO
+	Page       int
+	PageSize   int
+	TotalItems int
+	TotalPages int
+}
+
+// FromLoginEventEntity creates a LoginEventDTO from LoginEvent entity
+func FromLoginEventEntity(event *entity.LoginEvent) *LoginEventDTO {
+	return &LoginEventDTO{
+		ID:            event.ID,
+		UserID:        event.UserID,
+		Identifier:    event.Identifier,
+		Method:        string(event.Method),
+		Success:       event.Success,
+		FailureReason: string(event.FailureReason),
+		IPAddress:     event.IPAddress,
+		UserAgent:     event.UserAgent,
+		CreatedAt:     event.CreatedAt,
+	}
+}
@@ -9,68 +9,196 @@ import (
 
 // CreateUserDTO represents the data transfer object for creating a user
 type CreateUserDTO struct {
-	Email     string
-	Username  string
-	Password  string
-	FirstName string
-	LastName  string
-	IsActive  bool
-	IsAdmin   bool
+	Email      string
+	Username   string
+	Password   string
+	FirstName  string
+	LastName   string
+	IsActive   bool
+	IsAdmin    bool
+	Attributes map[string]interface{}
+
+	FamilyNameKana string
+	GivenNameKana  string
+	NameOrder      string
+	Locale         string
+
+	ValidFrom  *time.Time
+	ValidUntil *time.Time
 }
 
 // UpdateUserDTO represents the data transfer object for updating a user
 type UpdateUserDTO struct {
-	ID        uuid.UUID
+	ID uuid.UUID
+
+	// Fields lists the field paths to update; a listed field with a nil value is cleared
+	Fields []string
+
 	Email     *string
 	Username  *string
 	FirstName *string
 	LastName  *string
 	IsActive  *bool
 	IsAdmin   *bool
+
+	FamilyNameKana *string
+	GivenNameKana  *string
+	NameOrder      *string
+	Locale         *string
+
+	ValidFrom  *time.Time
+	ValidUntil *time.Time
+
+	// Attributes replaces all custom attribute values when non-nil
+	Attributes map[string]interface{}
+
+	// ETag rejects the update if the user has changed since it was read
+	ETag string
+}
+
+// DeleteUserDTO represents the data transfer object for deleting a user in a batch
+type DeleteUserDTO struct {
+	ID string
+
+	// ETag rejects the delete if the user has changed since it was read
+	ETag string
+}
+
+// BatchUserResultDTO represents the outcome of one item of a batch write
+type BatchUserResultDTO struct {
+	// User is the created or updated user; nil for deletes and failed items
+	User *UserDTO
+
+	// ChangedFields lists the paths of the fields an update changed
+	ChangedFields []string
+
+	// Err is the reason the item failed, nil when it succeeded
+	Err error
+}
+
+// BatchGetUserResultDTO represents the outcome of looking up one requested ID
+type BatchGetUserResultDTO struct {
+	// ID is the ID as requested
+	ID string
+
+	// User is the user with the ID; nil when Err is set
+	User *UserDTO
+
+	// Err is entity.ErrInvalidUserID for malformed IDs and entity.ErrUserNotFound
+	// for missing users
+	Err error
+}
+
+// UserChangeDTO represents a committed change to a user
+type UserChangeDTO struct {
+	Type   string
+	UserID uuid.UUID
+
+	// Before and After are the user before and after the change; Before is nil for
+	// creations and After for hard deletes
+	Before *UserDTO
+	After  *UserDTO
+
+	ChangedAt time.Time
+}
+
+// FromUserChangeEntity converts a user change log entry to a DTO
+func FromUserChangeEntity(change *entity.UserChange) *UserChangeDTO {
+	changeDTO := &UserChangeDTO{
+		Type:      string(change.Type),
+		UserID:    change.UserID,
+		ChangedAt: change.ChangedAt,
+	}
+	if change.Before != nil {
+		changeDTO.Before = FromEntity(change.Before)
+	}
+	if change.After != nil {
+		changeDTO.After = FromEntity(change.After)
+	}
+	return changeDTO
 }
 
 // UserDTO represents the data transfer object for a user
 type UserDTO struct {
-	ID        uuid.UUID
-	Email     string
-	Username  string
-	FirstName string
-	LastName  string
-	FullName  string
-	IsActive  bool
-	IsAdmin   bool
-	Status    string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt *time.Time
+	ID         uuid.UUID
+	Email      string
+	Username   string
+	FirstName  string
+	LastName   string
+	FullName   string
+	IsActive   bool
+	IsAdmin    bool
+	Status     string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  *time.Time
+	Attributes map[string]interface{}
+	ETag       string
+
+	LastLoginAt *time.Time
+
+	MustChangePassword bool
+	PasswordChangedAt  *time.Time
+
+	FamilyNameKana string
+	GivenNameKana  string
+	NameOrder      string
+	Locale         string
+
+	ValidFrom  *time.Time
+	ValidUntil *time.Time
 }
 
 // ToEntity converts CreateUserDTO to User entity
 func (dto *CreateUserDTO) ToEntity() *entity.User {
 	return &entity.User{
-		Email:     dto.Email,
-		Username:  dto.Username,
-		FirstName: dto.FirstName,
-		LastName:  dto.LastName,
-		IsActive:  dto.IsActive,
-		IsAdmin:   dto.IsAdmin,
+		Email:      dto.Email,
+		Username:   dto.Username,
+		FirstName:  dto.FirstName,
+		LastName:   dto.LastName,
+		IsActive:   dto.IsActive,
+		IsAdmin:    dto.IsAdmin,
+		Attributes: entity.Attributes(dto.Attributes),
+
+		FamilyNameKana: dto.FamilyNameKana,
+		GivenNameKana:  dto.GivenNameKana,
+		NameOrder:      entity.NameOrder(dto.NameOrder),
+		Locale:         dto.Locale,
+
+		ValidFrom:  dto.ValidFrom,
+		ValidUntil: dto.ValidUntil,
 	}
 }
 
 // FromEntity creates a UserDTO from User entity
 func FromEntity(user *entity.User) *UserDTO {
 	dto := &UserDTO{
-		ID:        user.ID,
-		Email:     user.Email,
-		Username:  user.Username,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		FullName:  user.GetFullName(),
-		IsActive:  user.IsActive,
-		IsAdmin:   user.IsAdmin,
-		Status:    string(user.GetStatus()),
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:         user.ID,
+		Email:      user.Email,
+		Username:   user.Username,
+		FirstName:  user.FirstName,
+		LastName:   user.LastName,
+		FullName:   user.GetFullName(),
+		IsActive:   user.IsActive,
+		IsAdmin:    user.IsAdmin,
+		Status:     string(user.GetStatus()),
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
+		Attributes: user.Attributes,
+		ETag:       user.ETag(),
+
+		LastLoginAt: user.LastLoginAt,
+
+		MustChangePassword: user.MustChangePassword,
+		PasswordChangedAt:  user.PasswordChangedAt,
+
+		FamilyNameKana: user.FamilyNameKana,
+		GivenNameKana:  user.GivenNameKana,
+		NameOrder:      string(user.NameOrder),
+		Locale:         user.Locale,
+
+		ValidFrom:  user.ValidFrom,
+		ValidUntil: user.ValidUntil,
 	}
 
 	if user.DeletedAt.Valid {
@@ -80,21 +208,78 @@ func FromEntity(user *entity.User) *UserDTO {
 	return dto
 }
 
-// ListUsersDTO represents the data transfer object for listing users
+// ListUsersDTO represents the data transfer object for listing users.
+// TotalItems and TotalPages are zero when the total was not counted.
 type ListUsersDTO struct {
-	Users      []*UserDTO
-	Page       int
-	PageSize   int
-	TotalItems int
-	TotalPages int
+	Users       []*UserDTO
+	Page        int
+	PageSize    int
+	TotalItems  int
+	TotalPages  int
+	HasNext     bool
+	HasPrevious bool
+
+	// NextPageToken continues the listing after this page; empty on the last page
+	NextPageToken string
 }
 
-// SearchUsersDTO represents the data transfer object for searching users
-type SearchUsersDTO struct {
-	Query    string
+// PageRequestDTO represents pagination parameters for listing users
+type PageRequestDTO struct {
 	Page     int
 	PageSize int
-	Filter   *FilterDTO
+
+	// PageToken continues a listing after a previous page; Page is ignored when set
+	PageToken string
+
+	// IncludeTotal counts the total even when paging with a token
+	IncludeTotal bool
+}
+
+// SortDTO represents sort options for listing users
+type SortDTO struct {
+	Field string
+	Order string
+}
+
+// SearchUsersDTO represents the data transfer object for searching users
+type SearchUsersDTO struct {
+	Query      string
+	Pagination *PageRequestDTO
+	Filter     *FilterDTO
+	Sort       *SortDTO
+}
+
+// SearchUsersResultDTO represents the result of a user search
+type SearchUsersResultDTO struct {
+	ListUsersDTO
+
+	// Matches describes how each user matched the query, in the order of Users
+	Matches []*UserMatchDTO
+
+	// TotalMatches is the number of users matching the query and filter
+	TotalMatches int
+}
+
+// UserMatchDTO represents the relevance of a user to a search query
+type UserMatchDTO struct {
+	UserID string
+	// Score is higher for better matches; prefix matches score above approximate ones
+	Score      float64
+	Highlights []*HighlightDTO
+}
+
+// HighlightDTO represents where a search query occurs in a field of a user.
+// Ranges are code point offsets into Value and empty for approximate matches.
+type HighlightDTO struct {
+	Field  string
+	Value  string
+	Ranges []TextRangeDTO
+}
+
+// TextRangeDTO represents the characters from Start up to but excluding End
+type TextRangeDTO struct {
+	Start int
+	End   int
 }
 
 // FilterDTO represents filter options for users
@@ -103,6 +288,41 @@ type FilterDTO struct {
 	Username *string
 	IsActive *bool
 	IsAdmin  *bool
+
+	// EmailMatch, UsernameMatch and NameMatch are "exact", "prefix" or "contains",
+	// the default when empty
+	EmailMatch    string
+	UsernameMatch string
+
+	// FirstName and LastName match names as selected by NameMatch
+	FirstName *string
+	LastName  *string
+	NameMatch string
+
+	// Status is "active", "inactive" or "suspended"
+	Status *string
+
+	// IDs restricts the users to these IDs when not empty; ExcludeIDs removes users
+	IDs        []string
+	ExcludeIDs []string
+
+	// CreatedAfter and UpdatedAfter are inclusive, CreatedBefore and UpdatedBefore exclusive
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+
+	// Reading matches a partial name reading in hiragana or katakana
+	Reading *string
+
+	// ExpiringWithin matches active users whose validity ends within this duration from now
+	ExpiringWithin *time.Duration
+
+	// Attributes matches indexed custom attribute values exactly
+	Attributes map[string]interface{}
+
+	// Expression is an AIP-160 filter expression
+	Expression string
 }
 
 // ChangePasswordDTO represents the data transfer object for changing password
@@ -110,10 +330,31 @@ type ChangePasswordDTO struct {
 	UserID      uuid.UUID
 	OldPassword string
 	NewPassword string
+	ETag        string
 }
 
 // AuthenticateDTO represents the data transfer object for authentication
 type AuthenticateDTO struct {
 	Identifier string // Email or username
 	Password   string
+
+	// IPAddress and UserAgent identify the client for the login history
+	IPAddress string
+	UserAgent string
+}
+
+// AuthenticationDTO represents the outcome of a successful authentication
+type AuthenticationDTO struct {
+	User           *UserDTO
+	Token          string
+	TokenExpiresAt time.Time
+
+	// PasswordChangeRequired means the token only permits changing the password
+	PasswordChangeRequired bool
+}
+
+// TemporaryPasswordDTO represents a user whose password was replaced by a temporary one
+type TemporaryPasswordDTO struct {
+	User              *UserDTO
+	TemporaryPassword string
 }
@@ -0,0 +1,52 @@
+package dto
+
+import "github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+
+// MergeUsersDTO represents the data transfer object for merging two users
+type MergeUsersDTO struct {
+	SourceID string
+	TargetID string
+
+	// FieldResolution maps field paths to "source" or "target"; unlisted fields keep the target's value
+	FieldResolution map[string]string
+
+	// SourceETag and TargetETag reject the merge if either user has changed since it was read
+	SourceETag string
+	TargetETag string
+
+	DryRun bool
+}
+
+// MergeUsersResultDTO represents the outcome, or the preview, of a merge
+type MergeUsersResultDTO struct {
+	User          *UserDTO
+	SourceID      string
+	ChangedFields []string
+	DryRun        bool
+
+	MovedOrganizationMemberships int64
+	MovedGroupMemberships        int64
+	MovedLoginEvents             int64
+	MovedInvitations             int64
+	MovedPreferences             bool
+}
+
+// FromMergeResult creates a MergeUsersResultDTO from a merge and the records it moved
+func FromMergeResult(merge *entity.UserMerge, moved *entity.MergedReferences, dryRun bool) *MergeUsersResultDTO {
+	changedFields := make([]string, 0, len(merge.ChangedFields))
+	for _, field := range merge.ChangedFields {
+		changedFields = append(changedFields, string(field))
+	}
+	return &MergeUsersResultDTO{
+		User:          FromEntity(merge.Target),
+		SourceID:      merge.Source.ID.String(),
+		ChangedFields: changedFields,
+		DryRun:        dryRun,
+
+		MovedOrganizationMemberships: moved.OrganizationMemberships,
+		MovedGroupMemberships:        moved.GroupMemberships,
+		MovedLoginEvents:             moved.LoginEvents,
+		MovedInvitations:             moved.Invitations,
+		MovedPreferences:             moved.Preferences,
+	}
+}
@@ -0,0 +1,120 @@
+package mapper
+
+import (
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AttributeDefinitionDTOToProto converts an AttributeDefinitionDTO to proto message
+func AttributeDefinitionDTOToProto(dto *dto.AttributeDefinitionDTO) *pb.AttributeDefinition {
+	if dto == nil {
+		return nil
+	}
+
+	return &pb.AttributeDefinition{
+		Id:            dto.ID.String(),
+		Key:           dto.Key,
+		DisplayName:   dto.DisplayName,
+		Type:          AttributeTypeToProto(dto.Type),
+		Required:      dto.Required,
+		Unique:        dto.Unique,
+		Indexed:       dto.Indexed,
+		AllowedValues: dto.AllowedValues,
+		CreatedAt:     timestamppb.New(dto.CreatedAt),
+		UpdatedAt:     timestamppb.New(dto.UpdatedAt),
+	}
+}
+
+// AttributeTypeToProto converts an attribute type string to proto enum
+func AttributeTypeToProto(attributeType string) pb.AttributeType {
+	switch entity.AttributeType(attributeType) {
+	case entity.AttributeTypeString:
+		return pb.AttributeType_ATTRIBUTE_TYPE_STRING
+	case entity.AttributeTypeNumber:
+		return pb.AttributeType_ATTRIBUTE_TYPE_NUMBER
+	case entity.AttributeTypeBoolean:
+		return pb.AttributeType_ATTRIBUTE_TYPE_BOOLEAN
+	default:
+		return pb.AttributeType_ATTRIBUTE_TYPE_UNSPECIFIED
+	}
+}
+
+// AttributeTypeFromProto converts a proto enum to an attribute type string
+func AttributeTypeFromProto(attributeType pb.AttributeType) string {
+	switch attributeType {
+	case pb.AttributeType_ATTRIBUTE_TYPE_STRING:
+		return string(entity.AttributeTypeString)
+	case pb.AttributeType_ATTRIBUTE_TYPE_NUMBER:
+		return string(entity.AttributeTypeNumber)
+	case pb.AttributeType_ATTRIBUTE_TYPE_BOOLEAN:
+		return string(entity.AttributeTypeBoolean)
+	default:
+		return ""
+	}
+}
+
+// CreateAttributeDefinitionRequestToDTO converts CreateAttributeDefinitionRequest to CreateAttributeDefinitionDTO
+func CreateAttributeDefinitionRequestToDTO(req *pb.CreateAttributeDefinitionRequest) *dto.CreateAttributeDefinitionDTO {
+	if req == nil {
+		return nil
+	}
+
+	return &dto.CreateAttributeDefinitionDTO{
+		Key:           req.Key,
+		DisplayName:   req.DisplayName,
+		Type:          AttributeTypeFromProto(req.Type),
+		Required:      req.Required,
+		Unique:        req.Unique,
+		Indexed:       req.Indexed,
+		AllowedValues: req.AllowedValues,
+	}
+}
+
+// UpdateAttributeDefinitionRequestToDTO converts UpdateAttributeDefinitionRequest to UpdateAttributeDefinitionDTO
+func UpdateAttributeDefinitionRequestToDTO(req *pb.UpdateAttributeDefinitionRequest) (*dto.UpdateAttributeDefinitionDTO, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, &entity.FieldViolation{Field: "id", Description: "must be a UUID", Err: entity.ErrInvalidAttributeDefinition}
+	}
+
+	dto := &dto.UpdateAttributeDefinitionDTO{
+		ID: id,
+	}
+
+	// Check field mask to determine which fields to update
+	if req.UpdateMask != nil {
+		for _, path := range req.UpdateMask.Paths {
+			switch path {
+			case "display_name":
+				dto.DisplayName = req.DisplayName
+			case "required":
+				dto.Required = req.Required
+			case "unique":
+				dto.Unique = req.Unique
+			case "indexed":
+				dto.Indexed = req.Indexed
+			case "allowed_values":
+				// An empty list in the mask clears the restriction
+				dto.AllowedValues = append([]string{}, req.AllowedValues...)
+			}
+		}
+	} else {
+		// If no field mask, update all provided fields
+		dto.DisplayName = req.DisplayName
+		dto.Required = req.Required
+		dto.Unique = req.Unique
+		dto.Indexed = req.Indexed
+		if len(req.AllowedValues) > 0 {
+			dto.AllowedValues = req.AllowedValues
+		}
+	}
+
+	return dto, nil
+}
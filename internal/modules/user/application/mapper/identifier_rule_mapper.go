@@ -0,0 +1,66 @@
+package mapper
+
+import (
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// IdentifierRuleDTOToProto converts an IdentifierRuleDTO to proto message
+func IdentifierRuleDTOToProto(dto *dto.IdentifierRuleDTO) *pb.IdentifierRule {
+	if dto == nil {
+		return nil
+	}
+
+	return &pb.IdentifierRule{
+		Id:        dto.ID.String(),
+		Kind:      IdentifierRuleKindToProto(dto.Kind),
+		Value:     dto.Value,
+		CreatedAt: timestamppb.New(dto.CreatedAt),
+	}
+}
+
+// IdentifierRuleKindToProto converts an identifier rule kind string to proto enum
+func IdentifierRuleKindToProto(kind string) pb.IdentifierRuleKind {
+	switch entity.IdentifierRuleKind(kind) {
+	case entity.IdentifierRuleReservedUsername:
+		return pb.IdentifierRuleKind_IDENTIFIER_RULE_KIND_RESERVED_USERNAME
+	case entity.IdentifierRuleUsernamePattern:
+		return pb.IdentifierRuleKind_IDENTIFIER_RULE_KIND_USERNAME_PATTERN
+	case entity.IdentifierRuleBlockedEmailDomain:
+		return pb.IdentifierRuleKind_IDENTIFIER_RULE_KIND_BLOCKED_EMAIL_DOMAIN
+	case entity.IdentifierRuleAllowedEmailDomain:
+		return pb.IdentifierRuleKind_IDENTIFIER_RULE_KIND_ALLOWED_EMAIL_DOMAIN
+	default:
+		return pb.IdentifierRuleKind_IDENTIFIER_RULE_KIND_UNSPECIFIED
+	}
+}
+
+// IdentifierRuleKindFromProto converts a proto enum to an identifier rule kind string
+func IdentifierRuleKindFromProto(kind pb.IdentifierRuleKind) string {
+	switch kind {
+	case pb.IdentifierRuleKind_IDENTIFIER_RULE_KIND_RESERVED_USERNAME:
+		return string(entity.IdentifierRuleReservedUsername)
+	case pb.IdentifierRuleKind_IDENTIFIER_RULE_KIND_USERNAME_PATTERN:
+		return string(entity.IdentifierRuleUsernamePattern)
+	case pb.IdentifierRuleKind_IDENTIFIER_RULE_KIND_BLOCKED_EMAIL_DOMAIN:
+		return string(entity.IdentifierRuleBlockedEmailDomain)
+	case pb.IdentifierRuleKind_IDENTIFIER_RULE_KIND_ALLOWED_EMAIL_DOMAIN:
+		return string(entity.IdentifierRuleAllowedEmailDomain)
+	default:
+		return ""
+	}
+}
+
+// CreateIdentifierRuleRequestToDTO converts CreateIdentifierRuleRequest to CreateIdentifierRuleDTO
+func CreateIdentifierRuleRequestToDTO(req *pb.CreateIdentifierRuleRequest) *dto.CreateIdentifierRuleDTO {
+	if req == nil {
+		return nil
+	}
+
+	return &dto.CreateIdentifierRuleDTO{
+		Kind:  IdentifierRuleKindFromProto(req.Kind),
+		Value: req.Value,
+	}
+}
@@ -0,0 +1,86 @@
+package mapper
+
+import (
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// InvitationDTOToProto converts an InvitationDTO to proto message
+func InvitationDTOToProto(dto *dto.InvitationDTO) *pb.Invitation {
+	if dto == nil {
+		return nil
+	}
+
+	invitation := &pb.Invitation{
+		Id:        dto.ID.String(),
+		Email:     dto.Email,
+		Roles:     dto.Roles,
+		Status:    InvitationStatusToProto(dto.Status),
+		ExpiresAt: timestamppb.New(dto.ExpiresAt),
+		CreatedAt: timestamppb.New(dto.CreatedAt),
+	}
+	if dto.OrganizationID != nil {
+		invitation.OrganizationId = dto.OrganizationID.String()
+	}
+	if dto.AcceptedAt != nil {
+		invitation.AcceptedAt = timestamppb.New(*dto.AcceptedAt)
+	}
+
+	return invitation
+}
+
+// InvitationStatusToProto converts an invitation status string to proto enum
+func InvitationStatusToProto(invitationStatus string) pb.InvitationStatus {
+	switch entity.InvitationStatus(invitationStatus) {
+	case entity.InvitationStatusPending:
+		return pb.InvitationStatus_INVITATION_STATUS_PENDING
+	case entity.InvitationStatusAccepted:
+		return pb.InvitationStatus_INVITATION_STATUS_ACCEPTED
+	case entity.InvitationStatusRevoked:
+		return pb.InvitationStatus_INVITATION_STATUS_REVOKED
+	case entity.InvitationStatusExpired:
+		return pb.InvitationStatus_INVITATION_STATUS_EXPIRED
+	default:
+		return pb.InvitationStatus_INVITATION_STATUS_UNSPECIFIED
+	}
+}
+
+// InviteUserRequestToDTO converts InviteUserRequest to InviteUserDTO
+func InviteUserRequestToDTO(req *pb.InviteUserRequest) (*dto.InviteUserDTO, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	dto := &dto.InviteUserDTO{
+		Email: req.Email,
+		Roles: req.Roles,
+	}
+
+	if req.OrganizationId != "" {
+		organizationID, err := uuid.Parse(req.OrganizationId)
+		if err != nil {
+			return nil, &entity.FieldViolation{Field: "organization_id", Description: "must be a UUID", Err: entity.ErrInvalidInvitation}
+		}
+		dto.OrganizationID = &organizationID
+	}
+
+	return dto, nil
+}
+
+// AcceptInvitationRequestToDTO converts AcceptInvitationRequest to AcceptInvitationDTO
+func AcceptInvitationRequestToDTO(req *pb.AcceptInvitationRequest) *dto.AcceptInvitationDTO {
+	if req == nil {
+		return nil
+	}
+
+	return &dto.AcceptInvitationDTO{
+		Token:     req.Token,
+		Username:  req.Username,
+		Password:  req.Password,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+	}
+}
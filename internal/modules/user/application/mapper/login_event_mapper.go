@@ -0,0 +1,63 @@
+package mapper
+
+import (
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// LoginEventDTOToProto converts a LoginEventDTO to proto message
+func LoginEventDTOToProto(dto *dto.LoginEventDTO) *pb.LoginEvent {
+	if dto == nil {
+		return nil
+	}
+
+	protoEvent := &pb.LoginEvent{
+		Id:            dto.ID.String(),
+		Identifier:    dto.Identifier,
+		Method:        LoginMethodToProto(dto.Method),
+		Success:       dto.Success,
+		FailureReason: LoginFailureReasonToProto(dto.FailureReason),
+		IpAddress:     dto.IPAddress,
+		UserAgent:     dto.UserAgent,
+		CreatedAt:     timestamppb.New(dto.CreatedAt),
+	}
+	if dto.UserID != nil {
+		protoEvent.UserId = dto.UserID.String()
+	}
+
+	return protoEvent
+}
+
+// LoginMethodToProto converts a login method string to proto enum
+func LoginMethodToProto(method string) pb.LoginMethod {
+	switch entity.LoginMethod(method) {
+	case entity.LoginMethodPassword:
+		return pb.LoginMethod_LOGIN_METHOD_PASSWORD
+	case entity.LoginMethodToken:
+		return pb.LoginMethod_LOGIN_METHOD_TOKEN
+	case entity.LoginMethodMFA:
+		return pb.LoginMethod_LOGIN_METHOD_MFA
+	default:
+		return pb.LoginMethod_LOGIN_METHOD_UNSPECIFIED
+	}
+}
+
+// LoginFailureReasonToProto converts a login failure reason string to proto enum
+func LoginFailureReasonToProto(reason string) pb.LoginFailureReason {
+	switch entity.LoginFailureReason(reason) {
+	case entity.LoginFailureUnknownIdentifier:
+		return pb.LoginFailureReason_LOGIN_FAILURE_REASON_UNKNOWN_IDENTIFIER
+	case entity.LoginFailureInvalidPassword:
+		return pb.LoginFailureReason_LOGIN_FAILURE_REASON_INVALID_PASSWORD
+	case entity.LoginFailureUserInactive:
+		return pb.LoginFailureReason_LOGIN_FAILURE_REASON_USER_INACTIVE
+	case entity.LoginFailureInternalError:
+		return pb.LoginFailureReason_LOGIN_FAILURE_REASON_INTERNAL_ERROR
+	case entity.LoginFailureOutsideValidity:
+		return pb.LoginFailureReason_LOGIN_FAILURE_REASON_OUTSIDE_VALIDITY
+	default:
+		return pb.LoginFailureReason_LOGIN_FAILURE_REASON_UNSPECIFIED
+	}
+}
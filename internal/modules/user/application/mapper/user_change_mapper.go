@@ -0,0 +1,43 @@
+package mapper
+
+import (
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// UserChangeDTOToProto converts a UserChangeDTO to proto message
+func UserChangeDTOToProto(dto *dto.UserChangeDTO) *pb.UserChange {
+	if dto == nil {
+		return nil
+	}
+
+	protoChange := &pb.UserChange{
+		Type:       UserChangeTypeToProto(dto.Type),
+		UserId:     dto.UserID.String(),
+		ChangeTime: timestamppb.New(dto.ChangedAt),
+	}
+	if dto.Before != nil {
+		protoChange.Before = UserDTOToProto(dto.Before)
+	}
+	if dto.After != nil {
+		protoChange.After = UserDTOToProto(dto.After)
+	}
+
+	return protoChange
+}
+
+// UserChangeTypeToProto converts a user change type string to proto enum
+func UserChangeTypeToProto(changeType string) pb.UserChangeType {
+	switch entity.UserChangeType(changeType) {
+	case entity.UserChangeCreated:
+		return pb.UserChangeType_USER_CHANGE_TYPE_CREATED
+	case entity.UserChangeUpdated:
+		return pb.UserChangeType_USER_CHANGE_TYPE_UPDATED
+	case entity.UserChangeDeleted:
+		return pb.UserChangeType_USER_CHANGE_TYPE_DELETED
+	default:
+		return pb.UserChangeType_USER_CHANGE_TYPE_UNSPECIFIED
+	}
+}
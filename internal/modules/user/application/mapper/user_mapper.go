@@ -1,10 +1,14 @@
 package mapper
 
 import (
+	"time"
+
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	commonpb "github.com/gigi434/sample-grpc-server/pkg/generated/common"
 	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -15,16 +19,22 @@ func UserToProto(user *entity.User) *pb.User {
 	}
 
 	protoUser := &pb.User{
-		Id:        user.ID.String(),
-		Email:     user.Email,
-		Username:  user.Username,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		FullName:  user.GetFullName(),
-		IsActive:  user.IsActive,
-		IsAdmin:   user.IsAdmin,
-		CreatedAt: timestamppb.New(user.CreatedAt),
-		UpdatedAt: timestamppb.New(user.UpdatedAt),
+		Id:         user.ID.String(),
+		Email:      user.Email,
+		Username:   user.Username,
+		FirstName:  user.FirstName,
+		LastName:   user.LastName,
+		FullName:   user.GetFullName(),
+		IsActive:   user.IsActive,
+		IsAdmin:    user.IsAdmin,
+		CreatedAt:  timestamppb.New(user.CreatedAt),
+		UpdatedAt:  timestamppb.New(user.UpdatedAt),
+		Attributes: AttributesToProto(user.Attributes),
+
+		FamilyNameKana: user.FamilyNameKana,
+		GivenNameKana:  user.GivenNameKana,
+		NameOrder:      NameOrderToProto(string(user.NameOrder)),
+		Locale:         user.Locale,
 	}
 
 	// Set status
@@ -44,6 +54,18 @@ func UserToProto(user *entity.User) *pb.User {
 		protoUser.DeletedAt = timestamppb.New(user.DeletedAt.Time)
 	}
 
+	// Set last_login_at if the user has signed in
+	if user.LastLoginAt != nil {
+		protoUser.LastLoginAt = timestamppb.New(*user.LastLoginAt)
+	}
+
+	// Set the validity window where bounded
+	protoUser.ValidFrom = optionalTimestamp(user.ValidFrom)
+	protoUser.ValidUntil = optionalTimestamp(user.ValidUntil)
+
+	protoUser.MustChangePassword = user.MustChangePassword
+	protoUser.PasswordChangedAt = optionalTimestamp(user.PasswordChangedAt)
+
 	return protoUser
 }
 
@@ -54,16 +76,23 @@ func UserDTOToProto(dto *dto.UserDTO) *pb.User {
 	}
 
 	protoUser := &pb.User{
-		Id:        dto.ID.String(),
-		Email:     dto.Email,
-		Username:  dto.Username,
-		FirstName: dto.FirstName,
-		LastName:  dto.LastName,
-		FullName:  dto.FullName,
-		IsActive:  dto.IsActive,
-		IsAdmin:   dto.IsAdmin,
-		CreatedAt: timestamppb.New(dto.CreatedAt),
-		UpdatedAt: timestamppb.New(dto.UpdatedAt),
+		Id:         dto.ID.String(),
+		Email:      dto.Email,
+		Username:   dto.Username,
+		FirstName:  dto.FirstName,
+		LastName:   dto.LastName,
+		FullName:   dto.FullName,
+		IsActive:   dto.IsActive,
+		IsAdmin:    dto.IsAdmin,
+		CreatedAt:  timestamppb.New(dto.CreatedAt),
+		UpdatedAt:  timestamppb.New(dto.UpdatedAt),
+		Attributes: AttributesToProto(dto.Attributes),
+		Etag:       dto.ETag,
+
+		FamilyNameKana: dto.FamilyNameKana,
+		GivenNameKana:  dto.GivenNameKana,
+		NameOrder:      NameOrderToProto(dto.NameOrder),
+		Locale:         dto.Locale,
 	}
 
 	// Set status
@@ -83,6 +112,18 @@ func UserDTOToProto(dto *dto.UserDTO) *pb.User {
 		protoUser.DeletedAt = timestamppb.New(*dto.DeletedAt)
 	}
 
+	// Set last_login_at if the user has signed in
+	if dto.LastLoginAt != nil {
+		protoUser.LastLoginAt = timestamppb.New(*dto.LastLoginAt)
+	}
+
+	// Set the validity window where bounded
+	protoUser.ValidFrom = optionalTimestamp(dto.ValidFrom)
+	protoUser.ValidUntil = optionalTimestamp(dto.ValidUntil)
+
+	protoUser.MustChangePassword = dto.MustChangePassword
+	protoUser.PasswordChangedAt = optionalTimestamp(dto.PasswordChangedAt)
+
 	return protoUser
 }
 
@@ -100,6 +141,11 @@ func CreateUserRequestToDTO(req *pb.CreateUserRequest) *dto.CreateUserDTO {
 		LastName:  req.LastName,
 		IsActive:  true,  // Default to true
 		IsAdmin:   false, // Default to false
+
+		FamilyNameKana: req.FamilyNameKana,
+		GivenNameKana:  req.GivenNameKana,
+		NameOrder:      NameOrderFromProto(req.NameOrder),
+		Locale:         req.Locale,
 	}
 
 	// Override defaults if provided
@@ -109,6 +155,11 @@ func CreateUserRequestToDTO(req *pb.CreateUserRequest) *dto.CreateUserDTO {
 	if req.IsAdmin != nil {
 		dto.IsAdmin = *req.IsAdmin
 	}
+	if req.Attributes != nil {
+		dto.Attributes = req.Attributes.AsMap()
+	}
+	dto.ValidFrom = optionalTime(req.ValidFrom)
+	dto.ValidUntil = optionalTime(req.ValidUntil)
 
 	return dto
 }
@@ -121,66 +172,261 @@ func UpdateUserRequestToDTO(req *pb.UpdateUserRequest) (*dto.UpdateUserDTO, erro
 
 	id, err := uuid.Parse(req.Id)
 	if err != nil {
-		return nil, err
+		return nil, &entity.FieldViolation{Field: "id", Description: "must be a UUID", Err: entity.ErrInvalidUserID}
 	}
 
 	dto := &dto.UpdateUserDTO{
-		ID: id,
-	}
-
-	// Check field mask to determine which fields to update
-	if req.UpdateMask != nil {
-		for _, path := range req.UpdateMask.Paths {
-			switch path {
-			case "email":
-				if req.Email != nil {
-					dto.Email = req.Email
-				}
-			case "username":
-				if req.Username != nil {
-					dto.Username = req.Username
-				}
-			case "first_name":
-				if req.FirstName != nil {
-					dto.FirstName = req.FirstName
-				}
-			case "last_name":
-				if req.LastName != nil {
-					dto.LastName = req.LastName
-				}
-			case "is_active":
-				if req.IsActive != nil {
-					dto.IsActive = req.IsActive
-				}
-			case "is_admin":
-				if req.IsAdmin != nil {
-					dto.IsAdmin = req.IsAdmin
-				}
+		ID:   id,
+		ETag: req.Etag,
+	}
+
+	// Without a field mask, update every field present in the request
+	if req.UpdateMask == nil || len(req.UpdateMask.Paths) == 0 {
+		dto.Fields = populatedUpdateFields(req)
+	} else {
+		fields, err := entity.ParseUpdateMask(req.UpdateMask.Paths)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range fields {
+			dto.Fields = append(dto.Fields, string(field))
+		}
+	}
+
+	// Copy values of the selected fields; absent values clear the field
+	for _, path := range dto.Fields {
+		switch entity.UserField(path) {
+		case entity.UserFieldEmail:
+			dto.Email = req.Email
+		case entity.UserFieldUsername:
+			dto.Username = req.Username
+		case entity.UserFieldFirstName:
+			dto.FirstName = req.FirstName
+		case entity.UserFieldLastName:
+			dto.LastName = req.LastName
+		case entity.UserFieldFamilyNameKana:
+			dto.FamilyNameKana = req.FamilyNameKana
+		case entity.UserFieldGivenNameKana:
+			dto.GivenNameKana = req.GivenNameKana
+		case entity.UserFieldNameOrder:
+			if req.NameOrder != nil {
+				nameOrder := NameOrderFromProto(*req.NameOrder)
+				dto.NameOrder = &nameOrder
+			}
+		case entity.UserFieldLocale:
+			dto.Locale = req.Locale
+		case entity.UserFieldIsActive:
+			dto.IsActive = req.IsActive
+		case entity.UserFieldIsAdmin:
+			dto.IsAdmin = req.IsAdmin
+		case entity.UserFieldValidFrom:
+			dto.ValidFrom = optionalTime(req.ValidFrom)
+		case entity.UserFieldValidUntil:
+			dto.ValidUntil = optionalTime(req.ValidUntil)
+		case entity.UserFieldAttributes:
+			if req.Attributes != nil {
+				dto.Attributes = req.Attributes.AsMap()
 			}
 		}
-	} else {
-		// If no field mask, update all provided fields
-		dto.Email = req.Email
-		dto.Username = req.Username
-		dto.FirstName = req.FirstName
-		dto.LastName = req.LastName
-		dto.IsActive = req.IsActive
-		dto.IsAdmin = req.IsAdmin
 	}
 
 	return dto, nil
 }
 
+// populatedUpdateFields returns the paths of fields set on an update request
+func populatedUpdateFields(req *pb.UpdateUserRequest) []string {
+	var fields []string
+	if req.Email != nil {
+		fields = append(fields, string(entity.UserFieldEmail))
+	}
+	if req.Username != nil {
+		fields = append(fields, string(entity.UserFieldUsername))
+	}
+	if req.FirstName != nil {
+		fields = append(fields, string(entity.UserFieldFirstName))
+	}
+	if req.LastName != nil {
+		fields = append(fields, string(entity.UserFieldLastName))
+	}
+	if req.FamilyNameKana != nil {
+		fields = append(fields, string(entity.UserFieldFamilyNameKana))
+	}
+	if req.GivenNameKana != nil {
+		fields = append(fields, string(entity.UserFieldGivenNameKana))
+	}
+	if req.NameOrder != nil {
+		fields = append(fields, string(entity.UserFieldNameOrder))
+	}
+	if req.Locale != nil {
+		fields = append(fields, string(entity.UserFieldLocale))
+	}
+	if req.IsActive != nil {
+		fields = append(fields, string(entity.UserFieldIsActive))
+	}
+	if req.IsAdmin != nil {
+		fields = append(fields, string(entity.UserFieldIsAdmin))
+	}
+	if req.ValidFrom != nil {
+		fields = append(fields, string(entity.UserFieldValidFrom))
+	}
+	if req.ValidUntil != nil {
+		fields = append(fields, string(entity.UserFieldValidUntil))
+	}
+	if req.Attributes != nil {
+		fields = append(fields, string(entity.UserFieldAttributes))
+	}
+	return fields
+}
+
 // ListUsersFilterToDTO converts ListUsersFilter to FilterDTO
 func ListUsersFilterToDTO(filter *pb.ListUsersFilter) *dto.FilterDTO {
 	if filter == nil {
 		return nil
 	}
 
-	return &dto.FilterDTO{
-		Email:    filter.Email,
-		Username: filter.Username,
-		IsActive: filter.IsActive,
-		IsAdmin:  filter.IsAdmin,
+	filterDTO := &dto.FilterDTO{
+		Email:         filter.Email,
+		Username:      filter.Username,
+		IsActive:      filter.IsActive,
+		IsAdmin:       filter.IsAdmin,
+		Reading:       filter.Reading,
+		EmailMatch:    StringMatchFromProto(filter.EmailMatch),
+		UsernameMatch: StringMatchFromProto(filter.UsernameMatch),
+		FirstName:     filter.FirstName,
+		LastName:      filter.LastName,
+		NameMatch:     StringMatchFromProto(filter.NameMatch),
+		IDs:           filter.Ids,
+		ExcludeIDs:    filter.ExcludeIds,
+		CreatedAfter:  optionalTime(filter.CreatedAfter),
+		CreatedBefore: optionalTime(filter.CreatedBefore),
+		UpdatedAfter:  optionalTime(filter.UpdatedAfter),
+		UpdatedBefore: optionalTime(filter.UpdatedBefore),
+	}
+	if filter.Status != nil {
+		if status := UserStatusFromProto(*filter.Status); status != "" {
+			filterDTO.Status = &status
+		}
+	}
+	if filter.Attributes != nil {
+		filterDTO.Attributes = filter.Attributes.AsMap()
+	}
+	if filter.ExpiringWithin != nil {
+		expiringWithin := filter.ExpiringWithin.AsDuration()
+		filterDTO.ExpiringWithin = &expiringWithin
+	}
+
+	return filterDTO
+}
+
+// StringMatchFromProto converts a proto string match enum to its DTO value,
+// or an empty string when unspecified
+func StringMatchFromProto(match pb.StringMatch) string {
+	switch match {
+	case pb.StringMatch_STRING_MATCH_EXACT:
+		return "exact"
+	case pb.StringMatch_STRING_MATCH_PREFIX:
+		return "prefix"
+	case pb.StringMatch_STRING_MATCH_CONTAINS:
+		return "contains"
+	default:
+		return ""
+	}
+}
+
+// UserStatusFromProto converts a proto user status enum to its domain value,
+// or an empty string when unspecified
+func UserStatusFromProto(status pb.UserStatus) string {
+	switch status {
+	case pb.UserStatus_USER_STATUS_ACTIVE:
+		return string(entity.UserStatusActive)
+	case pb.UserStatus_USER_STATUS_INACTIVE:
+		return string(entity.UserStatusInactive)
+	case pb.UserStatus_USER_STATUS_SUSPENDED:
+		return string(entity.UserStatusSuspended)
+	default:
+		return ""
+	}
+}
+
+// PaginationSortToDTO converts the sort parameters of a pagination request to SortDTO
+func PaginationSortToDTO(pagination *commonpb.PaginationRequest) *dto.SortDTO {
+	if pagination == nil || pagination.SortBy == "" {
+		return nil
+	}
+	return &dto.SortDTO{
+		Field: pagination.SortBy,
+		Order: pagination.SortOrder,
+	}
+}
+
+// UserMatchDTOToProto converts a search match DTO to proto
+func UserMatchDTOToProto(match *dto.UserMatchDTO) *pb.UserMatch {
+	highlights := make([]*pb.FieldHighlight, len(match.Highlights))
+	for i, highlight := range match.Highlights {
+		ranges := make([]*pb.TextRange, len(highlight.Ranges))
+		for j, r := range highlight.Ranges {
+			ranges[j] = &pb.TextRange{Start: int32(r.Start), End: int32(r.End)}
+		}
+		highlights[i] = &pb.FieldHighlight{
+			Field:  highlight.Field,
+			Value:  highlight.Value,
+			Ranges: ranges,
+		}
+	}
+	return &pb.UserMatch{
+		UserId:     match.UserID,
+		Score:      match.Score,
+		Highlights: highlights,
+	}
+}
+
+// NameOrderToProto converts a domain name order to its proto enum
+func NameOrderToProto(order string) pb.NameOrder {
+	switch entity.NameOrder(order) {
+	case entity.NameOrderGivenFirst:
+		return pb.NameOrder_NAME_ORDER_GIVEN_FIRST
+	case entity.NameOrderFamilyFirst:
+		return pb.NameOrder_NAME_ORDER_FAMILY_FIRST
+	default:
+		return pb.NameOrder_NAME_ORDER_UNSPECIFIED
+	}
+}
+
+// NameOrderFromProto converts a proto name order enum to its domain value
+func NameOrderFromProto(order pb.NameOrder) string {
+	switch order {
+	case pb.NameOrder_NAME_ORDER_GIVEN_FIRST:
+		return string(entity.NameOrderGivenFirst)
+	case pb.NameOrder_NAME_ORDER_FAMILY_FIRST:
+		return string(entity.NameOrderFamilyFirst)
+	default:
+		return string(entity.NameOrderDefault)
+	}
+}
+
+// optionalTimestamp converts an optional time to a proto timestamp, leaving it unset when nil
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// optionalTime converts an optional proto timestamp to a time, returning nil when unset
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// AttributesToProto converts custom attribute values to a proto Struct
+func AttributesToProto(attributes map[string]interface{}) *structpb.Struct {
+	protoAttributes, err := structpb.NewStruct(attributes)
+	if err != nil {
+		// Values are decoded from JSONB, so this only happens for corrupted data
+		return &structpb.Struct{}
 	}
+	return protoAttributes
 }
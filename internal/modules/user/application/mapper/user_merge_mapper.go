@@ -0,0 +1,52 @@
+package mapper
+
+import (
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// MergeUsersRequestToDTO converts MergeUsersRequest to MergeUsersDTO
+func MergeUsersRequestToDTO(req *pb.MergeUsersRequest) *dto.MergeUsersDTO {
+	resolution := make(map[string]string, len(req.FieldResolution))
+	for path, source := range req.FieldResolution {
+		resolution[path] = MergeFieldSourceFromProto(source)
+	}
+
+	return &dto.MergeUsersDTO{
+		SourceID:        req.SourceId,
+		TargetID:        req.TargetId,
+		FieldResolution: resolution,
+		SourceETag:      req.SourceEtag,
+		TargetETag:      req.TargetEtag,
+		DryRun:          req.DryRun,
+	}
+}
+
+// MergeFieldSourceFromProto converts a proto merge field source to a merge side.
+// Unknown values are passed through and rejected by validation.
+func MergeFieldSourceFromProto(source pb.MergeFieldSource) string {
+	switch source {
+	case pb.MergeFieldSource_MERGE_FIELD_SOURCE_UNSPECIFIED, pb.MergeFieldSource_MERGE_FIELD_SOURCE_TARGET:
+		return string(entity.MergeSideTarget)
+	case pb.MergeFieldSource_MERGE_FIELD_SOURCE_SOURCE:
+		return string(entity.MergeSideSource)
+	default:
+		return source.String()
+	}
+}
+
+// MergeUsersResultDTOToProto converts a MergeUsersResultDTO to a MergeUsersResponse
+func MergeUsersResultDTOToProto(result *dto.MergeUsersResultDTO) *pb.MergeUsersResponse {
+	return &pb.MergeUsersResponse{
+		User:                         UserDTOToProto(result.User),
+		ChangedFields:                &fieldmaskpb.FieldMask{Paths: result.ChangedFields},
+		DryRun:                       result.DryRun,
+		MovedOrganizationMemberships: result.MovedOrganizationMemberships,
+		MovedGroupMemberships:        result.MovedGroupMemberships,
+		MovedLoginEvents:             result.MovedLoginEvents,
+		MovedInvitations:             result.MovedInvitations,
+		MovedPreferences:             result.MovedPreferences,
+	}
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/service"
+	"github.com/google/uuid"
+)
+
+// AttributeUseCase handles custom attribute registry business logic
+type AttributeUseCase struct {
+	attributeRepo    repository.AttributeDefinitionRepository
+	attributeService *service.AttributeService
+	userService      *service.UserService
+}
+
+// NewAttributeUseCase creates a new instance of AttributeUseCase
+func NewAttributeUseCase(attributeRepo repository.AttributeDefinitionRepository, attributeService *service.AttributeService, userService *service.UserService) *AttributeUseCase {
+	return &AttributeUseCase{
+		attributeRepo:    attributeRepo,
+		attributeService: attributeService,
+		userService:      userService,
+	}
+}
+
+// CreateAttributeDefinition registers a new custom attribute
+func (uc *AttributeUseCase) CreateAttributeDefinition(ctx context.Context, createDTO *dto.CreateAttributeDefinitionDTO) (*dto.AttributeDefinitionDTO, error) {
+	// Only administrators change the attribute registry
+	if err := uc.userService.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	definition := createDTO.ToEntity()
+
+	if err := uc.attributeService.CreateDefinition(ctx, definition); err != nil {
+		return nil, fmt.Errorf("failed to create attribute definition: %w", err)
+	}
+
+	return dto.FromAttributeDefinitionEntity(definition), nil
+}
+
+// ListAttributeDefinitions lists all registered custom attributes
+func (uc *AttributeUseCase) ListAttributeDefinitions(ctx context.Context) ([]*dto.AttributeDefinitionDTO, error) {
+	definitions, err := uc.attributeRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attribute definitions: %w", err)
+	}
+
+	definitionDTOs := make([]*dto.AttributeDefinitionDTO, len(definitions))
+	for i, definition := range definitions {
+		definitionDTOs[i] = dto.FromAttributeDefinitionEntity(definition)
+	}
+
+	return definitionDTOs, nil
+}
+
+// UpdateAttributeDefinition updates a custom attribute definition.
+// The key and type are immutable because existing user values depend on them.
+func (uc *AttributeUseCase) UpdateAttributeDefinition(ctx context.Context, updateDTO *dto.UpdateAttributeDefinitionDTO) (*dto.AttributeDefinitionDTO, error) {
+	// Only administrators change the attribute registry
+	if err := uc.userService.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	definition, err := uc.attributeRepo.GetByID(ctx, updateDTO.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attribute definition: %w", err)
+	}
+
+	if updateDTO.DisplayName != nil {
+		definition.DisplayName = *updateDTO.DisplayName
+	}
+	if updateDTO.Required != nil {
+		definition.Required = *updateDTO.Required
+	}
+	if updateDTO.Unique != nil {
+		definition.Unique = *updateDTO.Unique
+	}
+	if updateDTO.Indexed != nil {
+		definition.Indexed = *updateDTO.Indexed
+	}
+	if updateDTO.AllowedValues != nil {
+		definition.AllowedValues = entity.StringList(updateDTO.AllowedValues)
+	}
+
+	if err := uc.attributeService.UpdateDefinition(ctx, definition); err != nil {
+		return nil, fmt.Errorf("failed to update attribute definition: %w", err)
+	}
+
+	return dto.FromAttributeDefinitionEntity(definition), nil
+}
+
+// DeleteAttributeDefinition removes a custom attribute definition
+func (uc *AttributeUseCase) DeleteAttributeDefinition(ctx context.Context, id string) error {
+	// Only administrators change the attribute registry
+	if err := uc.userService.RequireAdmin(ctx); err != nil {
+		return err
+	}
+
+	definitionID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("%w: invalid ID: %v", entity.ErrInvalidAttributeDefinition, err)
+	}
+
+	if err := uc.attributeRepo.Delete(ctx, definitionID); err != nil {
+		return fmt.Errorf("failed to delete attribute definition: %w", err)
+	}
+
+	return nil
+}
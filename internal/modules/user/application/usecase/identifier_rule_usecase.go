@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/service"
+	"github.com/google/uuid"
+)
+
+// IdentifierRuleUseCase handles reserved username and email domain rule business logic
+type IdentifierRuleUseCase struct {
+	ruleRepo    repository.IdentifierRuleRepository
+	ruleService *service.IdentifierRuleService
+	userService *service.UserService
+}
+
+// NewIdentifierRuleUseCase creates a new instance of IdentifierRuleUseCase
+func NewIdentifierRuleUseCase(ruleRepo repository.IdentifierRuleRepository, ruleService *service.IdentifierRuleService, userService *service.UserService) *IdentifierRuleUseCase {
+	return &IdentifierRuleUseCase{
+		ruleRepo:    ruleRepo,
+		ruleService: ruleService,
+		userService: userService,
+	}
+}
+
+// CreateIdentifierRule registers a new identifier rule
+func (uc *IdentifierRuleUseCase) CreateIdentifierRule(ctx context.Context, createDTO *dto.CreateIdentifierRuleDTO) (*dto.IdentifierRuleDTO, error) {
+	// Only administrators change which identifiers may be registered
+	if err := uc.userService.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	rule := createDTO.ToEntity()
+
+	if err := uc.ruleService.CreateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create identifier rule: %w", err)
+	}
+
+	return dto.FromIdentifierRuleEntity(rule), nil
+}
+
+// ListIdentifierRules lists identifier rules, optionally of a single kind
+func (uc *IdentifierRuleUseCase) ListIdentifierRules(ctx context.Context, kind string) ([]*dto.IdentifierRuleDTO, error) {
+	rules, err := uc.ruleRepo.List(ctx, entity.IdentifierRuleKind(kind))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identifier rules: %w", err)
+	}
+
+	ruleDTOs := make([]*dto.IdentifierRuleDTO, len(rules))
+	for i, rule := range rules {
+		ruleDTOs[i] = dto.FromIdentifierRuleEntity(rule)
+	}
+
+	return ruleDTOs, nil
+}
+
+// DeleteIdentifierRule removes an identifier rule
+func (uc *IdentifierRuleUseCase) DeleteIdentifierRule(ctx context.Context, id string) error {
+	// Only administrators change which identifiers may be registered
+	if err := uc.userService.RequireAdmin(ctx); err != nil {
+		return err
+	}
+
+	ruleID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("%w: invalid ID: %v", entity.ErrInvalidIdentifierRule, err)
+	}
+
+	return uc.ruleService.DeleteRule(ctx, ruleID)
+}
+
+// ImportIdentifierRuleFile registers rules of one kind from a file with one value
+// per line, skipping blank lines, # comments, and values that are already registered
+func (uc *IdentifierRuleUseCase) ImportIdentifierRuleFile(ctx context.Context, kind string, path string) (int64, error) {
+	// Only administrators change which identifiers may be registered
+	if err := uc.userService.RequireAdmin(ctx); err != nil {
+		return 0, err
+	}
+
+	return uc.importRuleFile(ctx, kind, path)
+}
+
+// LoadConfiguredRuleFile imports a rule file named in the server configuration
+// like ImportIdentifierRuleFile. It runs at startup, before any caller exists,
+// and must not be reachable from requests.
+func (uc *IdentifierRuleUseCase) LoadConfiguredRuleFile(ctx context.Context, kind string, path string) (int64, error) {
+	return uc.importRuleFile(ctx, kind, path)
+}
+
+// importRuleFile reads a rule file and registers its values
+func (uc *IdentifierRuleUseCase) importRuleFile(ctx context.Context, kind string, path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open identifier rule file: %w", err)
+	}
+	defer file.Close()
+
+	var values []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		values = append(values, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read identifier rule file: %w", err)
+	}
+
+	created, err := uc.ruleService.ImportRules(ctx, entity.IdentifierRuleKind(kind), values)
+	if err != nil {
+		return 0, fmt.Errorf("failed to import %s: %w", path, err)
+	}
+	return created, nil
+}
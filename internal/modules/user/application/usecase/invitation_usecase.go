@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/service"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+)
+
+// InvitationUseCase handles user invitation business logic
+type InvitationUseCase struct {
+	invitationRepo    repository.InvitationRepository
+	invitationService *service.InvitationService
+}
+
+// NewInvitationUseCase creates a new instance of InvitationUseCase
+func NewInvitationUseCase(invitationRepo repository.InvitationRepository, invitationService *service.InvitationService) *InvitationUseCase {
+	return &InvitationUseCase{
+		invitationRepo:    invitationRepo,
+		invitationService: invitationService,
+	}
+}
+
+// InviteUser creates a pending invitation
+func (uc *InvitationUseCase) InviteUser(ctx context.Context, inviteDTO *dto.InviteUserDTO) (*dto.InviteUserResultDTO, error) {
+	invitation := inviteDTO.ToEntity()
+
+	token, err := uc.invitationService.InviteUser(ctx, invitation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to invite user: %w", err)
+	}
+
+	return &dto.InviteUserResultDTO{
+		Invitation: dto.FromInvitationEntity(invitation),
+		Token:      token,
+	}, nil
+}
+
+// AcceptInvitation creates the invited user
+func (uc *InvitationUseCase) AcceptInvitation(ctx context.Context, acceptDTO *dto.AcceptInvitationDTO) (*dto.UserDTO, error) {
+	user := acceptDTO.ToEntity()
+
+	if _, err := uc.invitationService.AcceptInvitation(ctx, acceptDTO.Token, user, acceptDTO.Password); err != nil {
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	return dto.FromEntity(user), nil
+}
+
+// RevokeInvitation revokes a pending invitation
+func (uc *InvitationUseCase) RevokeInvitation(ctx context.Context, id string) error {
+	invitationID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", entity.ErrInvalidInvitationID, err)
+	}
+
+	if err := uc.invitationService.RevokeInvitation(ctx, invitationID); err != nil {
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+
+	return nil
+}
+
+// ListInvitations lists pending invitations of the current tenant with pagination,
+// or those outside any organization when no tenant is set
+func (uc *InvitationUseCase) ListInvitations(ctx context.Context, page, pageSize int) (*dto.ListInvitationsDTO, error) {
+	// Only administrators of the organization see who has been invited to it
+	var organizationID *uuid.UUID
+	if tenantID, ok := tenant.OrganizationIDFromContext(ctx); ok {
+		organizationID = &tenantID
+	}
+	if err := uc.invitationService.RequireOrganizationAdmin(ctx, organizationID); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+	now := time.Now()
+
+	invitations, err := uc.invitationRepo.ListPending(ctx, now, offset, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+
+	totalCount, err := uc.invitationRepo.CountPending(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count invitations: %w", err)
+	}
+
+	invitationDTOs := make([]*dto.InvitationDTO, len(invitations))
+	for i, invitation := range invitations {
+		invitationDTOs[i] = dto.FromInvitationEntity(invitation)
+	}
+
+	return &dto.ListInvitationsDTO{
+		Invitations: invitationDTOs,
+		Page:        page,
+		PageSize:    pageSize,
+		TotalItems:  int(totalCount),
+		TotalPages:  int(math.Ceil(float64(totalCount) / float64(pageSize))),
+	}, nil
+}
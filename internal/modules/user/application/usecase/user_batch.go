@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/google/uuid"
+)
+
+// BatchCreateUsers creates users and returns the outcome of each, in the order
+// of createDTOs. With allOrNothing, either every user is created or none is, and
+// the first failure is returned as an entity.BatchItemError.
+func (uc *UserUseCase) BatchCreateUsers(ctx context.Context, createDTOs []*dto.CreateUserDTO, allOrNothing bool) ([]*dto.BatchUserResultDTO, error) {
+	// Open signup is disabled: only administrators create users directly
+	if err := uc.userService.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	creations := make([]entity.UserCreation, len(createDTOs))
+	for i, createDTO := range createDTOs {
+		creations[i] = entity.UserCreation{User: createDTO.ToEntity(), Password: createDTO.Password}
+	}
+
+	// Create users using domain service (handles validation and password hashing)
+	errs, err := uc.userService.BatchCreateUsers(ctx, creations, allOrNothing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create users: %w", err)
+	}
+
+	results := make([]*dto.BatchUserResultDTO, len(creations))
+	for i, creation := range creations {
+		if errs[i] != nil {
+			results[i] = &dto.BatchUserResultDTO{Err: errs[i]}
+			continue
+		}
+		results[i] = &dto.BatchUserResultDTO{User: dto.FromEntity(creation.User)}
+	}
+	return results, nil
+}
+
+// BatchUpdateUsers applies partial updates to users and returns the outcome of
+// each, in the order of updateDTOs. With allOrNothing, either every user is
+// updated or none is, and the first failure is returned as an entity.BatchItemError.
+func (uc *UserUseCase) BatchUpdateUsers(ctx context.Context, updateDTOs []*dto.UpdateUserDTO, allOrNothing bool) ([]*dto.BatchUserResultDTO, error) {
+	if err := entity.CheckBatchSize(len(updateDTOs)); err != nil {
+		return nil, err
+	}
+
+	// Items with malformed updates fail here; the others go to the domain service
+	results := make([]*dto.BatchUserResultDTO, len(updateDTOs))
+	var modifications []entity.UserModification
+	var indexes []int
+	for i, updateDTO := range updateDTOs {
+		update, expectedVersion, err := userUpdateFromDTO(updateDTO)
+		if err != nil {
+			if allOrNothing {
+				return nil, &entity.BatchItemError{Index: i, Err: err}
+			}
+			results[i] = &dto.BatchUserResultDTO{Err: err}
+			continue
+		}
+		modifications = append(modifications, entity.UserModification{ID: updateDTO.ID, Update: update, ExpectedVersion: expectedVersion})
+		indexes = append(indexes, i)
+	}
+	if len(modifications) == 0 {
+		return results, nil
+	}
+
+	// Update users using domain service (handles validation and version checks)
+	modified, err := uc.userService.BatchUpdateUsers(ctx, modifications, allOrNothing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update users: %w", err)
+	}
+
+	for j, result := range modified {
+		if result.Err != nil {
+			results[indexes[j]] = &dto.BatchUserResultDTO{Err: result.Err}
+			continue
+		}
+		results[indexes[j]] = &dto.BatchUserResultDTO{
+			User:          dto.FromEntity(result.User),
+			ChangedFields: changedFieldPaths(result.Changed),
+		}
+	}
+	return results, nil
+}
+
+// BatchDeleteUsers soft deletes users and returns the outcome of each, in the
+// order of deleteDTOs. With allOrNothing, either every user is deleted or none is,
+// and the first failure is returned as an entity.BatchItemError.
+func (uc *UserUseCase) BatchDeleteUsers(ctx context.Context, deleteDTOs []*dto.DeleteUserDTO, allOrNothing bool) ([]*dto.BatchUserResultDTO, error) {
+	if err := entity.CheckBatchSize(len(deleteDTOs)); err != nil {
+		return nil, err
+	}
+
+	// Items with malformed IDs or etags fail here; the others go to the domain service
+	results := make([]*dto.BatchUserResultDTO, len(deleteDTOs))
+	var deletions []entity.UserDeletion
+	var indexes []int
+	for i, deleteDTO := range deleteDTOs {
+		deletion, err := userDeletionFromDTO(deleteDTO)
+		if err != nil {
+			if allOrNothing {
+				return nil, &entity.BatchItemError{Index: i, Err: err}
+			}
+			results[i] = &dto.BatchUserResultDTO{Err: err}
+			continue
+		}
+		deletions = append(deletions, deletion)
+		indexes = append(indexes, i)
+	}
+	if len(deletions) == 0 {
+		return results, nil
+	}
+
+	errs, err := uc.userService.BatchDeleteUsers(ctx, deletions, allOrNothing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete users: %w", err)
+	}
+
+	for j, err := range errs {
+		results[indexes[j]] = &dto.BatchUserResultDTO{Err: err}
+	}
+	return results, nil
+}
+
+// userDeletionFromDTO converts a delete DTO to a deletion of the user it names
+func userDeletionFromDTO(deleteDTO *dto.DeleteUserDTO) (entity.UserDeletion, error) {
+	userID, err := uuid.Parse(deleteDTO.ID)
+	if err != nil {
+		return entity.UserDeletion{}, fmt.Errorf("%w: %v", entity.ErrInvalidUserID, err)
+	}
+
+	expectedVersion, err := entity.ParseETag(deleteDTO.ETag)
+	if err != nil {
+		return entity.UserDeletion{}, err
+	}
+	return entity.UserDeletion{ID: userID, ExpectedVersion: expectedVersion}, nil
+}
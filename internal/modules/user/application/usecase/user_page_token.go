@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/pagination"
+)
+
+// userPageToken is the signed payload of a ListUsers or SearchUsers page token.
+// It holds the sort key values of the last user of the previous page.
+type userPageToken struct {
+	// Page is the number of the page the token leads to
+	Page int `json:"p"`
+	// Params is the fingerprint of the filter and sort the token was issued for
+	Params string `json:"q"`
+	// After is the last user of the previous page
+	After repository.UserCursor `json:"a"`
+}
+
+// userListParams are the request parameters a page token is bound to
+type userListParams struct {
+	// Query is the search query of SearchUsers tokens
+	Query  string `json:",omitempty"`
+	Filter *dto.FilterDTO
+	Sort   *repository.UserSortOptions
+}
+
+// encodePageToken returns a token for the page after the one ending at the cursor
+func (uc *UserUseCase) encodePageToken(page int, params string, after *repository.UserCursor) (string, error) {
+	token, err := uc.pageTokens.Encode(&userPageToken{
+		Page:   page,
+		Params: params,
+		After:  *after,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode page token: %w", err)
+	}
+	return token, nil
+}
+
+// decodePageToken verifies a page token and checks that it was issued for the same parameters
+func (uc *UserUseCase) decodePageToken(token string, params string) (*userPageToken, error) {
+	var decoded userPageToken
+	if err := uc.pageTokens.Decode(token, &decoded); err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidPageToken, err)
+	}
+	if decoded.Params != params {
+		return nil, fmt.Errorf("%w: filter or sort changed since the token was issued", entity.ErrInvalidPageToken)
+	}
+	if decoded.Page < 2 {
+		return nil, entity.ErrInvalidPageToken
+	}
+	return &decoded, nil
+}
+
+// userListFingerprint returns the fingerprint page tokens of a listing are bound to
+func userListFingerprint(filter *dto.FilterDTO, sort *repository.UserSortOptions) (string, error) {
+	return userSearchFingerprint("", filter, sort)
+}
+
+// userSearchFingerprint returns the fingerprint page tokens of a search are bound to
+func userSearchFingerprint(query string, filter *dto.FilterDTO, sort *repository.UserSortOptions) (string, error) {
+	fingerprint, err := pagination.Fingerprint(&userListParams{Query: query, Filter: filter, Sort: sort})
+	if err != nil {
+		return "", fmt.Errorf("failed to fingerprint list parameters: %w", err)
+	}
+	return fingerprint, nil
+}
@@ -4,30 +4,49 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
+	"time"
 
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/service"
+	"github.com/gigi434/sample-grpc-server/internal/shared/auth"
+	sharedfilter "github.com/gigi434/sample-grpc-server/internal/shared/filter"
+	"github.com/gigi434/sample-grpc-server/internal/shared/pagination"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
 	"github.com/google/uuid"
 )
 
 // UserUseCase handles user-related business logic
 type UserUseCase struct {
-	userRepo    repository.UserRepository
-	userService *service.UserService
+	userRepo     repository.UserRepository
+	userService  *service.UserService
+	loginHistory *service.LoginHistoryService
+	tokenIssuer  *auth.TokenIssuer
+	pageTokens   *pagination.TokenCodec
+	userChanges  repository.UserChangeRepository
 }
 
 // NewUserUseCase creates a new instance of UserUseCase
-func NewUserUseCase(userRepo repository.UserRepository, userService *service.UserService) *UserUseCase {
+func NewUserUseCase(userRepo repository.UserRepository, userService *service.UserService, loginHistory *service.LoginHistoryService, tokenIssuer *auth.TokenIssuer, pageTokens *pagination.TokenCodec, userChanges repository.UserChangeRepository) *UserUseCase {
 	return &UserUseCase{
-		userRepo:    userRepo,
-		userService: userService,
+		userRepo:     userRepo,
+		userService:  userService,
+		loginHistory: loginHistory,
+		tokenIssuer:  tokenIssuer,
+		pageTokens:   pageTokens,
+		userChanges:  userChanges,
 	}
 }
 
 // CreateUser creates a new user
 func (uc *UserUseCase) CreateUser(ctx context.Context, createDTO *dto.CreateUserDTO) (*dto.UserDTO, error) {
+	// Open signup is disabled: only administrators create users directly
+	if err := uc.userService.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
 	// Convert DTO to entity
 	user := createDTO.ToEntity()
 
@@ -45,7 +64,7 @@ func (uc *UserUseCase) GetUser(ctx context.Context, id string) (*dto.UserDTO, er
 	// Parse UUID
 	userID, err := uuid.Parse(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidUserID, err)
 	}
 
 	// Get user from repository
@@ -62,9 +81,17 @@ func (uc *UserUseCase) GetUser(ctx context.Context, id string) (*dto.UserDTO, er
 	return dto.FromEntity(user), nil
 }
 
-// ListUsers retrieves a list of users with pagination
-func (uc *UserUseCase) ListUsers(ctx context.Context, page, pageSize int, filter *dto.FilterDTO) (*dto.ListUsersDTO, error) {
+// ListUsers retrieves a list of users with pagination.
+// Users are sorted by the given comma-separated fields, e.g. "last_name asc, created_at desc",
+// or newest first without a sort.
+// Pages after the first may be requested by number or, without rescanning the
+// skipped users, with the page token returned for the previous page.
+func (uc *UserUseCase) ListUsers(ctx context.Context, pageDTO *dto.PageRequestDTO, filter *dto.FilterDTO, sort *dto.SortDTO) (*dto.ListUsersDTO, error) {
 	// Calculate offset
+	page, pageSize := 1, 10
+	if pageDTO != nil {
+		page, pageSize = pageDTO.Page, pageDTO.PageSize
+	}
 	if page < 1 {
 		page = 1
 	}
@@ -74,38 +101,54 @@ func (uc *UserUseCase) ListUsers(ctx context.Context, page, pageSize int, filter
 	offset := (page - 1) * pageSize
 
 	// Create repository filter
-	var repoFilter *repository.UserFilter
-	if filter != nil {
-		repoFilter = &repository.UserFilter{
-			Email:    filter.Email,
-			Username: filter.Username,
-			IsActive: filter.IsActive,
-			IsAdmin:  filter.IsAdmin,
+	repoFilter, err := uc.userFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create repository sort options
+	sortOptions := &repository.UserSortOptions{Keys: entity.DefaultUserSort}
+	if sort != nil {
+		keys, err := entity.ParseUserSort(sort.Field, sort.Order)
+		if err != nil {
+			return nil, err
 		}
+		sortOptions.Keys = keys
+	}
+
+	// Page tokens only continue listings with the same filter and sort
+	params, err := userListFingerprint(filter, sortOptions)
+	if err != nil {
+		return nil, err
+	}
+	var after *repository.UserCursor
+	if pageDTO != nil && pageDTO.PageToken != "" {
+		token, err := uc.decodePageToken(pageDTO.PageToken, params)
+		if err != nil {
+			return nil, err
+		}
+		page = token.Page
+		after = &token.After
 	}
 
 	// Check if repository supports advanced filtering
 	if advRepo, ok := uc.userRepo.(repository.UserRepositoryWithFilters); ok {
-		// Use advanced filtering
+		// Use advanced filtering; one extra user tells whether there is a next page
 		opts := &repository.ListOptions{
 			Offset: offset,
-			Limit:  pageSize,
+			Limit:  pageSize + 1,
 			Filter: repoFilter,
-			Sort: &repository.UserSortOptions{
-				Field: "created_at",
-				Order: "desc",
-			},
+			Sort:   sortOptions,
+			After:  after,
 		}
 
 		users, err := advRepo.ListWithOptions(ctx, opts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list users: %w", err)
 		}
-
-		// Get total count
-		totalCount, err := advRepo.CountWithFilter(ctx, repoFilter)
-		if err != nil {
-			return nil, fmt.Errorf("failed to count users: %w", err)
+		hasNext := len(users) > pageSize
+		if hasNext {
+			users = users[:pageSize]
 		}
 
 		// Convert entities to DTOs
@@ -114,19 +157,38 @@ func (uc *UserUseCase) ListUsers(ctx context.Context, page, pageSize int, filter
 			userDTOs[i] = dto.FromEntity(user)
 		}
 
-		// Calculate total pages
-		totalPages := int(math.Ceil(float64(totalCount) / float64(pageSize)))
+		listDTO := &dto.ListUsersDTO{
+			Users:       userDTOs,
+			Page:        page,
+			PageSize:    pageSize,
+			HasNext:     hasNext,
+			HasPrevious: page > 1,
+		}
 
-		return &dto.ListUsersDTO{
-			Users:      userDTOs,
-			Page:       page,
-			PageSize:   pageSize,
-			TotalItems: int(totalCount),
-			TotalPages: totalPages,
-		}, nil
+		if hasNext {
+			listDTO.NextPageToken, err = uc.encodePageToken(page+1, params, repository.NewUserCursor(users[len(users)-1]))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Get total count; paging with a token skips it unless asked for
+		if after == nil || pageDTO.IncludeTotal {
+			totalCount, err := advRepo.CountWithFilter(ctx, repoFilter)
+			if err != nil {
+				return nil, fmt.Errorf("failed to count users: %w", err)
+			}
+			listDTO.TotalItems = int(totalCount)
+			listDTO.TotalPages = int(math.Ceil(float64(totalCount) / float64(pageSize)))
+		}
+
+		return listDTO, nil
 	}
 
-	// Fallback to basic listing
+	// Fallback to basic listing, which only supports page numbers
+	if after != nil {
+		return nil, fmt.Errorf("%w: page tokens are not supported", entity.ErrInvalidPageToken)
+	}
 	users, err := uc.userRepo.List(ctx, offset, pageSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users: %w", err)
@@ -148,144 +210,687 @@ func (uc *UserUseCase) ListUsers(ctx context.Context, page, pageSize int, filter
 	totalPages := int(math.Ceil(float64(totalCount) / float64(pageSize)))
 
 	return &dto.ListUsersDTO{
-		Users:      userDTOs,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalItems: int(totalCount),
-		TotalPages: totalPages,
+		Users:       userDTOs,
+		Page:        page,
+		PageSize:    pageSize,
+		TotalItems:  int(totalCount),
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
 	}, nil
 }
 
-// UpdateUser updates an existing user
-func (uc *UserUseCase) UpdateUser(ctx context.Context, updateDTO *dto.UpdateUserDTO) (*dto.UserDTO, error) {
-	// Create an entity with update fields
-	updates := &entity.User{}
+// userFilter converts and validates list filter options, including the custom
+// attributes they match. A nil filter matches every user.
+func (uc *UserUseCase) userFilter(ctx context.Context, filter *dto.FilterDTO) (*repository.UserFilter, error) {
+	if filter == nil {
+		return nil, nil
+	}
+	if err := uc.userService.ValidateAttributeFilter(ctx, filter.Attributes); err != nil {
+		return nil, err
+	}
+	return userFilterFromDTO(filter)
+}
+
+// userFilterFromDTO converts and validates list filter options
+func userFilterFromDTO(filter *dto.FilterDTO) (*repository.UserFilter, error) {
+	repoFilter := &repository.UserFilter{
+		IsActive:   filter.IsActive,
+		IsAdmin:    filter.IsAdmin,
+		Attributes: entity.Attributes(filter.Attributes),
+	}
+
+	// String filters
+	var err error
+	if repoFilter.Email, err = stringFilter(filter.Email, filter.EmailMatch); err != nil {
+		return nil, err
+	}
+	if repoFilter.Username, err = stringFilter(filter.Username, filter.UsernameMatch); err != nil {
+		return nil, err
+	}
+	if repoFilter.FirstName, err = stringFilter(filter.FirstName, filter.NameMatch); err != nil {
+		return nil, err
+	}
+	if repoFilter.LastName, err = stringFilter(filter.LastName, filter.NameMatch); err != nil {
+		return nil, err
+	}
+
+	if filter.Status != nil {
+		status := entity.UserStatus(*filter.Status)
+		switch status {
+		case entity.UserStatusActive, entity.UserStatusInactive:
+			repoFilter.Status = &status
+		case entity.UserStatusSuspended:
+			// Listing never returns deleted users, so none of them can match
+			return nil, fmt.Errorf("%w: status %q is not supported", entity.ErrInvalidFilter, *filter.Status)
+		default:
+			return nil, fmt.Errorf("%w: unknown status %q", entity.ErrInvalidFilter, *filter.Status)
+		}
+	}
+
+	// ID sets
+	if repoFilter.IDs, err = parseUserIDs(filter.IDs); err != nil {
+		return nil, err
+	}
+	if repoFilter.ExcludeIDs, err = parseUserIDs(filter.ExcludeIDs); err != nil {
+		return nil, err
+	}
+
+	// Time ranges
+	if repoFilter.CreatedAt, err = timeRange(filter.CreatedAfter, filter.CreatedBefore); err != nil {
+		return nil, err
+	}
+	if repoFilter.UpdatedAt, err = timeRange(filter.UpdatedAfter, filter.UpdatedBefore); err != nil {
+		return nil, err
+	}
+
+	// Readings are stored as katakana, so hiragana queries match too
+	if filter.Reading != nil {
+		reading := entity.NormalizeKana(*filter.Reading)
+		repoFilter.Reading = &reading
+	}
+
+	if filter.ExpiringWithin != nil {
+		if *filter.ExpiringWithin <= 0 {
+			return nil, fmt.Errorf("%w: expiring_within must be positive", entity.ErrInvalidTimeRange)
+		}
+		expiringBefore := time.Now().Add(*filter.ExpiringWithin)
+		repoFilter.ExpiringBefore = &expiringBefore
+	}
+
+	// Filter expressions report errors with their position in the expression
+	expr, err := sharedfilter.Parse(filter.Expression)
+	if err == nil {
+		err = entity.UserFilterSchema().Check(expr)
+	}
+	if err != nil {
+		return nil, &entity.FieldViolation{Field: "filter_expression", Description: err.Error(), Err: entity.ErrInvalidFilter}
+	}
+	repoFilter.Expression = expr
+
+	return repoFilter, nil
+}
+
+// stringFilter converts a string filter value and its match mode, which defaults to contains
+func stringFilter(value *string, match string) (*repository.StringFilter, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch repository.StringMatch(match) {
+	case "":
+		return &repository.StringFilter{Value: *value, Match: repository.StringMatchContains}, nil
+	case repository.StringMatchExact, repository.StringMatchPrefix, repository.StringMatchContains:
+		return &repository.StringFilter{Value: *value, Match: repository.StringMatch(match)}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown match mode %q", entity.ErrInvalidFilter, match)
+	}
+}
+
+// parseUserIDs parses a set of user IDs
+func parseUserIDs(ids []string) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	userIDs := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", entity.ErrInvalidUserID, id, err)
+		}
+		userIDs[i] = userID
+	}
+	return userIDs, nil
+}
+
+// timeRange converts the bounds of a time range, rejecting ranges that end before they start
+func timeRange(from, to *time.Time) (*repository.TimeRange, error) {
+	if from == nil && to == nil {
+		return nil, nil
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, fmt.Errorf("%w: start time must be before end time", entity.ErrInvalidTimeRange)
+	}
+	return &repository.TimeRange{From: from, To: to}, nil
+}
+
+// UpdateUser applies a partial update and returns the updated user along with
+// the paths of the fields that changed
+func (uc *UserUseCase) UpdateUser(ctx context.Context, updateDTO *dto.UpdateUserDTO) (*dto.UserDTO, []string, error) {
+	update, expectedVersion, err := userUpdateFromDTO(updateDTO)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Update user using domain service (handles validation and version checks)
+	user, changed, err := uc.userService.UpdateUser(ctx, updateDTO.ID, update, expectedVersion)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// Convert entity to DTO
+	return dto.FromEntity(user), changedFieldPaths(changed), nil
+}
+
+// userUpdateFromDTO converts an update DTO to an update with the selected fields,
+// where nil values clear the field, and the version its etag expects
+func userUpdateFromDTO(updateDTO *dto.UpdateUserDTO) (*entity.UserUpdate, int64, error) {
+	update := &entity.UserUpdate{}
+	for _, path := range updateDTO.Fields {
+		field := entity.UserField(path)
+		if !field.IsMutable() {
+			return nil, 0, fmt.Errorf("%w: unknown or immutable field %q", entity.ErrInvalidUpdateMask, path)
+		}
+		update.Fields = append(update.Fields, field)
+	}
 
 	if updateDTO.Email != nil {
-		updates.Email = *updateDTO.Email
+		update.Email = *updateDTO.Email
 	}
 	if updateDTO.Username != nil {
-		updates.Username = *updateDTO.Username
+		update.Username = *updateDTO.Username
 	}
 	if updateDTO.FirstName != nil {
-		updates.FirstName = *updateDTO.FirstName
+		update.FirstName = *updateDTO.FirstName
 	}
 	if updateDTO.LastName != nil {
-		updates.LastName = *updateDTO.LastName
+		update.LastName = *updateDTO.LastName
+	}
+	if updateDTO.FamilyNameKana != nil {
+		update.FamilyNameKana = *updateDTO.FamilyNameKana
+	}
+	if updateDTO.GivenNameKana != nil {
+		update.GivenNameKana = *updateDTO.GivenNameKana
+	}
+	if updateDTO.NameOrder != nil {
+		update.NameOrder = entity.NameOrder(*updateDTO.NameOrder)
+	}
+	if updateDTO.Locale != nil {
+		update.Locale = *updateDTO.Locale
 	}
 	if updateDTO.IsActive != nil {
-		updates.IsActive = *updateDTO.IsActive
+		update.IsActive = *updateDTO.IsActive
 	}
 	if updateDTO.IsAdmin != nil {
-		updates.IsAdmin = *updateDTO.IsAdmin
+		update.IsAdmin = *updateDTO.IsAdmin
 	}
-
-	// Update user using domain service (handles validation)
-	if err := uc.userService.UpdateUser(ctx, updateDTO.ID, updates); err != nil {
-		return nil, fmt.Errorf("failed to update user: %w", err)
+	update.ValidFrom = updateDTO.ValidFrom
+	update.ValidUntil = updateDTO.ValidUntil
+	if updateDTO.Attributes != nil {
+		update.Attributes = entity.Attributes(updateDTO.Attributes)
 	}
 
-	// Get updated user
-	user, err := uc.userRepo.GetByID(ctx, updateDTO.ID)
+	expectedVersion, err := entity.ParseETag(updateDTO.ETag)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get updated user: %w", err)
+		return nil, 0, err
 	}
+	return update, expectedVersion, nil
+}
 
-	// Convert entity to DTO
-	return dto.FromEntity(user), nil
+// changedFieldPaths returns the paths of changed fields
+func changedFieldPaths(changed []entity.UserField) []string {
+	paths := make([]string, 0, len(changed))
+	for _, field := range changed {
+		paths = append(paths, string(field))
+	}
+	return paths
 }
 
-// DeleteUser deletes a user (soft delete by default)
-func (uc *UserUseCase) DeleteUser(ctx context.Context, id string, hardDelete bool) error {
+// DeleteUser deletes a user (soft delete by default).
+// A non-empty etag rejects the delete if the user has changed since it was read.
+func (uc *UserUseCase) DeleteUser(ctx context.Context, id string, hardDelete bool, etag string) error {
 	// Parse UUID
 	userID, err := uuid.Parse(id)
 	if err != nil {
-		return fmt.Errorf("invalid user ID: %w", err)
+		return fmt.Errorf("%w: %v", entity.ErrInvalidUserID, err)
 	}
 
-	// Check if user exists
-	exists, err := uc.userRepo.Exists(ctx, userID)
+	expectedVersion, err := entity.ParseETag(etag)
 	if err != nil {
-		return fmt.Errorf("failed to check user existence: %w", err)
+		return err
 	}
 
-	if !exists {
-		return entity.ErrUserNotFound
+	// Check if user exists and has the expected version
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if err := user.CheckVersion(expectedVersion); err != nil {
+		return err
 	}
 
 	// Delete user
-	if err := uc.userRepo.Delete(ctx, userID); err != nil {
+	if err := uc.userRepo.Delete(ctx, userID, expectedVersion); err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 
 	return nil
 }
 
-// BatchGetUsers retrieves multiple users by IDs
-func (uc *UserUseCase) BatchGetUsers(ctx context.Context, ids []string) (map[string]*dto.UserDTO, []string, error) {
-	users := make(map[string]*dto.UserDTO)
-	notFound := make([]string, 0)
+// MergeUsers merges the source user into the target user, or previews the merge for a dry run
+func (uc *UserUseCase) MergeUsers(ctx context.Context, mergeDTO *dto.MergeUsersDTO) (*dto.MergeUsersResultDTO, error) {
+	// Only administrators merge users, dry runs included, as the preview reveals both users
+	if err := uc.userService.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	// Parse UUIDs
+	sourceID, err := uuid.Parse(mergeDTO.SourceID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: source: %v", entity.ErrInvalidUserID, err)
+	}
+	targetID, err := uuid.Parse(mergeDTO.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: target: %v", entity.ErrInvalidUserID, err)
+	}
+
+	sourceVersion, err := entity.ParseETag(mergeDTO.SourceETag)
+	if err != nil {
+		return nil, err
+	}
+	targetVersion, err := entity.ParseETag(mergeDTO.TargetETag)
+	if err != nil {
+		return nil, err
+	}
+
+	resolution := make(entity.FieldResolution, len(mergeDTO.FieldResolution))
+	for path, side := range mergeDTO.FieldResolution {
+		resolution[entity.UserField(path)] = entity.MergeSide(side)
+	}
+
+	// Merge users using domain service (handles validation and version checks)
+	merge, moved, err := uc.userService.MergeUsers(ctx, sourceID, targetID, resolution, sourceVersion, targetVersion, mergeDTO.DryRun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge users: %w", err)
+	}
+
+	return dto.FromMergeResult(merge, moved, mergeDTO.DryRun), nil
+}
+
+// BatchGetUsers retrieves multiple users by IDs with a single query and returns
+// the outcome for each requested ID, in request order. Malformed IDs and missing
+// users fail individually, while a failure to query the users fails the call.
+func (uc *UserUseCase) BatchGetUsers(ctx context.Context, ids []string) ([]*dto.BatchGetUserResultDTO, error) {
+	results := make([]*dto.BatchGetUserResultDTO, len(ids))
+	userIDs := make([]uuid.UUID, len(ids))
+	valid := make([]uuid.UUID, 0, len(ids))
+	for i, id := range ids {
+		results[i] = &dto.BatchGetUserResultDTO{ID: id}
 
-	for _, id := range ids {
 		// Parse UUID
 		userID, err := uuid.Parse(id)
 		if err != nil {
-			notFound = append(notFound, id)
+			results[i].Err = fmt.Errorf("%w: %v", entity.ErrInvalidUserID, err)
 			continue
 		}
+		userIDs[i] = userID
+		valid = append(valid, userID)
+	}
+
+	// Get users from repository
+	users, err := uc.userRepo.GetByIDs(ctx, valid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	byID := make(map[uuid.UUID]*entity.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
 
-		// Get user from repository
-		user, err := uc.userRepo.GetByID(ctx, userID)
-		if err != nil || user == nil {
-			notFound = append(notFound, id)
+	for i, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		user, ok := byID[userIDs[i]]
+		if !ok {
+			result.Err = entity.ErrUserNotFound
 			continue
 		}
 
 		// Convert entity to DTO
-		users[id] = dto.FromEntity(user)
+		result.User = dto.FromEntity(user)
 	}
 
-	return users, notFound, nil
+	return results, nil
 }
 
-// SearchUsers searches users by query
-func (uc *UserUseCase) SearchUsers(ctx context.Context, searchDTO *dto.SearchUsersDTO) (*dto.ListUsersDTO, error) {
-	// For now, we'll use the list functionality with filters
-	// In a real implementation, you might want to use a search engine like Elasticsearch
+// SearchUsers searches users by email, username, first and last name, and kana
+// queries by name reading. Matches are ordered by relevance unless a sort is given,
+// and report their score and the fields they matched.
+func (uc *UserUseCase) SearchUsers(ctx context.Context, searchDTO *dto.SearchUsersDTO) (*dto.SearchUsersResultDTO, error) {
+	advRepo, ok := uc.userRepo.(repository.UserRepositoryWithFilters)
+	if !ok {
+		return nil, fmt.Errorf("user search is not supported by the repository")
+	}
 
-	// Create filter based on search query
-	filter := searchDTO.Filter
-	if filter == nil {
-		filter = &dto.FilterDTO{}
+	page, pageSize := 1, 10
+	pageDTO := searchDTO.Pagination
+	if pageDTO != nil {
+		page, pageSize = pageDTO.Page, pageDTO.PageSize
+	}
+	if page < 1 {
+		page = 1
 	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	query := strings.TrimSpace(searchDTO.Query)
 
-	// Add search query to filter (search in email and username)
-	if searchDTO.Query != "" {
-		filter.Email = &searchDTO.Query
-		// Note: In a real implementation, you'd want to search across multiple fields
+	// Create repository filter
+	repoFilter, err := uc.userFilter(ctx, searchDTO.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a sort, matches are ordered by relevance and an empty query lists the newest users first
+	var sortOptions *repository.UserSortOptions
+	if searchDTO.Sort != nil {
+		keys, err := entity.ParseUserSort(searchDTO.Sort.Field, searchDTO.Sort.Order)
+		if err != nil {
+			return nil, err
+		}
+		sortOptions = &repository.UserSortOptions{Keys: keys}
+	} else if query == "" {
+		sortOptions = &repository.UserSortOptions{Keys: entity.DefaultUserSort}
+	}
+
+	// Page tokens only continue searches with the same query, filter and sort
+	params, err := userSearchFingerprint(query, searchDTO.Filter, sortOptions)
+	if err != nil {
+		return nil, err
+	}
+	var after *repository.UserCursor
+	if pageDTO != nil && pageDTO.PageToken != "" {
+		token, err := uc.decodePageToken(pageDTO.PageToken, params)
+		if err != nil {
+			return nil, err
+		}
+		page = token.Page
+		after = &token.After
+	}
+
+	// One extra match tells whether there is a next page
+	results, err := advRepo.Search(ctx, &repository.UserSearchOptions{
+		Query:  query,
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize + 1,
+		Filter: repoFilter,
+		Sort:   sortOptions,
+		After:  after,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	hasNext := len(results) > pageSize
+	if hasNext {
+		results = results[:pageSize]
+	}
+
+	resultDTO := &dto.SearchUsersResultDTO{
+		ListUsersDTO: dto.ListUsersDTO{
+			Users:       make([]*dto.UserDTO, len(results)),
+			Page:        page,
+			PageSize:    pageSize,
+			HasNext:     hasNext,
+			HasPrevious: page > 1,
+		},
+		Matches: make([]*dto.UserMatchDTO, len(results)),
+	}
+	for i, result := range results {
+		resultDTO.Users[i] = dto.FromEntity(result.User)
+		resultDTO.Matches[i] = userMatchDTO(result, query)
+	}
+
+	if hasNext {
+		last := results[len(results)-1]
+		cursor := repository.NewUserCursor(last.User)
+		cursor.Score = last.Score
+		resultDTO.NextPageToken, err = uc.encodePageToken(page+1, params, cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Searches always report the number of matches
+	totalMatches, err := advRepo.CountSearch(ctx, query, repoFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count search matches: %w", err)
+	}
+	resultDTO.TotalMatches = int(totalMatches)
+	resultDTO.TotalItems = int(totalMatches)
+	resultDTO.TotalPages = int(math.Ceil(float64(totalMatches) / float64(pageSize)))
+
+	return resultDTO, nil
+}
+
+// userMatchDTO converts a search result to its relevance and highlights
+func userMatchDTO(result *repository.UserSearchResult, query string) *dto.UserMatchDTO {
+	match := &dto.UserMatchDTO{
+		UserID: result.User.ID.String(),
+		Score:  result.Score,
+	}
+	for _, highlight := range entity.HighlightUserMatches(result.User, query, result.MatchedFields) {
+		highlightDTO := &dto.HighlightDTO{
+			Field: string(highlight.Field),
+			Value: highlight.Value,
+		}
+		for _, r := range highlight.Ranges {
+			highlightDTO.Ranges = append(highlightDTO.Ranges, dto.TextRangeDTO{Start: r.Start, End: r.End})
+		}
+		match.Highlights = append(match.Highlights, highlightDTO)
 	}
+	return match
+}
+
+// Export batch sizes
+const (
+	defaultExportBatchSize = 500
+	maxExportBatchSize     = 1000
+)
+
+// exportSort walks users oldest first, so users created during an export come last
+var exportSort = []entity.UserSortKey{{Field: entity.UserSortCreatedAt}}
+
+// ExportUsers passes every user matching the filter to emit, oldest first, in
+// batches of up to batchSize users. Batches are read with a keyset cursor one at
+// a time, so memory use does not grow with the number of users. The export stops
+// at the first error returned by emit or when ctx is done.
+func (uc *UserUseCase) ExportUsers(ctx context.Context, filter *dto.FilterDTO, batchSize int, emit func(users []*dto.UserDTO) error) error {
+	// Only administrators export the user directory
+	if err := uc.userService.RequireAdmin(ctx); err != nil {
+		return err
+	}
+
+	advRepo, ok := uc.userRepo.(repository.UserRepositoryWithFilters)
+	if !ok {
+		return fmt.Errorf("user export is not supported by the repository")
+	}
+
+	if batchSize < 1 {
+		batchSize = defaultExportBatchSize
+	}
+	if batchSize > maxExportBatchSize {
+		batchSize = maxExportBatchSize
+	}
+
+	// Create repository filter
+	repoFilter, err := uc.userFilter(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	opts := &repository.ListOptions{
+		Limit:  batchSize,
+		Filter: repoFilter,
+		Sort:   &repository.UserSortOptions{Keys: exportSort},
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		users, err := advRepo.ListWithOptions(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to export users: %w", err)
+		}
+		if len(users) == 0 {
+			return nil
+		}
+
+		// Convert entities to DTOs
+		userDTOs := make([]*dto.UserDTO, len(users))
+		for i, user := range users {
+			userDTOs[i] = dto.FromEntity(user)
+		}
+		if err := emit(userDTOs); err != nil {
+			return err
+		}
 
-	return uc.ListUsers(ctx, searchDTO.Page, searchDTO.PageSize, filter)
+		if len(users) < batchSize {
+			return nil
+		}
+		opts.After = repository.NewUserCursor(users[len(users)-1])
+	}
 }
 
 // ChangePassword changes a user's password
 func (uc *UserUseCase) ChangePassword(ctx context.Context, changeDTO *dto.ChangePasswordDTO) error {
+	expectedVersion, err := entity.ParseETag(changeDTO.ETag)
+	if err != nil {
+		return err
+	}
+
 	// Use domain service to change password
-	if err := uc.userService.ChangePassword(ctx, changeDTO.UserID, changeDTO.OldPassword, changeDTO.NewPassword); err != nil {
+	if err := uc.userService.ChangePassword(ctx, changeDTO.UserID, changeDTO.OldPassword, changeDTO.NewPassword, expectedVersion); err != nil {
 		return fmt.Errorf("failed to change password: %w", err)
 	}
 
 	return nil
 }
 
-// AuthenticateUser authenticates a user with email/username and password
-func (uc *UserUseCase) AuthenticateUser(ctx context.Context, authDTO *dto.AuthenticateDTO) (*dto.UserDTO, error) {
+// AuthenticateUser authenticates a user with email/username and password and issues
+// a token. Users who must change their password receive a token that only permits that.
+func (uc *UserUseCase) AuthenticateUser(ctx context.Context, authDTO *dto.AuthenticateDTO) (*dto.AuthenticationDTO, error) {
 	// Use domain service to authenticate
-	user, err := uc.userService.Authenticate(ctx, authDTO.Identifier, authDTO.Password)
+	attempt := entity.LoginAttempt{
+		Method:    entity.LoginMethodPassword,
+		IPAddress: authDTO.IPAddress,
+		UserAgent: authDTO.UserAgent,
+	}
+	user, err := uc.userService.Authenticate(ctx, authDTO.Identifier, authDTO.Password, attempt)
 	if err != nil {
 		return nil, fmt.Errorf("authentication failed: %w", err)
 	}
 
+	// Issue a token limited to the password change when one is required
+	now := time.Now()
+	scope := auth.ScopeFull
+	passwordChangeRequired := uc.userService.PasswordChangeRequired(user, now)
+	if passwordChangeRequired {
+		scope = auth.ScopePasswordChange
+	}
+	// Bind the token to the organization the user signed in to
+	var organizationID *uuid.UUID
+	if currentOrganizationID, ok := tenant.OrganizationIDFromContext(ctx); ok {
+		organizationID = &currentOrganizationID
+	}
+	token, claims, err := uc.tokenIssuer.Issue(user.ID, organizationID, scope, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue token: %w", err)
+	}
+
 	// Convert entity to DTO
-	return dto.FromEntity(user), nil
+	return &dto.AuthenticationDTO{
+		User:                   dto.FromEntity(user),
+		Token:                  token,
+		TokenExpiresAt:         claims.Expiry(),
+		PasswordChangeRequired: passwordChangeRequired,
+	}, nil
+}
+
+// SetTemporaryPassword replaces a user's password with a generated one-time password
+// that must be changed on next sign-in.
+// A non-empty etag rejects the reset if the user has changed since it was read.
+func (uc *UserUseCase) SetTemporaryPassword(ctx context.Context, id string, etag string) (*dto.TemporaryPasswordDTO, error) {
+	// Only administrators reset other users' passwords
+	if err := uc.userService.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	// Parse UUID
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidUserID, err)
+	}
+
+	expectedVersion, err := entity.ParseETag(etag)
+	if err != nil {
+		return nil, err
+	}
+
+	user, temporaryPassword, err := uc.userService.SetTemporaryPassword(ctx, userID, expectedVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set temporary password: %w", err)
+	}
+
+	// Convert entity to DTO
+	return &dto.TemporaryPasswordDTO{
+		User:              dto.FromEntity(user),
+		TemporaryPassword: temporaryPassword,
+	}, nil
+}
+
+// ListLoginEvents lists the sign-in attempts of a user within an optional time range, newest first
+func (uc *UserUseCase) ListLoginEvents(ctx context.Context, id string, from, to *time.Time, page, pageSize int) (*dto.ListLoginEventsDTO, error) {
+	// Parse UUID
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidUserID, err)
+	}
+
+	// Users see their own sign-in history; administrators see everyone's
+	if err := uc.userService.RequireSelfOrAdmin(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// Only users visible to the caller have a visible login history
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, entity.ErrUserNotFound
+	}
+
+	// Calculate offset
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	filter := &repository.LoginEventFilter{
+		UserID: userID,
+		From:   from,
+		To:     to,
+	}
+	events, totalCount, err := uc.loginHistory.ListEvents(ctx, filter, offset, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert entities to DTOs
+	eventDTOs := make([]*dto.LoginEventDTO, len(events))
+	for i, event := range events {
+		eventDTOs[i] = dto.FromLoginEventEntity(event)
+	}
+
+	return &dto.ListLoginEventsDTO{
+		Events:     eventDTOs,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: int(totalCount),
+		TotalPages: int(math.Ceil(float64(totalCount) / float64(pageSize))),
+	}, nil
 }
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+)
+
+const (
+	// watchBatchSize is the most changes passed to emit at once
+	watchBatchSize = 100
+
+	// watchPollInterval is how often the change log is read when no commit
+	// notification arrives, which covers notifications lost while reconnecting
+	watchPollInterval = 5 * time.Second
+
+	// watchCheckpointInterval is how often a watch without matching changes passes
+	// a new resume token, so that resuming it does not read the skipped changes again
+	watchCheckpointInterval = 30 * time.Second
+)
+
+// userResumeToken is the signed payload of a WatchUsers resume token. Page tokens
+// are signed with the same codec, so the position has a key they do not use.
+type userResumeToken struct {
+	// After is the position of the watch in the user change log
+	After *repository.UserChangePosition `json:"w"`
+}
+
+// WatchUsers passes the changes of users matching the filter to emit as they
+// commit, along with a token that resumes the watch after them. A change matches
+// when the user matches the filter before or after it. Without a resume token,
+// the watch starts with the changes committed from now on, and emit is first
+// called without changes to hand out the token of that starting point. emit is
+// also called without changes when no change matched for a while, to advance the
+// token. A resume token expires once the changes after it are pruned from the
+// log. The watch runs until emit returns an error or ctx is done.
+func (uc *UserUseCase) WatchUsers(ctx context.Context, filter *dto.FilterDTO, resumeToken string, emit func(changes []*dto.UserChangeDTO, resumeToken string) error) error {
+	// Create repository filter
+	repoFilter, err := uc.userFilter(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	// Subscribe before reading, so that no commit goes unnoticed in between
+	notify := uc.userChanges.Subscribe(ctx)
+
+	var position repository.UserChangePosition
+	var emitted time.Time
+	if resumeToken != "" {
+		if position, err = uc.decodeResumeToken(resumeToken); err != nil {
+			return err
+		}
+		pruned, err := uc.userChanges.PrunedPosition(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get change log position: %w", err)
+		}
+		if position.Before(pruned) {
+			return entity.ErrResumeTokenExpired
+		}
+	} else {
+		if position, err = uc.userChanges.CurrentPosition(ctx); err != nil {
+			return fmt.Errorf("failed to get change log position: %w", err)
+		}
+		token, err := uc.encodeResumeToken(position)
+		if err != nil {
+			return err
+		}
+		if err := emit(nil, token); err != nil {
+			return err
+		}
+		emitted = time.Now()
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		changes, next, err := uc.userChanges.ListAfter(ctx, position, repoFilter, watchBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to watch users: %w", err)
+		}
+
+		if len(changes) > 0 || (next != position && time.Since(emitted) >= watchCheckpointInterval) {
+			token, err := uc.encodeResumeToken(next)
+			if err != nil {
+				return err
+			}
+
+			// Convert entities to DTOs
+			changeDTOs := make([]*dto.UserChangeDTO, len(changes))
+			for i, change := range changes {
+				changeDTOs[i] = dto.FromUserChangeEntity(change)
+			}
+			if err := emit(changeDTOs, token); err != nil {
+				return err
+			}
+			emitted = time.Now()
+		}
+		position = next
+
+		// A full batch may be followed by more changes that are ready now
+		if len(changes) == watchBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-notify:
+		case <-time.After(watchPollInterval):
+		}
+	}
+}
+
+// encodeResumeToken returns a token resuming a watch after the position
+func (uc *UserUseCase) encodeResumeToken(after repository.UserChangePosition) (string, error) {
+	token, err := uc.pageTokens.Encode(&userResumeToken{After: &after})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode resume token: %w", err)
+	}
+	return token, nil
+}
+
+// decodeResumeToken verifies a resume token and returns the position it resumes after
+func (uc *UserUseCase) decodeResumeToken(token string) (repository.UserChangePosition, error) {
+	var decoded userResumeToken
+	if err := uc.pageTokens.Decode(token, &decoded); err != nil {
+		return repository.UserChangePosition{}, fmt.Errorf("%w: %v", entity.ErrInvalidResumeToken, err)
+	}
+	if decoded.After == nil {
+		return repository.UserChangePosition{}, entity.ErrInvalidResumeToken
+	}
+	return *decoded.After, nil
+}
@@ -0,0 +1,182 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttributeType represents the value type of a custom user attribute
+type AttributeType string
+
+const (
+	AttributeTypeString  AttributeType = "string"
+	AttributeTypeNumber  AttributeType = "number"
+	AttributeTypeBoolean AttributeType = "boolean"
+)
+
+// IsValid reports whether the attribute type is supported
+func (t AttributeType) IsValid() bool {
+	switch t {
+	case AttributeTypeString, AttributeTypeNumber, AttributeTypeBoolean:
+		return true
+	}
+	return false
+}
+
+// AttributeDefinition declares a custom attribute that can be stored on users
+type AttributeDefinition struct {
+	ID            uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Key           string        `gorm:"type:varchar(64);uniqueIndex;not null" json:"key"`
+	DisplayName   string        `gorm:"type:varchar(255);not null" json:"display_name"`
+	Type          AttributeType `gorm:"type:varchar(20);not null" json:"type"`
+	Required      bool          `gorm:"default:false" json:"required"`
+	Unique        bool          `gorm:"default:false" json:"unique"`
+	Indexed       bool          `gorm:"default:false" json:"indexed"`
+	AllowedValues StringList    `gorm:"type:jsonb;not null;default:'[]'" json:"allowed_values"`
+	CreatedAt     time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for AttributeDefinition entity
+func (AttributeDefinition) TableName() string {
+	return "attribute_definitions"
+}
+
+var attributeKeyRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// MaxUniqueAttributeKeyLength is the longest key of a unique attribute. The key
+// names the database indexes enforcing its uniqueness, whose names are limited.
+const MaxUniqueAttributeKeyLength = 40
+
+// Validate validates the attribute definition
+func (d *AttributeDefinition) Validate() error {
+	if !attributeKeyRegex.MatchString(d.Key) {
+		return fmt.Errorf("%w: key must start with a lowercase letter and contain only lowercase letters, numbers, and underscores", ErrInvalidAttributeDefinition)
+	}
+	if !d.Type.IsValid() {
+		return fmt.Errorf("%w: unsupported type %q", ErrInvalidAttributeDefinition, d.Type)
+	}
+	if d.Unique && len(d.Key) > MaxUniqueAttributeKeyLength {
+		return fmt.Errorf("%w: keys of unique attributes must be at most %d characters", ErrInvalidAttributeDefinition, MaxUniqueAttributeKeyLength)
+	}
+	if len(d.AllowedValues) > 0 && d.Type != AttributeTypeString {
+		return fmt.Errorf("%w: allowed values are only supported for string attributes", ErrInvalidAttributeDefinition)
+	}
+	return nil
+}
+
+// ValidateValue checks that a value conforms to the definition's type and allowed values
+func (d *AttributeDefinition) ValidateValue(value interface{}) error {
+	switch d.Type {
+	case AttributeTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: %s must be a string", ErrInvalidAttribute, d.Key)
+		}
+		if len(d.AllowedValues) > 0 && !d.AllowedValues.Contains(s) {
+			return fmt.Errorf("%w: %s must be one of %v", ErrInvalidAttribute, d.Key, []string(d.AllowedValues))
+		}
+	case AttributeTypeNumber:
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%w: %s must be a number", ErrInvalidAttribute, d.Key)
+		}
+	case AttributeTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%w: %s must be a boolean", ErrInvalidAttribute, d.Key)
+		}
+	default:
+		return fmt.Errorf("%w: %s has unsupported type %q", ErrInvalidAttribute, d.Key, d.Type)
+	}
+	return nil
+}
+
+// Attributes holds custom attribute values stored as JSONB
+type Attributes map[string]interface{}
+
+// Value implements driver.Valuer
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (a *Attributes) Scan(value interface{}) error {
+	data, err := jsonBytes(value)
+	if err != nil {
+		return err
+	}
+	result := Attributes{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &result); err != nil {
+			return err
+		}
+	}
+	*a = result
+	return nil
+}
+
+// StringList holds a list of strings stored as JSONB
+type StringList []string
+
+// Contains reports whether the list contains the given value
+func (l StringList) Contains(value string) bool {
+	for _, v := range l {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Value implements driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (l *StringList) Scan(value interface{}) error {
+	data, err := jsonBytes(value)
+	if err != nil {
+		return err
+	}
+	result := StringList{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &result); err != nil {
+			return err
+		}
+	}
+	*l = result
+	return nil
+}
+
+// jsonBytes converts a database value into raw JSON bytes
+func jsonBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, fmt.Errorf("unsupported JSON value type %T", value)
+	}
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditAction identifies what an audit event records
+type AuditAction string
+
+const (
+	// AuditActionUserMerged records a user being merged into another user
+	AuditActionUserMerged AuditAction = "user.merged"
+)
+
+// AuditEvent records an administrative change to a user. SubjectID is the user
+// the change was applied to; Details holds action-specific data.
+type AuditEvent struct {
+	ID        uuid.UUID   `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Action    AuditAction `gorm:"type:varchar(64);not null;index" json:"action"`
+	SubjectID uuid.UUID   `gorm:"type:uuid;not null;index:idx_audit_events_subject_created,priority:1" json:"subject_id"`
+	Details   Attributes  `gorm:"type:jsonb;not null;default:'{}'" json:"details"`
+	CreatedAt time.Time   `gorm:"autoCreateTime;index:idx_audit_events_subject_created,priority:2" json:"created_at"`
+}
+
+// TableName specifies the table name for AuditEvent entity
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// BeforeCreate hook to set UUID before creating
+func (e *AuditEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// confusables maps lowercase Greek and Cyrillic letters to the Latin letters they
+// are visually indistinguishable from, following the Unicode confusables data (UTS #39)
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'ё': 'e', 'һ': 'h', 'і': 'i', 'ї': 'i', 'ј': 'j',
+	'к': 'k', 'ӏ': 'l', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p', 'ԛ': 'q', 'ѕ': 's',
+	'т': 't', 'у': 'y', 'ԝ': 'w', 'х': 'x', 'ԁ': 'd', 'ɡ': 'g', 'с': 'c', 'ү': 'y',
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o',
+	'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x', 'γ': 'y', 'ζ': 'z', 'μ': 'u',
+}
+
+// confusableScripts are the scripts whose letters are commonly mistaken for one another
+var confusableScripts = []*unicode.RangeTable{unicode.Latin, unicode.Greek, unicode.Cyrillic}
+
+var caseFolder = cases.Fold()
+
+// UsernameSkeleton returns the form of a username used to detect look-alikes.
+// Usernames with the same skeleton differ only in case, width, or letters that
+// render the same, such as "admin" and "аdmin" with a Cyrillic "а".
+func UsernameSkeleton(username string) string {
+	folded := caseFolder.String(norm.NFKC.String(username))
+	skeleton := strings.Map(func(r rune) rune {
+		if latin, ok := confusables[r]; ok {
+			return latin
+		}
+		return r
+	}, folded)
+	return norm.NFKC.String(skeleton)
+}
+
+// mixesConfusableScripts reports whether letters from more than one of the
+// confusable scripts appear in the same string
+func mixesConfusableScripts(s string) bool {
+	var seen *unicode.RangeTable
+	for _, r := range s {
+		for _, script := range confusableScripts {
+			if !unicode.Is(script, r) {
+				continue
+			}
+			if seen != nil && seen != script {
+				return true
+			}
+			seen = script
+		}
+	}
+	return false
+}
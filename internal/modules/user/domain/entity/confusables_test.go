@@ -0,0 +1,79 @@
+package entity
+
+import "testing"
+
+func TestUsernameSkeleton(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		want     string
+	}{
+		{name: "plain", username: "admin", want: "admin"},
+		{name: "case", username: "Admin", want: "admin"},
+		{name: "full width", username: "ＡＤＭＩＮ", want: "admin"},
+		{name: "cyrillic a", username: "аdmin", want: "admin"},
+		{name: "greek letters", username: "ραγ", want: "pay"},
+		{name: "greek capitals", username: "ΑΒΓ", want: "aby"},
+		{name: "cyrillic capitals", username: "АДМИН", want: "aдmиh"},
+		{name: "sharp s folds", username: "Straße", want: "strasse"},
+		{name: "ligature", username: "ﬁle", want: "file"},
+		{name: "circled digit", username: "①", want: "1"},
+		{name: "digits and underscore", username: "admin_1", want: "admin_1"},
+		{name: "other scripts unchanged", username: "山田", want: "山田"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UsernameSkeleton(tt.username); got != tt.want {
+				t.Errorf("UsernameSkeleton(%q) = %q, want %q", tt.username, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsernameSkeletonCollisions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{a: "paypal", b: "рaypal", same: true},
+		{a: "paypal", b: "PAYPAL", same: true},
+		{a: "strasse", b: "Straße", same: true},
+		{a: "scope", b: "ѕсоре", same: true},
+		{a: "admin", b: "admin1", same: false},
+		{a: "admin", b: "adm1n", same: false},
+		{a: "rn", b: "m", same: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if same := UsernameSkeleton(tt.a) == UsernameSkeleton(tt.b); same != tt.same {
+				t.Errorf("skeletons of %q and %q equal = %v, want %v", tt.a, tt.b, same, tt.same)
+			}
+		})
+	}
+}
+
+func TestMixesConfusableScripts(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{s: "admin", want: false},
+		{s: "админ", want: false},
+		{s: "αβγ", want: false},
+		{s: "аdmin", want: true},
+		{s: "adminα", want: true},
+		{s: "αдмин", want: true},
+		{s: "admin_山田", want: false},
+		{s: "123_", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := mixesConfusableScripts(tt.s); got != tt.want {
+				t.Errorf("mixesConfusableScripts(%q) = %v, want %v", tt.s, got, tt.want)
+			}
+		})
+	}
+}
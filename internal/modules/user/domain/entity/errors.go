@@ -5,22 +5,135 @@ import "errors"
 var (
 	// ErrUserNotFound is returned when a user is not found
 	ErrUserNotFound = errors.New("user not found")
-	
+
 	// ErrUserAlreadyExists is returned when attempting to create a user that already exists
 	ErrUserAlreadyExists = errors.New("user already exists")
-	
+
 	// ErrInvalidEmail is returned when an email is invalid
 	ErrInvalidEmail = errors.New("invalid email address")
-	
+
 	// ErrInvalidUsername is returned when a username is invalid
 	ErrInvalidUsername = errors.New("invalid username")
-	
+
+	// ErrInvalidName is returned when a person's name is empty or too long
+	ErrInvalidName = errors.New("invalid name")
+
+	// ErrInvalidNameReading is returned when a phonetic name reading is not kana
+	ErrInvalidNameReading = errors.New("invalid name reading")
+
+	// ErrInvalidNameOrder is returned when a name order is not supported
+	ErrInvalidNameOrder = errors.New("invalid name order")
+
+	// ErrInvalidLocale is returned when a locale is not a valid BCP 47 language tag
+	ErrInvalidLocale = errors.New("invalid locale")
+
 	// ErrPasswordTooShort is returned when a password is too short
 	ErrPasswordTooShort = errors.New("password must be at least 8 characters")
-	
+
+	// ErrPasswordChangeRequired is returned when a user must change their password before doing anything else
+	ErrPasswordChangeRequired = errors.New("password change required")
+
+	// ErrPasswordReused is returned when a new password is the same as the current one
+	ErrPasswordReused = errors.New("new password must differ from the current password")
+
 	// ErrInvalidCredentials is returned when login credentials are invalid
 	ErrInvalidCredentials = errors.New("invalid credentials")
-	
+
+	// ErrAdminRequired is returned when a caller who is not an active administrator
+	// attempts an operation reserved for administrators
+	ErrAdminRequired = errors.New("administrator privileges required")
+
+	// ErrUserInactive is returned when a deactivated user tries to sign in
+	ErrUserInactive = errors.New("user account is not active")
+
+	// ErrUserOutsideValidity is returned when a user tries to sign in before or after their validity window
+	ErrUserOutsideValidity = errors.New("user account is not valid at this time")
+
+	// ErrInvalidValidity is returned when a validity window ends before it starts
+	ErrInvalidValidity = errors.New("invalid validity window")
+
+	// ErrInvalidTimeRange is returned when a time range ends before it starts
+	ErrInvalidTimeRange = errors.New("invalid time range")
+
+	// ErrInvalidFilter is returned when a list filter has an unknown status or match mode
+	ErrInvalidFilter = errors.New("invalid filter")
+
+	// ErrInvalidSort is returned when a sort order names an unknown field or direction
+	ErrInvalidSort = errors.New("invalid sort")
+
+	// ErrInvalidPageToken is returned when a page token is invalid or was issued for different list parameters
+	ErrInvalidPageToken = errors.New("invalid page token")
+
+	// ErrInvalidResumeToken is returned when a WatchUsers resume token is invalid
+	ErrInvalidResumeToken = errors.New("invalid resume token")
+
+	// ErrResumeTokenExpired is returned when the changes after a WatchUsers resume
+	// token have been pruned from the change log
+	ErrResumeTokenExpired = errors.New("resume token expired")
+
+	// ErrInvalidBatch is returned when a batch is empty or too large
+	ErrInvalidBatch = errors.New("invalid batch")
+
+	// ErrDuplicateBatchItem is returned for a batch item naming the same user as an earlier item
+	ErrDuplicateBatchItem = errors.New("user appears more than once in the batch")
+
+	// ErrInvalidMerge is returned when two users cannot be merged, e.g. a user into itself
+	ErrInvalidMerge = errors.New("invalid merge")
+
 	// ErrInvalidUserID is returned when a user ID is invalid
 	ErrInvalidUserID = errors.New("invalid user ID")
-)
\ No newline at end of file
+
+	// ErrInvalidETag is returned when an etag cannot be parsed
+	ErrInvalidETag = errors.New("invalid etag")
+
+	// ErrETagMismatch is returned when a request's etag does not match the current version
+	ErrETagMismatch = errors.New("etag does not match the current version")
+
+	// ErrConcurrentModification is returned when a user was changed by another request while being written
+	ErrConcurrentModification = errors.New("user was modified concurrently")
+
+	// ErrInvalidUpdateMask is returned when an update mask names an unknown or immutable field
+	ErrInvalidUpdateMask = errors.New("invalid update mask")
+
+	// ErrInvalidAttribute is returned when a custom attribute value is invalid
+	ErrInvalidAttribute = errors.New("invalid attribute")
+
+	// ErrInvalidAttributeDefinition is returned when an attribute definition is invalid
+	ErrInvalidAttributeDefinition = errors.New("invalid attribute definition")
+
+	// ErrAttributeDefinitionNotFound is returned when an attribute definition is not found
+	ErrAttributeDefinitionNotFound = errors.New("attribute definition not found")
+
+	// ErrAttributeDefinitionAlreadyExists is returned when an attribute definition key is already in use
+	ErrAttributeDefinitionAlreadyExists = errors.New("attribute definition already exists")
+
+	// ErrIdentifierNotAllowed is returned when a username or email is reserved or blocked
+	ErrIdentifierNotAllowed = errors.New("identifier not allowed")
+
+	// ErrInvalidIdentifierRule is returned when an identifier rule is invalid
+	ErrInvalidIdentifierRule = errors.New("invalid identifier rule")
+
+	// ErrIdentifierRuleNotFound is returned when an identifier rule is not found
+	ErrIdentifierRuleNotFound = errors.New("identifier rule not found")
+
+	// ErrIdentifierRuleAlreadyExists is returned when an identical identifier rule already exists
+	ErrIdentifierRuleAlreadyExists = errors.New("identifier rule already exists")
+
+	// ErrInvitationNotFound is returned when an invitation is not found
+	ErrInvitationNotFound = errors.New("invitation not found")
+
+	// ErrInvitationAlreadyExists is returned when a pending invitation already exists for an email
+	ErrInvitationAlreadyExists = errors.New("invitation already exists")
+
+	// ErrInvitationExpired is returned when accepting an expired invitation
+	ErrInvitationExpired = errors.New("invitation has expired")
+
+	// ErrInvitationNotPending is returned when an invitation has already been accepted or revoked
+	ErrInvitationNotPending = errors.New("invitation is no longer pending")
+
+	// ErrInvalidInvitation is returned when invitation data is invalid
+	ErrInvalidInvitation = errors.New("invalid invitation")
+
+	// ErrInvalidInvitationID is returned when an invitation ID is invalid
+	ErrInvalidInvitationID = errors.New("invalid invitation ID")
+)
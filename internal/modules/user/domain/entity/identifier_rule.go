@@ -0,0 +1,185 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdentifierRuleKind is the kind of restriction an identifier rule places on signups
+type IdentifierRuleKind string
+
+const (
+	// IdentifierRuleReservedUsername reserves a username, including its look-alikes
+	IdentifierRuleReservedUsername IdentifierRuleKind = "reserved_username"
+	// IdentifierRuleUsernamePattern blocks usernames matching a regular expression
+	IdentifierRuleUsernamePattern IdentifierRuleKind = "username_pattern"
+	// IdentifierRuleBlockedEmailDomain blocks an email domain and its subdomains
+	IdentifierRuleBlockedEmailDomain IdentifierRuleKind = "blocked_email_domain"
+	// IdentifierRuleAllowedEmailDomain allows an email domain and its subdomains.
+	// Once any allowed domain exists, emails from every other domain are rejected.
+	IdentifierRuleAllowedEmailDomain IdentifierRuleKind = "allowed_email_domain"
+)
+
+// IsValid reports whether the identifier rule kind is supported
+func (k IdentifierRuleKind) IsValid() bool {
+	switch k {
+	case IdentifierRuleReservedUsername, IdentifierRuleUsernamePattern,
+		IdentifierRuleBlockedEmailDomain, IdentifierRuleAllowedEmailDomain:
+		return true
+	}
+	return false
+}
+
+// IdentifierRule restricts which usernames and email domains can be registered
+type IdentifierRule struct {
+	ID        uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Kind      IdentifierRuleKind `gorm:"type:varchar(32);not null;uniqueIndex:idx_identifier_rules_kind_value" json:"kind"`
+	Value     string             `gorm:"type:varchar(255);not null;uniqueIndex:idx_identifier_rules_kind_value" json:"value"`
+	CreatedAt time.Time          `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for IdentifierRule entity
+func (IdentifierRule) TableName() string {
+	return "identifier_rules"
+}
+
+// Normalize canonicalizes the rule value so equivalent rules compare equal
+func (r *IdentifierRule) Normalize() {
+	r.Value = strings.TrimSpace(r.Value)
+	switch r.Kind {
+	case IdentifierRuleReservedUsername:
+		r.Value = NormalizeUsername(r.Value)
+	case IdentifierRuleBlockedEmailDomain, IdentifierRuleAllowedEmailDomain:
+		r.Value = strings.TrimPrefix(strings.ToLower(r.Value), "@")
+	}
+}
+
+// Validate validates the identifier rule
+func (r *IdentifierRule) Validate() error {
+	if !r.Kind.IsValid() {
+		return fmt.Errorf("%w: unsupported kind %q", ErrInvalidIdentifierRule, r.Kind)
+	}
+	if r.Value == "" {
+		return fmt.Errorf("%w: value is required", ErrInvalidIdentifierRule)
+	}
+	switch r.Kind {
+	case IdentifierRuleUsernamePattern:
+		if _, err := regexp.Compile(r.Value); err != nil {
+			return fmt.Errorf("%w: invalid pattern: %v", ErrInvalidIdentifierRule, err)
+		}
+	case IdentifierRuleBlockedEmailDomain, IdentifierRuleAllowedEmailDomain:
+		if strings.ContainsAny(r.Value, "@ ") || !strings.Contains(r.Value, ".") {
+			return fmt.Errorf("%w: %q is not a domain", ErrInvalidIdentifierRule, r.Value)
+		}
+	}
+	return nil
+}
+
+// IdentifierPolicy checks usernames and emails against a set of identifier rules
+type IdentifierPolicy struct {
+	reserved       map[string]bool
+	patterns       []*regexp.Regexp
+	blockedDomains []string
+	allowedDomains []string
+}
+
+// NewIdentifierPolicy compiles identifier rules into a policy
+func NewIdentifierPolicy(rules []*IdentifierRule) (*IdentifierPolicy, error) {
+	policy := &IdentifierPolicy{reserved: make(map[string]bool)}
+	for _, rule := range rules {
+		switch rule.Kind {
+		case IdentifierRuleReservedUsername:
+			policy.reserved[UsernameSkeleton(rule.Value)] = true
+		case IdentifierRuleUsernamePattern:
+			// Patterns match the skeleton, so they also catch case and homoglyph variants
+			pattern, err := regexp.Compile("(?i)" + rule.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid pattern %q: %v", ErrInvalidIdentifierRule, rule.Value, err)
+			}
+			policy.patterns = append(policy.patterns, pattern)
+		case IdentifierRuleBlockedEmailDomain:
+			policy.blockedDomains = append(policy.blockedDomains, rule.Value)
+		case IdentifierRuleAllowedEmailDomain:
+			policy.allowedDomains = append(policy.allowedDomains, rule.Value)
+		}
+	}
+	return policy, nil
+}
+
+// CheckUsername rejects reserved usernames and usernames matching a blocked pattern
+func (p *IdentifierPolicy) CheckUsername(username string) error {
+	skeleton := UsernameSkeleton(username)
+	if p.reserved[skeleton] {
+		return &FieldViolation{
+			Field:       "username",
+			Description: fmt.Sprintf("username %q is reserved", username),
+			Err:         ErrIdentifierNotAllowed,
+		}
+	}
+	for _, pattern := range p.patterns {
+		if pattern.MatchString(skeleton) {
+			return &FieldViolation{
+				Field:       "username",
+				Description: fmt.Sprintf("username %q is not allowed", username),
+				Err:         ErrIdentifierNotAllowed,
+			}
+		}
+	}
+	return nil
+}
+
+// CheckEmail rejects emails from blocked domains or, when an allowlist exists, from unlisted domains
+func (p *IdentifierPolicy) CheckEmail(email string) error {
+	at := strings.LastIndex(email, "@")
+	domain := strings.ToLower(email[at+1:])
+
+	for _, blocked := range p.blockedDomains {
+		if matchesDomain(domain, blocked) {
+			return &FieldViolation{
+				Field:       "email",
+				Description: fmt.Sprintf("email domain %q is not allowed", domain),
+				Err:         ErrIdentifierNotAllowed,
+			}
+		}
+	}
+
+	if len(p.allowedDomains) == 0 {
+		return nil
+	}
+	for _, allowed := range p.allowedDomains {
+		if matchesDomain(domain, allowed) {
+			return nil
+		}
+	}
+	return &FieldViolation{
+		Field:       "email",
+		Description: fmt.Sprintf("email domain %q is not on the allowlist", domain),
+		Err:         ErrIdentifierNotAllowed,
+	}
+}
+
+// matchesDomain reports whether domain is the rule domain or one of its subdomains
+func matchesDomain(domain, ruleDomain string) bool {
+	return domain == ruleDomain || strings.HasSuffix(domain, "."+ruleDomain)
+}
+
+// FieldViolation reports a request field whose value breaks a rule
+type FieldViolation struct {
+	Field       string
+	Description string
+	Err         error
+}
+
+// Error implements error
+func (v *FieldViolation) Error() string {
+	return fmt.Sprintf("%v: %s", v.Err, v.Description)
+}
+
+// Unwrap returns the underlying domain error
+func (v *FieldViolation) Unwrap() error {
+	return v.Err
+}
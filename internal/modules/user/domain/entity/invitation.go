@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvitationStatus represents the state of an invitation
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "pending"
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	InvitationStatusRevoked  InvitationStatus = "revoked"
+	InvitationStatusExpired  InvitationStatus = "expired"
+)
+
+// Invitation allows a person to create an account for the invited email address.
+// Only a hash of the invitation token is stored.
+type Invitation struct {
+	ID             uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrganizationID *uuid.UUID       `gorm:"type:uuid;index" json:"organization_id,omitempty"`
+	Email          string           `gorm:"type:varchar(255);index;not null" json:"email"`
+	Roles          StringList       `gorm:"type:jsonb;not null;default:'[]'" json:"roles"`
+	TokenHash      string           `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Status         InvitationStatus `gorm:"type:varchar(20);index;not null;default:'pending'" json:"status"`
+	ExpiresAt      time.Time        `gorm:"not null" json:"expires_at"`
+	AcceptedAt     *time.Time       `json:"accepted_at,omitempty"`
+	AcceptedUserID *uuid.UUID       `gorm:"type:uuid" json:"accepted_user_id,omitempty"`
+	RevokedAt      *time.Time       `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for Invitation entity
+func (Invitation) TableName() string {
+	return "invitations"
+}
+
+// BeforeCreate hook to set UUID before creating
+func (i *Invitation) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// GetStatus returns the invitation's status, reporting expiry of pending invitations
+func (i *Invitation) GetStatus(now time.Time) InvitationStatus {
+	if i.Status == InvitationStatusPending && !now.Before(i.ExpiresAt) {
+		return InvitationStatusExpired
+	}
+	return i.Status
+}
+
+// CanAccept checks that the invitation is still pending and not expired
+func (i *Invitation) CanAccept(now time.Time) error {
+	switch i.GetStatus(now) {
+	case InvitationStatusPending:
+		return nil
+	case InvitationStatusExpired:
+		return ErrInvitationExpired
+	default:
+		return ErrInvitationNotPending
+	}
+}
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LoginMethod is the way a user tried to sign in
+type LoginMethod string
+
+const (
+	LoginMethodPassword LoginMethod = "password"
+	LoginMethodToken    LoginMethod = "token"
+	LoginMethodMFA      LoginMethod = "mfa"
+)
+
+// LoginFailureReason explains why a sign-in attempt failed
+type LoginFailureReason string
+
+const (
+	// LoginFailureNone is the reason recorded for successful attempts
+	LoginFailureNone LoginFailureReason = ""
+	// LoginFailureUnknownIdentifier means no user has the email or username
+	LoginFailureUnknownIdentifier LoginFailureReason = "unknown_identifier"
+	// LoginFailureInvalidPassword means the password did not match
+	LoginFailureInvalidPassword LoginFailureReason = "invalid_password"
+	// LoginFailureUserInactive means the credentials matched a deactivated user
+	LoginFailureUserInactive LoginFailureReason = "user_inactive"
+	// LoginFailureOutsideValidity means the credentials matched a user before or after their validity window
+	LoginFailureOutsideValidity LoginFailureReason = "outside_validity"
+	// LoginFailureInternalError means the attempt could not be checked
+	LoginFailureInternalError LoginFailureReason = "internal_error"
+)
+
+// Column limits of login events; longer client-supplied values are truncated
+const (
+	maxLoginIdentifierLength = 255
+	maxLoginIPAddressLength  = 64
+	maxLoginUserAgentLength  = 512
+)
+
+// LoginAttempt describes where a sign-in attempt came from
+type LoginAttempt struct {
+	Method    LoginMethod
+	IPAddress string
+	UserAgent string
+}
+
+// LoginEvent records the outcome of a sign-in attempt. UserID is nil when the
+// identifier did not match any user.
+type LoginEvent struct {
+	ID            uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID        *uuid.UUID         `gorm:"type:uuid;index:idx_login_events_user_created,priority:1" json:"user_id,omitempty"`
+	Identifier    string             `gorm:"type:varchar(255);not null" json:"identifier"`
+	Method        LoginMethod        `gorm:"type:varchar(20);not null" json:"method"`
+	Success       bool               `gorm:"not null" json:"success"`
+	FailureReason LoginFailureReason `gorm:"type:varchar(32);not null;default:''" json:"failure_reason,omitempty"`
+	IPAddress     string             `gorm:"type:varchar(64);not null;default:''" json:"ip_address"`
+	UserAgent     string             `gorm:"type:varchar(512);not null;default:''" json:"user_agent"`
+	CreatedAt     time.Time          `gorm:"autoCreateTime;index:idx_login_events_user_created,priority:2" json:"created_at"`
+}
+
+// TableName specifies the table name for LoginEvent entity
+func (LoginEvent) TableName() string {
+	return "login_events"
+}
+
+// BeforeCreate hook to set UUID before creating
+func (e *LoginEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// NewLoginEvent creates a login event for an attempt with the given identifier
+func NewLoginEvent(attempt LoginAttempt, identifier string, at time.Time) *LoginEvent {
+	method := attempt.Method
+	if method == "" {
+		method = LoginMethodPassword
+	}
+	return &LoginEvent{
+		Identifier: truncateRunes(strings.TrimSpace(identifier), maxLoginIdentifierLength),
+		Method:     method,
+		IPAddress:  truncateRunes(attempt.IPAddress, maxLoginIPAddressLength),
+		UserAgent:  truncateRunes(attempt.UserAgent, maxLoginUserAgentLength),
+		CreatedAt:  at,
+	}
+}
+
+// truncateRunes shortens s to at most max characters
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
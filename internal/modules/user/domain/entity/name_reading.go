@@ -0,0 +1,133 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NameOrder controls whether the family or given name comes first in a full name
+type NameOrder string
+
+const (
+	// NameOrderDefault follows the user's locale
+	NameOrderDefault NameOrder = ""
+	// NameOrderGivenFirst writes the given name first, as in "Taro Yamada"
+	NameOrderGivenFirst NameOrder = "given_first"
+	// NameOrderFamilyFirst writes the family name first, as in "山田 太郎"
+	NameOrderFamilyFirst NameOrder = "family_first"
+)
+
+// IsValid reports whether the name order is supported
+func (o NameOrder) IsValid() bool {
+	switch o {
+	case NameOrderDefault, NameOrderGivenFirst, NameOrderFamilyFirst:
+		return true
+	}
+	return false
+}
+
+// familyFirstLanguages are the languages that conventionally write the family name first
+var familyFirstLanguages = map[string]bool{
+	"ja": true,
+	"zh": true,
+	"ko": true,
+	"vi": true,
+	"hu": true,
+}
+
+// NameOrderForLocale returns the conventional name order for a BCP 47 locale
+func NameOrderForLocale(locale string) NameOrder {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return NameOrderGivenFirst
+	}
+	base, _ := tag.Base()
+	if familyFirstLanguages[base.String()] {
+		return NameOrderFamilyFirst
+	}
+	return NameOrderGivenFirst
+}
+
+// NormalizeLocale validates a BCP 47 locale and returns its canonical form.
+// An empty locale is allowed and means no preference.
+func NormalizeLocale(locale string) (string, error) {
+	locale = strings.TrimSpace(locale)
+	if locale == "" {
+		return "", nil
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+	}
+	return tag.String(), nil
+}
+
+// FormatFullName joins a given and family name in the given order. Names written
+// entirely in CJK scripts are joined without a space, as in "山田太郎".
+func FormatFullName(givenName, familyName string, order NameOrder) string {
+	if givenName == "" {
+		return familyName
+	}
+	if familyName == "" {
+		return givenName
+	}
+
+	first, second := givenName, familyName
+	if order == NameOrderFamilyFirst {
+		first, second = familyName, givenName
+	}
+	if isCJK(first) && isCJK(second) {
+		return first + second
+	}
+	return first + " " + second
+}
+
+// isCJK reports whether every letter in s is Han, Hiragana, Katakana, or Hangul
+func isCJK(s string) bool {
+	for _, r := range s {
+		if !unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeKana returns the canonical form of a phonetic reading: half-width
+// katakana is widened, hiragana is folded to katakana, and spaces are collapsed
+func NormalizeKana(reading string) string {
+	reading = norm.NFKC.String(reading)
+	reading = strings.Map(func(r rune) rune {
+		// Hiragana ぁ (U+3041) to ゖ (U+3096) and the iteration marks ゝゞ map onto katakana
+		if (r >= 'ぁ' && r <= 'ゖ') || r == 'ゝ' || r == 'ゞ' {
+			return r + ('ァ' - 'ぁ')
+		}
+		return r
+	}, reading)
+	return strings.Join(strings.Fields(reading), " ")
+}
+
+// NewNameReading normalizes and validates a katakana reading of a name.
+// An empty reading is allowed.
+func NewNameReading(reading string) (string, error) {
+	reading = NormalizeKana(reading)
+	if utf8.RuneCountInString(reading) > 100 {
+		return "", fmt.Errorf("%w: reading must not exceed 100 characters", ErrInvalidNameReading)
+	}
+	for _, r := range reading {
+		if !unicode.Is(unicode.Katakana, r) && r != 'ー' && r != '・' && r != ' ' {
+			return "", fmt.Errorf("%w: reading must contain only kana", ErrInvalidNameReading)
+		}
+	}
+	return reading, nil
+}
+
+// IsKana reports whether s consists only of kana once normalized
+func IsKana(s string) bool {
+	reading, err := NewNameReading(s)
+	return err == nil && reading != ""
+}
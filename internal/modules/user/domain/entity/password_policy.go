@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"crypto/rand"
+	"math/big"
+	"time"
+)
+
+// temporaryPasswordAlphabet omits characters that are easily confused when read
+// out by a helpdesk, such as 0/O and 1/l/I
+const temporaryPasswordAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnpqrstuvwxyz23456789"
+
+// temporaryPasswordLength is the length of generated temporary passwords
+const temporaryPasswordLength = 16
+
+// PasswordPolicy controls when users must change their password
+type PasswordPolicy struct {
+	// MaxAge is how long a password stays valid; zero means passwords never expire
+	MaxAge time.Duration
+}
+
+// IsExpired reports whether the user's password is older than the policy allows.
+// Users without a recorded password change count from their creation.
+func (p PasswordPolicy) IsExpired(user *User, now time.Time) bool {
+	if p.MaxAge <= 0 {
+		return false
+	}
+	changedAt := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+	return !now.Before(changedAt.Add(p.MaxAge))
+}
+
+// RequiresChange reports whether the user must change their password before
+// doing anything else
+func (p PasswordPolicy) RequiresChange(user *User, now time.Time) bool {
+	return user.MustChangePassword || p.IsExpired(user, now)
+}
+
+// GenerateTemporaryPassword returns a random password for an administrator to hand to a user
+func GenerateTemporaryPassword() (string, error) {
+	max := big.NewInt(int64(len(temporaryPasswordAlphabet)))
+	password := make([]byte, temporaryPasswordLength)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		password[i] = temporaryPasswordAlphabet[n.Int64()]
+	}
+	return string(password), nil
+}
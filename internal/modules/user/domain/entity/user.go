@@ -1,6 +1,9 @@
 package entity
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,17 +12,50 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Email     string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
-	Username  string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"username"`
-	FirstName string         `gorm:"type:varchar(100);not null" json:"first_name"`
-	LastName  string         `gorm:"type:varchar(100);not null" json:"last_name"`
-	Password  string         `gorm:"type:varchar(255);not null" json:"-"`
-	IsActive  bool           `gorm:"default:true" json:"is_active"`
-	IsAdmin   bool           `gorm:"default:false" json:"is_admin"`
-	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Email     string    `gorm:"type:varchar(255);index;not null" json:"email"`
+	Username  string    `gorm:"type:varchar(100);index;not null" json:"username"`
+	FirstName string    `gorm:"type:varchar(100);not null" json:"first_name"`
+	LastName  string    `gorm:"type:varchar(100);not null" json:"last_name"`
+	// FamilyNameKana and GivenNameKana are phonetic readings normalized to full-width katakana
+	FamilyNameKana string         `gorm:"type:varchar(100);not null;default:''" json:"family_name_kana"`
+	GivenNameKana  string         `gorm:"type:varchar(100);not null;default:''" json:"given_name_kana"`
+	NameOrder      NameOrder      `gorm:"type:varchar(20);not null;default:''" json:"name_order"`
+	Locale         string         `gorm:"type:varchar(35);not null;default:''" json:"locale"`
+	Password       string         `gorm:"type:varchar(255);not null" json:"-"`
+	IsActive       bool           `gorm:"default:true" json:"is_active"`
+	IsAdmin        bool           `gorm:"default:false" json:"is_admin"`
+	Attributes     Attributes     `gorm:"type:jsonb;not null;default:'{}';index:idx_users_attributes,type:gin" json:"attributes"`
+	Version        int64          `gorm:"not null;default:1" json:"version"`
+	LastLoginAt    *time.Time     `json:"last_login_at,omitempty"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// UsernameSkeleton is the look-alike detection form of Username; it is unique
+	// within the same scope as the username itself
+	UsernameSkeleton string `gorm:"type:varchar(255);not null;default:''" json:"-"`
+
+	// UniquenessOrganizationID is the organization within which email and username
+	// are unique, or nil when they are unique across the deployment
+	UniquenessOrganizationID *uuid.UUID `gorm:"type:uuid" json:"-"`
+
+	// MergedIntoID is the user this user was merged into; it is only set on
+	// soft-deleted users
+	MergedIntoID *uuid.UUID `gorm:"type:uuid;index" json:"merged_into_id,omitempty"`
+
+	// ValidFrom and ValidUntil bound when the user can sign in, e.g. for contractors
+	// and guests; nil leaves that side of the window open
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `gorm:"index" json:"valid_until,omitempty"`
+
+	// MustChangePassword limits the user to changing their password after signing in,
+	// e.g. after an administrator set a temporary password
+	MustChangePassword bool `gorm:"not null;default:false" json:"must_change_password"`
+
+	// PasswordChangedAt is when the password was last set, or nil for users created
+	// before it was recorded
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty"`
 }
 
 // TableName specifies the table name for User entity
@@ -32,15 +68,59 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	if u.ID == uuid.Nil {
 		u.ID = uuid.New()
 	}
+	if u.Version == 0 {
+		u.Version = 1
+	}
+	if u.UsernameSkeleton == "" {
+		u.UsernameSkeleton = UsernameSkeleton(u.Username)
+	}
 	return nil
 }
 
+// ETag returns an opaque tag identifying the current version of the user
+func (u *User) ETag() string {
+	return strconv.Quote(strconv.FormatInt(u.Version, 10))
+}
+
+// CheckVersion verifies that the user has not changed since the expected version was read.
+// An expected version of zero skips the check.
+func (u *User) CheckVersion(expectedVersion int64) error {
+	if expectedVersion != 0 && u.Version != expectedVersion {
+		return ErrETagMismatch
+	}
+	return nil
+}
+
+// ParseETag returns the version identified by an etag. An empty etag yields zero.
+func ParseETag(etag string) (int64, error) {
+	if etag == "" {
+		return 0, nil
+	}
+	value := etag
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return 0, ErrInvalidETag
+		}
+		value = unquoted
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 1 {
+		return 0, ErrInvalidETag
+	}
+	return version, nil
+}
+
+// AdminRole is the group role that grants administrator privileges within a
+// tenant, like IsAdmin does for a single user
+const AdminRole = "admin"
+
 // UserStatus represents the status of a user
 type UserStatus string
 
 const (
-	UserStatusActive   UserStatus = "active"
-	UserStatusInactive UserStatus = "inactive"
+	UserStatusActive    UserStatus = "active"
+	UserStatusInactive  UserStatus = "inactive"
 	UserStatusSuspended UserStatus = "suspended"
 )
 
@@ -55,12 +135,47 @@ func (u *User) GetStatus() UserStatus {
 	return UserStatusActive
 }
 
-// GetFullName returns the user's full name
+// IsValidAt reports whether the time falls within the user's validity window.
+// The window includes ValidFrom and excludes ValidUntil.
+func (u *User) IsValidAt(t time.Time) bool {
+	if u.ValidFrom != nil && t.Before(*u.ValidFrom) {
+		return false
+	}
+	if u.ValidUntil != nil && !t.Before(*u.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// ValidateValidity checks that the validity window does not end before it starts
+func ValidateValidity(validFrom, validUntil *time.Time) error {
+	if validFrom != nil && validUntil != nil && !validUntil.After(*validFrom) {
+		return fmt.Errorf("%w: valid_until must be after valid_from", ErrInvalidValidity)
+	}
+	return nil
+}
+
+// GetFullName returns the user's full name in the user's name order
 func (u *User) GetFullName() string {
 	if u.FirstName == "" && u.LastName == "" {
 		return u.Username
 	}
-	return u.FirstName + " " + u.LastName
+	return FormatFullName(u.FirstName, u.LastName, u.EffectiveNameOrder())
+}
+
+// EffectiveNameOrder returns the user's name order, falling back to the
+// convention of the user's locale when no explicit order is set
+func (u *User) EffectiveNameOrder() NameOrder {
+	if u.NameOrder != NameOrderDefault {
+		return u.NameOrder
+	}
+	return NameOrderForLocale(u.Locale)
+}
+
+// GetNameReading returns the phonetic reading of the full name in family-given order,
+// which is the order readings are sorted and searched by
+func (u *User) GetNameReading() string {
+	return strings.TrimSpace(u.FamilyNameKana + " " + u.GivenNameKana)
 }
 
 // Validate validates the user entity
@@ -75,4 +190,4 @@ func (u *User) Validate() error {
 		return ErrPasswordTooShort
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// MaxUserBatchSize is the most users one batch may create, update or delete
+const MaxUserBatchSize = 1000
+
+// UserCreation is a user to create in a batch, with its plain password
+type UserCreation struct {
+	User     *User
+	Password string
+}
+
+// UserModification is an update of one user in a batch.
+// A non-zero expected version rejects the update if the user has changed since it was read.
+type UserModification struct {
+	ID              uuid.UUID
+	Update          *UserUpdate
+	ExpectedVersion int64
+}
+
+// UserModificationResult is the outcome of one update of a batch. On success,
+// User is the updated user and Changed lists the fields whose values changed.
+type UserModificationResult struct {
+	User    *User
+	Changed []UserField
+	Err     error
+}
+
+// UserDeletion is the deletion of one user in a batch.
+// A non-zero expected version rejects the deletion if the user has changed since it was read.
+type UserDeletion struct {
+	ID              uuid.UUID
+	ExpectedVersion int64
+}
+
+// BatchItemError is the error of one item of a batch that is written all or nothing
+type BatchItemError struct {
+	// Index is the position of the item in the batch
+	Index int
+	Err   error
+}
+
+// Error implements error
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the item's error
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// FirstBatchError returns the first non-nil error of a batch as a BatchItemError, or nil
+func FirstBatchError(errs []error) error {
+	for i, err := range errs {
+		if err != nil {
+			return &BatchItemError{Index: i, Err: err}
+		}
+	}
+	return nil
+}
+
+// CheckBatchSize rejects empty batches and batches larger than MaxUserBatchSize
+func CheckBatchSize(size int) error {
+	if size == 0 {
+		return fmt.Errorf("%w: batch is empty", ErrInvalidBatch)
+	}
+	if size > MaxUserBatchSize {
+		return fmt.Errorf("%w: batch of %d exceeds the limit of %d", ErrInvalidBatch, size, MaxUserBatchSize)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFirstBatchError(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantIndex int
+		wantErr   error
+	}{
+		{name: "empty", errs: nil, wantIndex: -1},
+		{name: "no failures", errs: []error{nil, nil, nil}, wantIndex: -1},
+		{name: "first item", errs: []error{errFirst, nil, errSecond}, wantIndex: 0, wantErr: errFirst},
+		{name: "later item", errs: []error{nil, nil, errFirst, errSecond}, wantIndex: 2, wantErr: errFirst},
+		{name: "last item", errs: []error{nil, errSecond}, wantIndex: 1, wantErr: errSecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FirstBatchError(tt.errs)
+			if tt.wantIndex < 0 {
+				if err != nil {
+					t.Fatalf("FirstBatchError() = %v, want nil", err)
+				}
+				return
+			}
+
+			var itemErr *BatchItemError
+			if !errors.As(err, &itemErr) {
+				t.Fatalf("FirstBatchError() = %v, want *BatchItemError", err)
+			}
+			if itemErr.Index != tt.wantIndex {
+				t.Errorf("Index = %d, want %d", itemErr.Index, tt.wantIndex)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("FirstBatchError() = %v, want it to wrap %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckBatchSize(t *testing.T) {
+	tests := []struct {
+		size    int
+		wantErr bool
+	}{
+		{size: 0, wantErr: true},
+		{size: 1},
+		{size: MaxUserBatchSize},
+		{size: MaxUserBatchSize + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := CheckBatchSize(tt.size)
+		if errors.Is(err, ErrInvalidBatch) != tt.wantErr {
+			t.Errorf("CheckBatchSize(%d) = %v, want error %v", tt.size, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserChangeType identifies what a user change did to the user
+type UserChangeType string
+
+const (
+	// UserChangeCreated records a user being created, or restored after a soft delete
+	UserChangeCreated UserChangeType = "created"
+	// UserChangeUpdated records a change to a user that neither creates nor deletes it
+	UserChangeUpdated UserChangeType = "updated"
+	// UserChangeDeleted records a user being soft or hard deleted
+	UserChangeDeleted UserChangeType = "deleted"
+)
+
+// UserChange is an entry of the user change log. Entries are written by a trigger
+// on the users table in the transaction that changed the user, so the log holds
+// exactly the committed changes. Before and After are snapshots of the user
+// without its password; Before is nil for creations and After for hard deletes.
+type UserChange struct {
+	Seq int64 `gorm:"primaryKey;autoIncrement;index:idx_user_changes_position,priority:2" json:"seq"`
+
+	// TxID is the ID of the transaction that made the change. Changes are read in
+	// transaction order, see repository.UserChangePosition.
+	TxID int64 `gorm:"column:txid;not null;index:idx_user_changes_position,priority:1" json:"txid"`
+
+	UserID    uuid.UUID      `gorm:"type:uuid;not null" json:"user_id"`
+	Type      UserChangeType `gorm:"column:change_type;type:varchar(20);not null" json:"type"`
+	Before    *User          `gorm:"column:before_data;type:jsonb;serializer:json" json:"before,omitempty"`
+	After     *User          `gorm:"column:after_data;type:jsonb;serializer:json" json:"after,omitempty"`
+	ChangedAt time.Time      `gorm:"not null;default:now();index" json:"changed_at"`
+}
+
+// TableName specifies the table name for UserChange entity
+func (UserChange) TableName() string {
+	return "user_changes"
+}
@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/shared/filter"
+	"github.com/google/uuid"
+)
+
+// UserFilterStatusField is the virtual field filter expressions compare with
+// UserFilterStatusValues; it is not stored on User
+const UserFilterStatusField = "status"
+
+// UserFilterStatusValues are the statuses filter expressions can compare with.
+// Suspended users are soft-deleted, which filter expressions never match.
+var UserFilterStatusValues = []string{"ACTIVE", "INACTIVE"}
+
+// unfilterableUserFields are fields of User that filter expressions cannot reference
+var unfilterableUserFields = map[string]bool{
+	"attributes":     true,
+	"deleted_at":     true,
+	"merged_into_id": true,
+}
+
+// userFilterSchema is derived once from User's fields
+var userFilterSchema = deriveUserFilterSchema()
+
+// UserFilterSchema returns the fields filter expressions on users may reference.
+// They are derived from the JSON names and types of User's fields, plus status.
+func UserFilterSchema() filter.Schema {
+	return userFilterSchema
+}
+
+// deriveUserFilterSchema maps User's serialized fields to filter fields by type
+func deriveUserFilterSchema() filter.Schema {
+	schema := filter.Schema{
+		UserFilterStatusField: {Type: filter.TypeEnum, Values: UserFilterStatusValues},
+	}
+
+	userType := reflect.TypeOf(User{})
+	for i := 0; i < userType.NumField(); i++ {
+		structField := userType.Field(i)
+		name := strings.Split(structField.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || unfilterableUserFields[name] {
+			continue
+		}
+
+		fieldType := structField.Type
+		nullable := fieldType.Kind() == reflect.Ptr
+		if nullable {
+			fieldType = fieldType.Elem()
+		}
+
+		var field filter.Field
+		switch {
+		case fieldType == reflect.TypeOf(time.Time{}):
+			field = filter.Field{Type: filter.TypeTimestamp, Nullable: nullable}
+		case fieldType == reflect.TypeOf(uuid.UUID{}):
+			field = filter.Field{Type: filter.TypeUUID, Nullable: nullable}
+		case fieldType.Kind() == reflect.String:
+			field = filter.Field{Type: filter.TypeString, Nullable: nullable}
+		case fieldType.Kind() == reflect.Bool:
+			field = filter.Field{Type: filter.TypeBool, Nullable: nullable}
+		case fieldType.Kind() == reflect.Int64:
+			field = filter.Field{Type: filter.TypeInt, Nullable: nullable}
+		default:
+			continue
+		}
+		schema[name] = field
+	}
+	return schema
+}
@@ -0,0 +1,133 @@
+package entity
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MergeSide selects which of two merged users a field value is taken from
+type MergeSide string
+
+const (
+	// MergeSideTarget keeps the value of the user that survives the merge
+	MergeSideTarget MergeSide = "target"
+	// MergeSideSource takes the value of the user that is merged away
+	MergeSideSource MergeSide = "source"
+)
+
+// UserFieldPassword identifies the password hash, which is never updated directly
+// but can be carried over from the source user of a merge
+const UserFieldPassword UserField = "password"
+
+// MergeableUserFields lists every field whose value can be taken from the source user of a merge
+var MergeableUserFields = append(append([]UserField(nil), MutableUserFields...), UserFieldPassword)
+
+// IsMergeable reports whether the field can be resolved by a merge
+func (f UserField) IsMergeable() bool {
+	for _, field := range MergeableUserFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldResolution maps fields to the user whose value survives a merge.
+// Fields that are not listed keep the target's value.
+type FieldResolution map[UserField]MergeSide
+
+// Validate checks that every resolved field is mergeable and every side is known
+func (r FieldResolution) Validate() error {
+	for field, side := range r {
+		if !field.IsMergeable() {
+			return fmt.Errorf("%w: field %q cannot be merged", ErrInvalidMerge, field)
+		}
+		if side != MergeSideTarget && side != MergeSideSource {
+			return fmt.Errorf("%w: unknown side %q for field %q", ErrInvalidMerge, side, field)
+		}
+	}
+	return nil
+}
+
+// Apply copies the fields resolved to the source from source to target and
+// returns the fields whose values changed, in MergeableUserFields order
+func (r FieldResolution) Apply(target, source *User) []UserField {
+	var changed []UserField
+	for _, field := range MergeableUserFields {
+		if r[field] != MergeSideSource {
+			continue
+		}
+
+		var differs bool
+		switch field {
+		case UserFieldEmail:
+			differs = target.Email != source.Email
+			target.Email = source.Email
+		case UserFieldUsername:
+			differs = target.Username != source.Username
+			target.Username = source.Username
+			target.UsernameSkeleton = source.UsernameSkeleton
+		case UserFieldFirstName:
+			differs = target.FirstName != source.FirstName
+			target.FirstName = source.FirstName
+		case UserFieldLastName:
+			differs = target.LastName != source.LastName
+			target.LastName = source.LastName
+		case UserFieldFamilyNameKana:
+			differs = target.FamilyNameKana != source.FamilyNameKana
+			target.FamilyNameKana = source.FamilyNameKana
+		case UserFieldGivenNameKana:
+			differs = target.GivenNameKana != source.GivenNameKana
+			target.GivenNameKana = source.GivenNameKana
+		case UserFieldNameOrder:
+			differs = target.NameOrder != source.NameOrder
+			target.NameOrder = source.NameOrder
+		case UserFieldLocale:
+			differs = target.Locale != source.Locale
+			target.Locale = source.Locale
+		case UserFieldIsActive:
+			differs = target.IsActive != source.IsActive
+			target.IsActive = source.IsActive
+		case UserFieldIsAdmin:
+			differs = target.IsAdmin != source.IsAdmin
+			target.IsAdmin = source.IsAdmin
+		case UserFieldValidFrom:
+			differs = !reflect.DeepEqual(target.ValidFrom, source.ValidFrom)
+			target.ValidFrom = source.ValidFrom
+		case UserFieldValidUntil:
+			differs = !reflect.DeepEqual(target.ValidUntil, source.ValidUntil)
+			target.ValidUntil = source.ValidUntil
+		case UserFieldAttributes:
+			differs = !reflect.DeepEqual(target.Attributes, source.Attributes)
+			target.Attributes = source.Attributes
+		case UserFieldPassword:
+			differs = target.Password != source.Password
+			target.Password = source.Password
+			target.PasswordChangedAt = source.PasswordChangedAt
+			target.MustChangePassword = source.MustChangePassword
+		}
+		if differs {
+			changed = append(changed, field)
+		}
+	}
+	return changed
+}
+
+// UserMerge describes merging a source user into a target user. Target holds the
+// target user with the resolved field values already applied.
+type UserMerge struct {
+	Source        *User
+	Target        *User
+	ChangedFields []UserField
+	Audit         *AuditEvent
+}
+
+// MergedReferences counts the records moved from the source user to the target
+// user. Memberships the target already had are merged rather than moved.
+type MergedReferences struct {
+	OrganizationMemberships int64
+	GroupMemberships        int64
+	LoginEvents             int64
+	Invitations             int64
+	Preferences             bool
+}
@@ -0,0 +1,144 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// UserSearchField names a field user searches match against
+type UserSearchField string
+
+const (
+	UserSearchEmail     UserSearchField = "email"
+	UserSearchUsername  UserSearchField = "username"
+	UserSearchFirstName UserSearchField = "first_name"
+	UserSearchLastName  UserSearchField = "last_name"
+	// UserSearchReading matches kana queries against the family and given name readings
+	UserSearchReading UserSearchField = "reading"
+)
+
+// SearchableUserFields lists the fields searches match, in the order highlights are reported
+var SearchableUserFields = []UserSearchField{
+	UserSearchEmail,
+	UserSearchUsername,
+	UserSearchFirstName,
+	UserSearchLastName,
+	UserSearchReading,
+}
+
+// TextRange is a range of characters of a field value, counted in Unicode code
+// points from the start of the value. End is exclusive.
+type TextRange struct {
+	Start int
+	End   int
+}
+
+// FieldHighlight marks where a search query occurs in a field of a user.
+// Ranges is empty when the field only matched approximately.
+type FieldHighlight struct {
+	Field  UserSearchField
+	Value  string
+	Ranges []TextRange
+}
+
+// SearchFieldValue returns the value of the user a search field matches against
+func (u *User) SearchFieldValue(field UserSearchField) string {
+	switch field {
+	case UserSearchEmail:
+		return u.Email
+	case UserSearchUsername:
+		return u.Username
+	case UserSearchFirstName:
+		return u.FirstName
+	case UserSearchLastName:
+		return u.LastName
+	case UserSearchReading:
+		return strings.TrimSpace(u.FamilyNameKana + " " + u.GivenNameKana)
+	default:
+		return ""
+	}
+}
+
+// HighlightUserMatches returns the highlights of the fields a search query matched,
+// in the order of SearchableUserFields. Kana queries are highlighted in readings
+// in their normalized form.
+func HighlightUserMatches(user *User, query string, matched []UserSearchField) []FieldHighlight {
+	isMatched := make(map[UserSearchField]bool, len(matched))
+	for _, field := range matched {
+		isMatched[field] = true
+	}
+
+	var highlights []FieldHighlight
+	for _, field := range SearchableUserFields {
+		if !isMatched[field] {
+			continue
+		}
+		value := user.SearchFieldValue(field)
+		terms := query
+		if field == UserSearchReading {
+			terms = NormalizeKana(query)
+		}
+		highlights = append(highlights, FieldHighlight{
+			Field:  field,
+			Value:  value,
+			Ranges: HighlightTerms(value, terms),
+		})
+	}
+	return highlights
+}
+
+// HighlightTerms returns the ranges of value where a whitespace-separated term of
+// the query occurs, ignoring case. Overlapping and adjacent ranges are merged.
+func HighlightTerms(value, query string) []TextRange {
+	haystack := foldRunes(value)
+	var ranges []TextRange
+	for _, term := range strings.Fields(query) {
+		needle := foldRunes(term)
+		for i := 0; i+len(needle) <= len(haystack); i++ {
+			if equalRunes(haystack[i:i+len(needle)], needle) {
+				ranges = append(ranges, TextRange{Start: i, End: i + len(needle)})
+			}
+		}
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= last.End {
+			if r.End > last.End {
+				last.End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// foldRunes lowercases each rune of s, keeping one rune per code point so that
+// offsets into the result are offsets into s
+func foldRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// equalRunes reports whether two rune slices are equal
+func equalRunes(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UserSortField names a field users can be listed by
+type UserSortField string
+
+const (
+	UserSortCreatedAt UserSortField = "created_at"
+	UserSortUpdatedAt UserSortField = "updated_at"
+	UserSortEmail     UserSortField = "email"
+	UserSortUsername  UserSortField = "username"
+	UserSortFirstName UserSortField = "first_name"
+	UserSortLastName  UserSortField = "last_name"
+	// UserSortReading sorts by family then given name reading
+	UserSortReading     UserSortField = "reading"
+	UserSortLastLoginAt UserSortField = "last_login_at"
+	UserSortValidUntil  UserSortField = "valid_until"
+)
+
+// SortableUserFields lists the fields users can be sorted by. Users without a
+// value for reading, last_login_at or valid_until sort last in either direction.
+var SortableUserFields = []UserSortField{
+	UserSortCreatedAt,
+	UserSortUpdatedAt,
+	UserSortEmail,
+	UserSortUsername,
+	UserSortFirstName,
+	UserSortLastName,
+	UserSortReading,
+	UserSortLastLoginAt,
+	UserSortValidUntil,
+}
+
+// UserSortKey is one term of a user sort order
+type UserSortKey struct {
+	Field      UserSortField `json:"field"`
+	Descending bool          `json:"desc,omitempty"`
+}
+
+// DefaultUserSort lists the newest users first
+var DefaultUserSort = []UserSortKey{{Field: UserSortCreatedAt, Descending: true}}
+
+// ParseUserSort parses a comma-separated sort order such as "last_name asc, created_at desc".
+// Fields without a direction use defaultOrder ("asc" or "desc", ascending when empty).
+// An empty sort order yields DefaultUserSort. Unknown or repeated fields are rejected.
+func ParseUserSort(sortBy, defaultOrder string) ([]UserSortKey, error) {
+	defaultDescending, err := parseSortDirection(defaultOrder)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(sortBy) == "" {
+		return append([]UserSortKey(nil), DefaultUserSort...), nil
+	}
+
+	terms := strings.Split(sortBy, ",")
+	keys := make([]UserSortKey, 0, len(terms))
+	seen := make(map[UserSortField]bool, len(terms))
+	for _, term := range terms {
+		parts := strings.Fields(term)
+		if len(parts) == 0 || len(parts) > 2 {
+			return nil, fmt.Errorf("%w: malformed sort term %q", ErrInvalidSort, strings.TrimSpace(term))
+		}
+
+		field := UserSortField(parts[0])
+		if !field.IsSortable() {
+			return nil, fmt.Errorf("%w: unknown sort field %q", ErrInvalidSort, parts[0])
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("%w: field %q is sorted by more than once", ErrInvalidSort, parts[0])
+		}
+		seen[field] = true
+
+		key := UserSortKey{Field: field, Descending: defaultDescending}
+		if len(parts) == 2 {
+			if key.Descending, err = parseSortDirection(parts[1]); err != nil {
+				return nil, err
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// IsSortable reports whether users can be sorted by the field
+func (f UserSortField) IsSortable() bool {
+	for _, field := range SortableUserFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSortDirection reports whether a sort direction is descending
+func parseSortDirection(direction string) (bool, error) {
+	switch strings.ToLower(direction) {
+	case "", "asc":
+		return false, nil
+	case "desc":
+		return true, nil
+	default:
+		return false, fmt.Errorf("%w: sort order must be \"asc\" or \"desc\", got %q", ErrInvalidSort, direction)
+	}
+}
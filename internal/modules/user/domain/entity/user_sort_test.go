@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseUserSort(t *testing.T) {
+	tests := []struct {
+		name         string
+		sortBy       string
+		defaultOrder string
+		want         []UserSortKey
+	}{
+		{name: "empty", sortBy: "", want: DefaultUserSort},
+		{name: "blank", sortBy: "  ", defaultOrder: "asc", want: DefaultUserSort},
+		{name: "field", sortBy: "email", want: []UserSortKey{{Field: UserSortEmail}}},
+		{name: "default order", sortBy: "email", defaultOrder: "desc", want: []UserSortKey{{Field: UserSortEmail, Descending: true}}},
+		{name: "default order ignores case", sortBy: "email", defaultOrder: "DESC", want: []UserSortKey{{Field: UserSortEmail, Descending: true}}},
+		{name: "explicit order overrides default", sortBy: "email asc", defaultOrder: "desc", want: []UserSortKey{{Field: UserSortEmail}}},
+		{
+			name:   "several fields",
+			sortBy: "last_name asc, first_name, created_at desc",
+			want: []UserSortKey{
+				{Field: UserSortLastName},
+				{Field: UserSortFirstName},
+				{Field: UserSortCreatedAt, Descending: true},
+			},
+		},
+		{name: "extra whitespace", sortBy: " reading   DESC ,valid_until", want: []UserSortKey{{Field: UserSortReading, Descending: true}, {Field: UserSortValidUntil}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUserSort(tt.sortBy, tt.defaultOrder)
+			if err != nil {
+				t.Fatalf("ParseUserSort(%q, %q) error = %v", tt.sortBy, tt.defaultOrder, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUserSort(%q, %q) = %+v, want %+v", tt.sortBy, tt.defaultOrder, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUserSortErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		sortBy       string
+		defaultOrder string
+	}{
+		{name: "unknown field", sortBy: "password"},
+		{name: "unknown direction", sortBy: "email up"},
+		{name: "unknown default order", sortBy: "email", defaultOrder: "down"},
+		{name: "unknown default order without sort", defaultOrder: "down"},
+		{name: "repeated field", sortBy: "email, email desc"},
+		{name: "empty term", sortBy: "email,"},
+		{name: "too many words", sortBy: "email asc nulls"},
+		{name: "field names are case sensitive", sortBy: "Email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUserSort(tt.sortBy, tt.defaultOrder)
+			if !errors.Is(err, ErrInvalidSort) {
+				t.Fatalf("ParseUserSort(%q, %q) = %+v, %v, want ErrInvalidSort", tt.sortBy, tt.defaultOrder, got, err)
+			}
+		})
+	}
+}
+
+func TestParseUserSortDoesNotAliasDefault(t *testing.T) {
+	got, err := ParseUserSort("", "")
+	if err != nil {
+		t.Fatalf("ParseUserSort() error = %v", err)
+	}
+	got[0].Descending = !got[0].Descending
+	if DefaultUserSort[0].Descending == got[0].Descending {
+		t.Fatal("modifying the parsed sort changed DefaultUserSort")
+	}
+}
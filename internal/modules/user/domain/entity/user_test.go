@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseETag(t *testing.T) {
+	tests := []struct {
+		name    string
+		etag    string
+		want    int64
+		wantErr bool
+	}{
+		{name: "empty", etag: "", want: 0},
+		{name: "quoted", etag: `"3"`, want: 3},
+		{name: "unquoted", etag: "3", want: 3},
+		{name: "large", etag: `"9223372036854775807"`, want: 9223372036854775807},
+		{name: "zero", etag: `"0"`, wantErr: true},
+		{name: "negative", etag: `"-1"`, wantErr: true},
+		{name: "not a number", etag: `"abc"`, wantErr: true},
+		{name: "weak", etag: `W/"3"`, wantErr: true},
+		{name: "unterminated quote", etag: `"3`, wantErr: true},
+		{name: "overflow", etag: `"9223372036854775808"`, wantErr: true},
+		{name: "whitespace", etag: ` "3"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseETag(tt.etag)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidETag) {
+					t.Fatalf("ParseETag(%q) = %d, %v, want ErrInvalidETag", tt.etag, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseETag(%q) error = %v", tt.etag, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseETag(%q) = %d, want %d", tt.etag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestETagRoundTrip(t *testing.T) {
+	for _, version := range []int64{1, 2, 42, 1 << 40} {
+		user := &User{Version: version}
+		etag := user.ETag()
+		got, err := ParseETag(etag)
+		if err != nil {
+			t.Fatalf("ParseETag(%q) error = %v", etag, err)
+		}
+		if got != version {
+			t.Errorf("ParseETag(%q) = %d, want %d", etag, got, version)
+		}
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	user := &User{Version: 3}
+	tests := []struct {
+		name     string
+		expected int64
+		wantErr  error
+	}{
+		{name: "skipped", expected: 0},
+		{name: "current", expected: 3},
+		{name: "stale", expected: 2, wantErr: ErrETagMismatch},
+		{name: "ahead", expected: 4, wantErr: ErrETagMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := user.CheckVersion(tt.expected); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckVersion(%d) = %v, want %v", tt.expected, err, tt.wantErr)
+			}
+		})
+	}
+}
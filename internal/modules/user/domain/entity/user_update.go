@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// UserField identifies a mutable user field by its API path
+type UserField string
+
+const (
+	UserFieldEmail          UserField = "email"
+	UserFieldUsername       UserField = "username"
+	UserFieldFirstName      UserField = "first_name"
+	UserFieldLastName       UserField = "last_name"
+	UserFieldFamilyNameKana UserField = "family_name_kana"
+	UserFieldGivenNameKana  UserField = "given_name_kana"
+	UserFieldNameOrder      UserField = "name_order"
+	UserFieldLocale         UserField = "locale"
+	UserFieldIsActive       UserField = "is_active"
+	UserFieldIsAdmin        UserField = "is_admin"
+	UserFieldValidFrom      UserField = "valid_from"
+	UserFieldValidUntil     UserField = "valid_until"
+	UserFieldAttributes     UserField = "attributes"
+)
+
+// UpdateMaskWildcard selects every mutable field
+const UpdateMaskWildcard = "*"
+
+// MutableUserFields lists every field that can be changed by an update
+var MutableUserFields = []UserField{
+	UserFieldEmail,
+	UserFieldUsername,
+	UserFieldFirstName,
+	UserFieldLastName,
+	UserFieldFamilyNameKana,
+	UserFieldGivenNameKana,
+	UserFieldNameOrder,
+	UserFieldLocale,
+	UserFieldIsActive,
+	UserFieldIsAdmin,
+	UserFieldValidFrom,
+	UserFieldValidUntil,
+	UserFieldAttributes,
+}
+
+// ParseUpdateMask resolves update mask paths to user fields.
+// The wildcard expands to all mutable fields; unknown paths are rejected.
+func ParseUpdateMask(paths []string) ([]UserField, error) {
+	seen := make(map[UserField]bool, len(paths))
+	fields := make([]UserField, 0, len(paths))
+	for _, path := range paths {
+		if path == UpdateMaskWildcard {
+			if len(paths) > 1 {
+				return nil, fmt.Errorf("%w: %q cannot be combined with other paths", ErrInvalidUpdateMask, UpdateMaskWildcard)
+			}
+			return append([]UserField(nil), MutableUserFields...), nil
+		}
+
+		field := UserField(path)
+		if !field.IsMutable() {
+			return nil, fmt.Errorf("%w: unknown or immutable field %q", ErrInvalidUpdateMask, path)
+		}
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// IsMutable reports whether the field can be changed by an update
+func (f UserField) IsMutable() bool {
+	for _, field := range MutableUserFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// UserUpdate describes a partial update of a user. Only the listed fields are
+// applied; a listed field holding its zero value clears it where allowed.
+type UserUpdate struct {
+	Fields         []UserField
+	Email          string
+	Username       string
+	FirstName      string
+	LastName       string
+	FamilyNameKana string
+	GivenNameKana  string
+	NameOrder      NameOrder
+	Locale         string
+	IsActive       bool
+	IsAdmin        bool
+	ValidFrom      *time.Time
+	ValidUntil     *time.Time
+	Attributes     Attributes
+}
+
+// Has reports whether the update includes the field
+func (u *UserUpdate) Has(field UserField) bool {
+	for _, f := range u.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // Email represents a validated email address
@@ -11,9 +15,10 @@ type Email struct {
 	value string
 }
 
-// NewEmail creates a new Email value object
+// NewEmail creates a new Email value object.
+// Emails are compared case-insensitively, so the address is stored lowercased.
 func NewEmail(email string) (*Email, error) {
-	email = strings.TrimSpace(strings.ToLower(email))
+	email = strings.TrimSpace(strings.ToLower(norm.NFKC.String(email)))
 	if !isValidEmail(email) {
 		return nil, fmt.Errorf("%w: %s", ErrInvalidEmail, email)
 	}
@@ -35,15 +40,32 @@ type Username struct {
 	value string
 }
 
-// NewUsername creates a new Username value object
+// UsernamePolicy controls which characters usernames may contain
+type UsernamePolicy struct {
+	// AllowUnicode permits letters and digits from any script instead of ASCII only
+	AllowUnicode bool
+}
+
+// NewUsername creates a new ASCII-only Username value object
 func NewUsername(username string) (*Username, error) {
-	username = strings.TrimSpace(username)
-	if err := validateUsername(username); err != nil {
+	return UsernamePolicy{}.NewUsername(username)
+}
+
+// NewUsername creates a new Username value object under the policy.
+// The username is NFKC-normalized; its case is preserved but not significant.
+func (p UsernamePolicy) NewUsername(username string) (*Username, error) {
+	username = NormalizeUsername(username)
+	if err := validateUsername(username, p.AllowUnicode); err != nil {
 		return nil, err
 	}
 	return &Username{value: username}, nil
 }
 
+// NormalizeUsername applies the normalization usernames are stored with
+func NormalizeUsername(username string) string {
+	return strings.TrimSpace(norm.NFKC.String(username))
+}
+
 // String returns the string representation of the username
 func (u Username) String() string {
 	return u.value
@@ -54,6 +76,11 @@ func (u Username) Value() string {
 	return u.value
 }
 
+// Skeleton returns the form used to detect look-alike usernames
+func (u Username) Skeleton() string {
+	return UsernameSkeleton(u.value)
+}
+
 // Password represents a hashed password
 type Password struct {
 	hash string
@@ -81,11 +108,11 @@ func NewPersonName(firstName, lastName string) (*PersonName, error) {
 	lastName = strings.TrimSpace(lastName)
 	
 	if firstName == "" && lastName == "" {
-		return nil, fmt.Errorf("at least one name must be provided")
+		return nil, fmt.Errorf("%w: at least one name must be provided", ErrInvalidName)
 	}
 	
 	if len(firstName) > 100 || len(lastName) > 100 {
-		return nil, fmt.Errorf("name too long")
+		return nil, fmt.Errorf("%w: name too long", ErrInvalidName)
 	}
 	
 	return &PersonName{
@@ -94,15 +121,9 @@ func NewPersonName(firstName, lastName string) (*PersonName, error) {
 	}, nil
 }
 
-// FullName returns the full name
-func (n PersonName) FullName() string {
-	if n.FirstName == "" {
-		return n.LastName
-	}
-	if n.LastName == "" {
-		return n.FirstName
-	}
-	return fmt.Sprintf("%s %s", n.FirstName, n.LastName)
+// FullName returns the full name with the given and family names in the given order
+func (n PersonName) FullName(order NameOrder) string {
+	return FormatFullName(n.FirstName, n.LastName, order)
 }
 
 // Helper functions
@@ -116,19 +137,39 @@ func isValidEmail(email string) bool {
 	return emailRegex.MatchString(email)
 }
 
-func validateUsername(username string) error {
-	if len(username) < 3 {
+var asciiUsernameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+func validateUsername(username string, allowUnicode bool) error {
+	length := utf8.RuneCountInString(username)
+	if length < 3 {
 		return fmt.Errorf("%w: username must be at least 3 characters", ErrInvalidUsername)
 	}
-	if len(username) > 100 {
+	if length > 100 {
 		return fmt.Errorf("%w: username must not exceed 100 characters", ErrInvalidUsername)
 	}
-	
-	// Username must start with a letter and contain only letters, numbers, and underscores
-	usernameRegex := regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
-	if !usernameRegex.MatchString(username) {
-		return fmt.Errorf("%w: username must start with a letter and contain only letters, numbers, and underscores", ErrInvalidUsername)
+
+	if !allowUnicode {
+		// Username must start with a letter and contain only letters, numbers, and underscores
+		if !asciiUsernameRegex.MatchString(username) {
+			return fmt.Errorf("%w: username must start with a letter and contain only letters, numbers, and underscores", ErrInvalidUsername)
+		}
+		return nil
 	}
-	
+
+	// Unicode usernames follow the same shape with letters and digits from any script
+	for i, r := range username {
+		switch {
+		case unicode.IsLetter(r):
+		case i == 0:
+			return fmt.Errorf("%w: username must start with a letter", ErrInvalidUsername)
+		case unicode.Is(unicode.Nd, r), unicode.Is(unicode.M, r), r == '_':
+		default:
+			return fmt.Errorf("%w: username must contain only letters, numbers, and underscores", ErrInvalidUsername)
+		}
+	}
+	if mixesConfusableScripts(username) {
+		return fmt.Errorf("%w: username must not mix Latin, Greek, and Cyrillic letters", ErrInvalidUsername)
+	}
+
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+)
+
+// AccountNotifier delivers account lifecycle notifications to users
+type AccountNotifier interface {
+	// NotifyAccountExpired tells a user that their account was deactivated because its validity window ended
+	NotifyAccountExpired(ctx context.Context, user *entity.User) error
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/google/uuid"
+)
+
+// AttributeDefinitionRepository defines the interface for attribute definition data operations
+type AttributeDefinitionRepository interface {
+	// Create creates a new attribute definition
+	Create(ctx context.Context, definition *entity.AttributeDefinition) error
+
+	// GetByID retrieves an attribute definition by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.AttributeDefinition, error)
+
+	// GetByKey retrieves an attribute definition by key
+	GetByKey(ctx context.Context, key string) (*entity.AttributeDefinition, error)
+
+	// List retrieves all attribute definitions ordered by key
+	List(ctx context.Context) ([]*entity.AttributeDefinition, error)
+
+	// Update updates an existing attribute definition
+	Update(ctx context.Context, definition *entity.AttributeDefinition) error
+
+	// Delete deletes an attribute definition
+	Delete(ctx context.Context, id uuid.UUID) error
+}
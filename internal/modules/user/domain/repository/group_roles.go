@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// GroupRoles is the view of the group module the user module depends on
+type GroupRoles interface {
+	// TenantRoles returns the roles a user holds through groups of the current tenant
+	TenantRoles(ctx context.Context, userID uuid.UUID) ([]string, error)
+}
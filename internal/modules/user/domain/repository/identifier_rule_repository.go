@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/google/uuid"
+)
+
+// IdentifierRuleRepository defines the interface for identifier rule data operations
+type IdentifierRuleRepository interface {
+	// Create creates a new identifier rule
+	Create(ctx context.Context, rule *entity.IdentifierRule) error
+
+	// CreateMissing creates the rules that do not exist yet and returns how many were created
+	CreateMissing(ctx context.Context, rules []*entity.IdentifierRule) (int64, error)
+
+	// List retrieves identifier rules ordered by kind and value.
+	// An empty kind lists rules of every kind.
+	List(ctx context.Context, kind entity.IdentifierRuleKind) ([]*entity.IdentifierRule, error)
+
+	// Delete deletes an identifier rule
+	Delete(ctx context.Context, id uuid.UUID) error
+}
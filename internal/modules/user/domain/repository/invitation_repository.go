@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/google/uuid"
+)
+
+// InvitationRepository defines the interface for invitation data operations
+type InvitationRepository interface {
+	// Create creates a new invitation
+	Create(ctx context.Context, invitation *entity.Invitation) error
+
+	// GetByID retrieves an invitation by ID within the current tenant
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Invitation, error)
+
+	// GetByTokenHash retrieves an invitation by the hash of its token, across all tenants
+	GetByTokenHash(ctx context.Context, tokenHash string) (*entity.Invitation, error)
+
+	// ExistsPending checks if an unexpired pending invitation exists for an email in an organization
+	ExistsPending(ctx context.Context, organizationID *uuid.UUID, email string, now time.Time) (bool, error)
+
+	// ListPending retrieves unexpired pending invitations of the current tenant with
+	// pagination, or those outside any organization when no tenant is set
+	ListPending(ctx context.Context, now time.Time, offset, limit int) ([]*entity.Invitation, error)
+
+	// CountPending returns the number of unexpired pending invitations of the current
+	// tenant, or of those outside any organization when no tenant is set
+	CountPending(ctx context.Context, now time.Time) (int64, error)
+
+	// MarkAccepted records that a pending invitation was accepted by a user
+	MarkAccepted(ctx context.Context, id, userID uuid.UUID, at time.Time) error
+
+	// MarkRevoked records that a pending invitation was revoked
+	MarkRevoked(ctx context.Context, id uuid.UUID, at time.Time) error
+}
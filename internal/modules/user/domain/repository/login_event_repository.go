@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/google/uuid"
+)
+
+// LoginEventFilter selects the login events of a user within an optional time range
+type LoginEventFilter struct {
+	UserID uuid.UUID
+
+	// From and To bound the event time; From is inclusive and To is exclusive
+	From *time.Time
+	To   *time.Time
+}
+
+// LoginEventRepository defines the interface for login event data operations
+type LoginEventRepository interface {
+	// Create records a login event
+	Create(ctx context.Context, event *entity.LoginEvent) error
+
+	// List retrieves login events matching the filter, newest first, with pagination
+	List(ctx context.Context, filter *LoginEventFilter, offset, limit int) ([]*entity.LoginEvent, error)
+
+	// Count returns the number of login events matching the filter
+	Count(ctx context.Context, filter *LoginEventFilter) (int64, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// OrganizationMembers is the view of the organization module invitations depend on
+type OrganizationMembers interface {
+	// ValidateMemberRoles checks that an organization exists and that the roles can be granted in it
+	ValidateMemberRoles(ctx context.Context, organizationID uuid.UUID, roles []string) error
+
+	// GrantMemberRoles grants roles to an existing member of an organization
+	GrantMemberRoles(ctx context.Context, organizationID, userID uuid.UUID, roles []string) error
+
+	// IsOrganizationAdmin reports whether a user is an owner or admin of an organization
+	IsOrganizationAdmin(ctx context.Context, organizationID, userID uuid.UUID) (bool, error)
+}
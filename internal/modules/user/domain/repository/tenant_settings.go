@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+)
+
+// TenantSettings is the view of the organization module the user module depends on
+type TenantSettings interface {
+	// UniquenessScope returns where user identifiers must be unique for an organization
+	UniquenessScope(ctx context.Context, organizationID uuid.UUID) (tenant.UniquenessScope, error)
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+)
+
+// UserChangePosition is a position in the user change log. Changes are ordered by
+// the transaction that made them, then by sequence number, and a change is only
+// read once every older transaction has ended. A reader therefore never passes a
+// change that has yet to commit, although changes of one user made by concurrent
+// transactions may be read out of order.
+type UserChangePosition struct {
+	TxID int64 `json:"t"`
+	Seq  int64 `json:"s"`
+}
+
+// Before reports whether p comes before other in the log
+func (p UserChangePosition) Before(other UserChangePosition) bool {
+	return p.TxID < other.TxID || (p.TxID == other.TxID && p.Seq < other.Seq)
+}
+
+// UserChangeRepository defines the interface for reading the user change log
+type UserChangeRepository interface {
+	// CurrentPosition returns the position after every change committed so far
+	CurrentPosition(ctx context.Context) (UserChangePosition, error)
+
+	// ListAfter retrieves up to limit changes after a position, in log order, whose
+	// user matches the filter before or after the change. It also returns the
+	// position to continue from, which is past every change read so far even
+	// when none of them matched.
+	ListAfter(ctx context.Context, after UserChangePosition, filter *UserFilter, limit int) ([]*entity.UserChange, UserChangePosition, error)
+
+	// PrunedPosition returns the position up to which changes have been pruned.
+	// Reading after an earlier position would miss changes.
+	PrunedPosition(ctx context.Context) (UserChangePosition, error)
+
+	// Prune deletes the committed changes made before a time, along with every
+	// change before them in the log, and returns the number deleted
+	Prune(ctx context.Context, before time.Time) (int64, error)
+
+	// Subscribe returns a channel that receives a value when changes may have been
+	// committed since the last receive, until ctx is done. Notifications may be
+	// lost while the database is unreachable, so readers should also poll.
+	Subscribe(ctx context.Context) <-chan struct{}
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/shared/filter"
 	"github.com/google/uuid"
 )
 
@@ -15,17 +17,52 @@ type UserRepository interface {
 	// GetByID retrieves a user by ID
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
 
-	// GetByEmail retrieves a user by email
+	// GetByIDs retrieves the users with the given IDs in one query, in no particular
+	// order. IDs of missing users are skipped rather than reported as errors.
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.User, error)
+
+	// GetByEmail retrieves a user by email, ignoring case
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 
-	// GetByUsername retrieves a user by username
+	// GetByUsername retrieves a user by username, ignoring case
 	GetByUsername(ctx context.Context, username string) (*entity.User, error)
 
-	// Update updates an existing user
+	// Update updates an existing user if it still has the version it was read with,
+	// and increments the version. It returns entity.ErrConcurrentModification otherwise.
 	Update(ctx context.Context, user *entity.User) error
 
-	// Delete soft deletes a user
-	Delete(ctx context.Context, id uuid.UUID) error
+	// UpdateLastLogin records a successful sign-in without changing the user's version
+	UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// Delete soft deletes a user. A non-zero expected version makes the delete
+	// conditional, returning entity.ErrConcurrentModification on mismatch.
+	Delete(ctx context.Context, id uuid.UUID, expectedVersion int64) error
+
+	// Merge soft-deletes the source user of a merge, writes the resolved target user,
+	// moves the source's memberships, login history, preferences and accepted
+	// invitations to the target and records the audit event, all in one transaction.
+	// A dry run performs the same writes and rolls them back. Either user having
+	// changed since it was read yields entity.ErrConcurrentModification.
+	Merge(ctx context.Context, merge *entity.UserMerge, dryRun bool) (*entity.MergedReferences, error)
+
+	// CreateBatch creates users with multi-row inserts in one transaction and, within
+	// a tenant, makes them members of that tenant. It returns the error of each user
+	// that could not be created, indexed like users. When atomic, any such error
+	// rolls back the whole batch.
+	CreateBatch(ctx context.Context, users []*entity.User, atomic bool) ([]error, error)
+
+	// UpdateBatch updates users that still have the versions they were read with in
+	// one transaction, and increments their versions. Users that changed since yield
+	// entity.ErrConcurrentModification. Errors are returned like CreateBatch's.
+	UpdateBatch(ctx context.Context, users []*entity.User, atomic bool) ([]error, error)
+
+	// DeleteBatch soft deletes users in one transaction. A non-zero expected version
+	// makes a delete conditional like Delete's. Errors are returned like CreateBatch's.
+	DeleteBatch(ctx context.Context, deletions []entity.UserDeletion, atomic bool) ([]error, error)
+
+	// ListExpired retrieves up to limit active users whose validity window ended
+	// at or before now, earliest expiry first
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*entity.User, error)
 
 	// List retrieves users with pagination
 	List(ctx context.Context, offset, limit int) ([]*entity.User, error)
@@ -36,25 +73,109 @@ type UserRepository interface {
 	// Exists checks if a user exists by ID
 	Exists(ctx context.Context, id uuid.UUID) (bool, error)
 
-	// ExistsByEmail checks if a user exists by email
+	// ExistsByEmail checks if a user exists by email, ignoring case
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 
-	// ExistsByUsername checks if a user exists by username
+	// ExistsByUsername checks if a user exists by username, ignoring case
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
 }
 
+// StringMatch selects how a string filter compares to a field. Every mode
+// ignores case and matches the value literally, without wildcards.
+type StringMatch string
+
+const (
+	StringMatchExact    StringMatch = "exact"
+	StringMatchPrefix   StringMatch = "prefix"
+	StringMatchContains StringMatch = "contains"
+)
+
+// StringFilter matches a string field against a value
+type StringFilter struct {
+	Value string
+	Match StringMatch
+}
+
+// TimeRange matches times at or after From and before To; nil leaves that side open
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
 // UserFilter represents filter options for listing users
 type UserFilter struct {
-	Email    *string
-	Username *string
-	IsActive *bool
-	IsAdmin  *bool
+	Email     *StringFilter
+	Username  *StringFilter
+	FirstName *StringFilter
+	LastName  *StringFilter
+	IsActive  *bool
+	IsAdmin   *bool
+
+	// Status matches users with the status, active or inactive; deleted users
+	// never match, so suspended is not a status it takes
+	Status *entity.UserStatus
+
+	// IDs restricts the users to these IDs when not empty; ExcludeIDs removes users
+	IDs        []uuid.UUID
+	ExcludeIDs []uuid.UUID
+
+	CreatedAt *TimeRange
+	UpdatedAt *TimeRange
+
+	// Reading matches a normalized katakana substring of the family and given name readings
+	Reading *string
+
+	// Attributes matches users whose custom attributes contain all given values
+	Attributes entity.Attributes
+
+	// ExpiringBefore matches active users whose validity window has not ended yet
+	// but ends at or before this time
+	ExpiringBefore *time.Time
+
+	// Expression is a filter expression checked against entity.UserFilterSchema
+	Expression filter.Expr
 }
 
-// UserSortOptions represents sort options for listing users
+// UserSortOptions represents sort options for listing users. Users are ordered
+// by the keys in turn, then by ID so that every user has a stable position.
 type UserSortOptions struct {
-	Field string // "created_at", "updated_at", "email", "username"
-	Order string // "asc" or "desc"
+	Keys []entity.UserSortKey
+}
+
+// UserCursor holds the sort key values of the last user of a page. Keyset
+// pagination continues with the users that sort after it.
+type UserCursor struct {
+	ID             uuid.UUID  `json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Email          string     `json:"email,omitempty"`
+	Username       string     `json:"username,omitempty"`
+	FirstName      string     `json:"first_name,omitempty"`
+	LastName       string     `json:"last_name,omitempty"`
+	FamilyNameKana string     `json:"family_name_kana,omitempty"`
+	GivenNameKana  string     `json:"given_name_kana,omitempty"`
+	LastLoginAt    *time.Time `json:"last_login_at,omitempty"`
+	ValidUntil     *time.Time `json:"valid_until,omitempty"`
+
+	// Score is the relevance of the user when searching by relevance
+	Score float64 `json:"score,omitempty"`
+}
+
+// NewUserCursor returns the cursor positioned at the given user
+func NewUserCursor(user *entity.User) *UserCursor {
+	return &UserCursor{
+		ID:             user.ID,
+		CreatedAt:      user.CreatedAt,
+		UpdatedAt:      user.UpdatedAt,
+		Email:          user.Email,
+		Username:       user.Username,
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		FamilyNameKana: user.FamilyNameKana,
+		GivenNameKana:  user.GivenNameKana,
+		LastLoginAt:    user.LastLoginAt,
+		ValidUntil:     user.ValidUntil,
+	}
 }
 
 // ListOptions represents options for listing users
@@ -63,6 +184,38 @@ type ListOptions struct {
 	Limit  int
 	Filter *UserFilter
 	Sort   *UserSortOptions
+
+	// After continues the listing with the users sorting after the cursor; Offset is ignored when set
+	After *UserCursor
+}
+
+// UserSearchOptions represents options for searching users
+type UserSearchOptions struct {
+	// Query is matched against email, username, first and last name by trigram
+	// similarity and prefix, and against name readings when it is kana.
+	// An empty query matches every user.
+	Query  string
+	Offset int
+	Limit  int
+	Filter *UserFilter
+
+	// Sort orders the matches; nil orders them by relevance, best first
+	Sort *UserSortOptions
+
+	// After continues the search with the users sorting after the cursor; Offset is ignored when set
+	After *UserCursor
+}
+
+// UserSearchResult is a user matching a search
+type UserSearchResult struct {
+	User *entity.User
+
+	// Score is the relevance of the user to the query, higher is better.
+	// Prefix matches score above approximate ones.
+	Score float64
+
+	// MatchedFields lists the fields the query matched
+	MatchedFields []entity.UserSearchField
 }
 
 // UserRepositoryWithFilters extends UserRepository with advanced filtering
@@ -74,4 +227,10 @@ type UserRepositoryWithFilters interface {
 
 	// CountWithFilter returns the count of users matching the filter
 	CountWithFilter(ctx context.Context, filter *UserFilter) (int64, error)
+
+	// Search retrieves users matching a search query and filter with their relevance
+	Search(ctx context.Context, opts *UserSearchOptions) ([]*UserSearchResult, error)
+
+	// CountSearch returns the count of users matching a search query and filter
+	CountSearch(ctx context.Context, query string, filter *UserFilter) (int64, error)
 }
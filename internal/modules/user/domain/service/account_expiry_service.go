@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+)
+
+// expiryBatchSize is how many expired users are deactivated per query
+const expiryBatchSize = 100
+
+// AccountExpiryService deactivates users whose validity window has ended.
+// Sign-in is denied at the end of the window regardless; deactivating the
+// user makes the expiry visible and lets the user be notified.
+type AccountExpiryService struct {
+	userRepo repository.UserRepository
+	notifier repository.AccountNotifier
+}
+
+// NewAccountExpiryService creates a new instance of AccountExpiryService
+func NewAccountExpiryService(userRepo repository.UserRepository, notifier repository.AccountNotifier) *AccountExpiryService {
+	return &AccountExpiryService{
+		userRepo: userRepo,
+		notifier: notifier,
+	}
+}
+
+// ExpireAccounts deactivates every active user of every tenant whose validity
+// window ended at or before now and notifies them. It returns the number of
+// users deactivated. Users changed concurrently are left for the next run.
+func (s *AccountExpiryService) ExpireAccounts(ctx context.Context, now time.Time) (int, error) {
+	ctx = tenant.Unscoped(ctx)
+
+	expired := 0
+	for {
+		users, err := s.userRepo.ListExpired(ctx, now, expiryBatchSize)
+		if err != nil {
+			return expired, fmt.Errorf("failed to list expired users: %w", err)
+		}
+
+		deactivated := 0
+		for _, user := range users {
+			user.IsActive = false
+			if err := s.userRepo.Update(ctx, user); err != nil {
+				if errors.Is(err, entity.ErrConcurrentModification) {
+					continue
+				}
+				return expired, fmt.Errorf("failed to deactivate user %s: %w", user.ID, err)
+			}
+			deactivated++
+
+			// A failed notification is logged so that it does not block other expiries
+			if err := s.notifier.NotifyAccountExpired(ctx, user); err != nil {
+				log.Printf("Failed to notify user %s of account expiry: %v", user.ID, err)
+			}
+		}
+		expired += deactivated
+
+		// Stop when the last batch was partial, or when every user in it was
+		// skipped and the next query would return the same users again
+		if len(users) < expiryBatchSize || deactivated == 0 {
+			return expired, nil
+		}
+	}
+}
+
+// Run expires accounts once per interval until the context is cancelled
+func (s *AccountExpiryService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		expired, err := s.ExpireAccounts(ctx, time.Now())
+		if err != nil {
+			log.Printf("Failed to expire accounts: %v", err)
+		} else if expired > 0 {
+			log.Printf("Deactivated %d expired accounts", expired)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+)
+
+// AttributeService provides domain services for custom user attributes
+type AttributeService struct {
+	attributeRepo repository.AttributeDefinitionRepository
+}
+
+// NewAttributeService creates a new instance of AttributeService
+func NewAttributeService(attributeRepo repository.AttributeDefinitionRepository) *AttributeService {
+	return &AttributeService{
+		attributeRepo: attributeRepo,
+	}
+}
+
+// CreateDefinition validates and registers a new attribute definition
+func (s *AttributeService) CreateDefinition(ctx context.Context, definition *entity.AttributeDefinition) error {
+	if definition.DisplayName == "" {
+		definition.DisplayName = definition.Key
+	}
+	if err := definition.Validate(); err != nil {
+		return err
+	}
+
+	// Check if key is already registered
+	existing, err := s.attributeRepo.GetByKey(ctx, definition.Key)
+	if err != nil && !errors.Is(err, entity.ErrAttributeDefinitionNotFound) {
+		return fmt.Errorf("failed to check attribute definition existence: %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: %s", entity.ErrAttributeDefinitionAlreadyExists, definition.Key)
+	}
+
+	if err := s.attributeRepo.Create(ctx, definition); err != nil {
+		return fmt.Errorf("failed to create attribute definition: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateDefinition validates and saves changes to an attribute definition
+func (s *AttributeService) UpdateDefinition(ctx context.Context, definition *entity.AttributeDefinition) error {
+	if err := definition.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.attributeRepo.Update(ctx, definition); err != nil {
+		return fmt.Errorf("failed to update attribute definition: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateAttributes validates a complete set of attribute values for a user.
+// Uniqueness is enforced by the database when the user is saved.
+func (s *AttributeService) ValidateAttributes(ctx context.Context, attributes entity.Attributes) error {
+	definitions, err := s.definitionsByKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Reject attributes that are not registered
+	for _, key := range sortedKeys(attributes) {
+		if _, ok := definitions[key]; !ok {
+			return fmt.Errorf("%w: unknown attribute %q", entity.ErrInvalidAttribute, key)
+		}
+	}
+
+	for _, key := range sortedDefinitionKeys(definitions) {
+		definition := definitions[key]
+		value, ok := attributes[key]
+		if !ok || value == nil {
+			if definition.Required {
+				return fmt.Errorf("%w: %s is required", entity.ErrInvalidAttribute, key)
+			}
+			continue
+		}
+
+		if err := definition.ValidateValue(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UniqueKeys returns the keys of the unique attributes in order
+func (s *AttributeService) UniqueKeys(ctx context.Context) ([]string, error) {
+	definitions, err := s.definitionsByKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, key := range sortedDefinitionKeys(definitions) {
+		if definitions[key].Unique {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// ValidateFilter checks that every filtered attribute is registered and indexed
+func (s *AttributeService) ValidateFilter(ctx context.Context, attributes entity.Attributes) error {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	definitions, err := s.definitionsByKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range sortedKeys(attributes) {
+		definition, ok := definitions[key]
+		if !ok {
+			return fmt.Errorf("%w: unknown attribute %q", entity.ErrInvalidAttribute, key)
+		}
+		if !definition.Indexed {
+			return fmt.Errorf("%w: attribute %q is not indexed and cannot be filtered on", entity.ErrInvalidAttribute, key)
+		}
+		if err := definition.ValidateValue(attributes[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// definitionsByKey loads all attribute definitions keyed by attribute key
+func (s *AttributeService) definitionsByKey(ctx context.Context) (map[string]*entity.AttributeDefinition, error) {
+	definitions, err := s.attributeRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attribute definitions: %w", err)
+	}
+
+	byKey := make(map[string]*entity.AttributeDefinition, len(definitions))
+	for _, definition := range definitions {
+		byKey[definition.Key] = definition
+	}
+	return byKey, nil
+}
+
+// sortedKeys returns attribute keys in a deterministic order
+func sortedKeys(attributes entity.Attributes) []string {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedDefinitionKeys returns definition keys in a deterministic order
+func sortedDefinitionKeys(definitions map[string]*entity.AttributeDefinition) []string {
+	keys := make([]string, 0, len(definitions))
+	for key := range definitions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/google/uuid"
+)
+
+// identifierPolicyTTL is how long a compiled policy is used before the rules are
+// read again, which bounds how long changes made by other servers go unnoticed
+const identifierPolicyTTL = time.Minute
+
+// IdentifierRuleService provides domain services for reserved and blocked identifiers.
+// The rules are compiled into a policy once and cached until they change.
+type IdentifierRuleService struct {
+	ruleRepo repository.IdentifierRuleRepository
+
+	mu         sync.Mutex
+	policy     *entity.IdentifierPolicy
+	loadedAt   time.Time
+	generation uint64
+}
+
+// NewIdentifierRuleService creates a new instance of IdentifierRuleService
+func NewIdentifierRuleService(ruleRepo repository.IdentifierRuleRepository) *IdentifierRuleService {
+	return &IdentifierRuleService{
+		ruleRepo: ruleRepo,
+	}
+}
+
+// CreateRule validates and registers a new identifier rule
+func (s *IdentifierRuleService) CreateRule(ctx context.Context, rule *entity.IdentifierRule) error {
+	rule.Normalize()
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return fmt.Errorf("failed to create identifier rule: %w", err)
+	}
+	s.invalidatePolicy()
+
+	return nil
+}
+
+// DeleteRule removes an identifier rule
+func (s *IdentifierRuleService) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	if err := s.ruleRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete identifier rule: %w", err)
+	}
+	s.invalidatePolicy()
+
+	return nil
+}
+
+// ImportRules registers rules of one kind, skipping values that are already registered
+func (s *IdentifierRuleService) ImportRules(ctx context.Context, kind entity.IdentifierRuleKind, values []string) (int64, error) {
+	rules := make([]*entity.IdentifierRule, 0, len(values))
+	for _, value := range values {
+		rule := &entity.IdentifierRule{Kind: kind, Value: value}
+		rule.Normalize()
+		if err := rule.Validate(); err != nil {
+			return 0, err
+		}
+		rules = append(rules, rule)
+	}
+
+	created, err := s.ruleRepo.CreateMissing(ctx, rules)
+	if err != nil {
+		return 0, fmt.Errorf("failed to import identifier rules: %w", err)
+	}
+	if created > 0 {
+		s.invalidatePolicy()
+	}
+	return created, nil
+}
+
+// CheckIdentifiers checks an email and username against the registered rules.
+// An empty email or username is not checked.
+func (s *IdentifierRuleService) CheckIdentifiers(ctx context.Context, email, username string) error {
+	if email == "" && username == "" {
+		return nil
+	}
+
+	policy, err := s.currentPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
+	if username != "" {
+		if err := policy.CheckUsername(username); err != nil {
+			return err
+		}
+	}
+	if email != "" {
+		if err := policy.CheckEmail(email); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentPolicy returns the compiled policy of the registered rules, compiling it
+// when it is not cached or has expired
+func (s *IdentifierRuleService) currentPolicy(ctx context.Context) (*entity.IdentifierPolicy, error) {
+	s.mu.Lock()
+	policy, generation := s.policy, s.generation
+	fresh := policy != nil && time.Since(s.loadedAt) < identifierPolicyTTL
+	s.mu.Unlock()
+	if fresh {
+		return policy, nil
+	}
+
+	loadedAt := time.Now()
+	rules, err := s.ruleRepo.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identifier rules: %w", err)
+	}
+	policy, err = entity.NewIdentifierPolicy(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	// A policy read before the rules changed must not replace the invalidated one
+	s.mu.Lock()
+	if s.generation == generation {
+		s.policy = policy
+		s.loadedAt = loadedAt
+	}
+	s.mu.Unlock()
+	return policy, nil
+}
+
+// invalidatePolicy discards the cached policy after the rules changed
+func (s *IdentifierRuleService) invalidatePolicy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policy = nil
+	s.generation++
+}
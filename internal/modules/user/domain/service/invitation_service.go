@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/auth"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+)
+
+// invitationTokenBytes is the amount of randomness in an invitation token
+const invitationTokenBytes = 32
+
+// InvitationService provides domain services for inviting users
+type InvitationService struct {
+	invitationRepo      repository.InvitationRepository
+	userService         *UserService
+	organizationMembers repository.OrganizationMembers
+	transactor          repository.Transactor
+	ttl                 time.Duration
+}
+
+// NewInvitationService creates a new instance of InvitationService
+func NewInvitationService(invitationRepo repository.InvitationRepository, userService *UserService, organizationMembers repository.OrganizationMembers, transactor repository.Transactor, ttl time.Duration) *InvitationService {
+	return &InvitationService{
+		invitationRepo:      invitationRepo,
+		userService:         userService,
+		organizationMembers: organizationMembers,
+		transactor:          transactor,
+		ttl:                 ttl,
+	}
+}
+
+// InviteUser validates and creates a pending invitation and returns its token.
+// The token is not stored and cannot be retrieved later.
+func (s *InvitationService) InviteUser(ctx context.Context, invitation *entity.Invitation) (string, error) {
+	// Validate email
+	email, err := entity.NewEmail(invitation.Email)
+	if err != nil {
+		return "", err
+	}
+	invitation.Email = email.Value()
+
+	// Reject blocked email domains before anyone is invited
+	if err := s.userService.CheckEmailAllowed(ctx, invitation.Email); err != nil {
+		return "", err
+	}
+
+	// Invitations are created in the current tenant unless another organization is given
+	if organizationID, ok := tenant.OrganizationIDFromContext(ctx); ok {
+		if invitation.OrganizationID == nil {
+			invitation.OrganizationID = &organizationID
+		} else if *invitation.OrganizationID != organizationID {
+			return "", fmt.Errorf("%w: organization does not match the current tenant", entity.ErrInvalidInvitation)
+		}
+	}
+
+	// Only administrators of the organization invite users into it
+	if err := s.RequireOrganizationAdmin(ctx, invitation.OrganizationID); err != nil {
+		return "", err
+	}
+
+	// Validate roles
+	invitation.Roles = uniqueStrings(invitation.Roles)
+	inviteCtx := ctx
+	if invitation.OrganizationID != nil {
+		inviteCtx = tenant.WithOrganizationID(ctx, *invitation.OrganizationID)
+		if err := s.organizationMembers.ValidateMemberRoles(inviteCtx, *invitation.OrganizationID, invitation.Roles); err != nil {
+			return "", err
+		}
+	} else if len(invitation.Roles) > 0 {
+		return "", fmt.Errorf("%w: roles require an organization", entity.ErrInvalidInvitation)
+	}
+
+	// Check if email is already in use
+	emailInUse, err := s.userService.EmailInUse(inviteCtx, invitation.Email)
+	if err != nil {
+		return "", err
+	}
+	if emailInUse {
+		return "", fmt.Errorf("%w: email already in use", entity.ErrUserAlreadyExists)
+	}
+
+	now := time.Now()
+	pending, err := s.invitationRepo.ExistsPending(ctx, invitation.OrganizationID, invitation.Email, now)
+	if err != nil {
+		return "", fmt.Errorf("failed to check invitation existence: %w", err)
+	}
+	if pending {
+		return "", fmt.Errorf("%w: %s has a pending invitation", entity.ErrInvitationAlreadyExists, invitation.Email)
+	}
+
+	token, err := generateInvitationToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+
+	invitation.TokenHash = hashInvitationToken(token)
+	invitation.Status = entity.InvitationStatusPending
+	invitation.ExpiresAt = now.Add(s.ttl)
+
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return "", fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	return token, nil
+}
+
+// AcceptInvitation creates the invited user and marks the invitation as accepted.
+// The user's email is taken from the invitation. Either the invitation is accepted
+// along with the user and their roles, or nothing is written.
+func (s *InvitationService) AcceptInvitation(ctx context.Context, token string, user *entity.User, plainPassword string) (*entity.Invitation, error) {
+	invitation, err := s.invitationRepo.GetByTokenHash(ctx, hashInvitationToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	now := time.Now()
+	if err := invitation.CanAccept(now); err != nil {
+		return nil, err
+	}
+
+	// The user joins the organization the invitation was issued for
+	acceptCtx := ctx
+	if invitation.OrganizationID != nil {
+		acceptCtx = tenant.WithOrganizationID(ctx, *invitation.OrganizationID)
+	}
+
+	user.Email = invitation.Email
+	user.ID = uuid.New()
+	err = s.transactor.WithinTransaction(acceptCtx, func(txCtx context.Context) error {
+		// Claim the invitation first: a concurrent acceptance or revocation waits for
+		// this transaction and then finds the invitation no longer pending
+		if err := s.invitationRepo.MarkAccepted(txCtx, invitation.ID, user.ID, now); err != nil {
+			return fmt.Errorf("failed to accept invitation: %w", err)
+		}
+
+		if err := s.userService.CreateUser(txCtx, user, plainPassword); err != nil {
+			return err
+		}
+
+		if invitation.OrganizationID != nil && len(invitation.Roles) > 0 {
+			if err := s.organizationMembers.GrantMemberRoles(txCtx, *invitation.OrganizationID, user.ID, invitation.Roles); err != nil {
+				return fmt.Errorf("failed to grant invitation roles: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invitation.Status = entity.InvitationStatusAccepted
+	invitation.AcceptedAt = &now
+	invitation.AcceptedUserID = &user.ID
+	return invitation, nil
+}
+
+// RevokeInvitation revokes a pending invitation
+func (s *InvitationService) RevokeInvitation(ctx context.Context, id uuid.UUID) error {
+	invitation, err := s.invitationRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	// Only administrators of the organization revoke its invitations
+	if err := s.RequireOrganizationAdmin(ctx, invitation.OrganizationID); err != nil {
+		return err
+	}
+
+	if invitation.Status != entity.InvitationStatusPending {
+		return entity.ErrInvitationNotPending
+	}
+
+	if err := s.invitationRepo.MarkRevoked(ctx, id, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+
+	return nil
+}
+
+// RequireOrganizationAdmin returns ErrAdminRequired unless the caller administers
+// the organization invitations are managed in: an administrator of it as a tenant,
+// or an owner or admin of it. Invitations outside any organization require an
+// administrator.
+func (s *InvitationService) RequireOrganizationAdmin(ctx context.Context, organizationID *uuid.UUID) error {
+	if organizationID == nil {
+		return s.userService.RequireAdmin(ctx)
+	}
+
+	isAdmin, err := s.userService.IsAdmin(tenant.WithOrganizationID(ctx, *organizationID))
+	if err != nil {
+		return err
+	}
+	if isAdmin {
+		return nil
+	}
+
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return entity.ErrAdminRequired
+	}
+	isOrganizationAdmin, err := s.organizationMembers.IsOrganizationAdmin(ctx, *organizationID, claims.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to check caller membership: %w", err)
+	}
+	if !isOrganizationAdmin {
+		return entity.ErrAdminRequired
+	}
+	return nil
+}
+
+// generateInvitationToken returns a random URL-safe token
+func generateInvitationToken() (string, error) {
+	b := make([]byte, invitationTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashInvitationToken returns the hex-encoded SHA-256 hash of a token
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// uniqueStrings returns the values without duplicates, preserving order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+)
+
+// LoginHistoryService provides domain services for the history of sign-in attempts
+type LoginHistoryService struct {
+	loginEventRepo repository.LoginEventRepository
+}
+
+// NewLoginHistoryService creates a new instance of LoginHistoryService
+func NewLoginHistoryService(loginEventRepo repository.LoginEventRepository) *LoginHistoryService {
+	return &LoginHistoryService{
+		loginEventRepo: loginEventRepo,
+	}
+}
+
+// Record stores a login event. A failure to store it is logged rather than
+// returned so that an unavailable history never blocks sign-in.
+// Recording is skipped when the service is nil.
+func (s *LoginHistoryService) Record(ctx context.Context, event *entity.LoginEvent) {
+	if s == nil {
+		return
+	}
+	if err := s.loginEventRepo.Create(ctx, event); err != nil {
+		log.Printf("Failed to record login event for %q: %v", event.Identifier, err)
+	}
+}
+
+// ListEvents returns a page of login events matching the filter along with the total count
+func (s *LoginHistoryService) ListEvents(ctx context.Context, filter *repository.LoginEventFilter, offset, limit int) ([]*entity.LoginEvent, int64, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, 0, fmt.Errorf("%w: start time must be before end time", entity.ErrInvalidTimeRange)
+	}
+
+	events, err := s.loginEventRepo.List(ctx, filter, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list login events: %w", err)
+	}
+
+	count, err := s.loginEventRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count login events: %w", err)
+	}
+
+	return events, count, nil
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/google/uuid"
+)
+
+// BatchCreateUsers validates and creates users like CreateUser, hashing their
+// passwords in parallel and writing them with multi-row inserts. It returns the
+// error of each user that was not created, indexed like creations. With
+// allOrNothing, no user is created unless all are, and the first failure is
+// returned as an entity.BatchItemError.
+func (s *UserService) BatchCreateUsers(ctx context.Context, creations []entity.UserCreation, allOrNothing bool) ([]error, error) {
+	if err := entity.CheckBatchSize(len(creations)); err != nil {
+		return nil, err
+	}
+
+	// bcrypt dominates the cost of creating a user, so prepare users on every CPU
+	errs := make([]error, len(creations))
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i := range creations {
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = s.prepareUser(ctx, creations[i].User, creations[i].Password)
+		}(i)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// The database would reject all but one of the users sharing an email,
+	// username or unique attribute value; reject the later ones up front so the
+	// earlier one is created
+	uniqueKeys, err := s.attributeService.UniqueKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	emails := make(map[string]bool, len(creations))
+	usernames := make(map[string]bool, len(creations))
+	attributeValues := make(map[string]map[string]bool, len(uniqueKeys))
+	for _, key := range uniqueKeys {
+		attributeValues[key] = make(map[string]bool, len(creations))
+	}
+	for i, creation := range creations {
+		if errs[i] != nil {
+			continue
+		}
+		email := strings.ToLower(creation.User.Email)
+		if emails[email] {
+			errs[i] = duplicateBatchItem("email")
+			continue
+		}
+		if usernames[creation.User.UsernameSkeleton] {
+			errs[i] = duplicateBatchItem("username")
+			continue
+		}
+		values, err := uniqueAttributeValues(creation.User.Attributes, uniqueKeys)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if key, ok := firstSeenAttribute(attributeValues, values); ok {
+			errs[i] = duplicateBatchItem("attributes." + key)
+			continue
+		}
+		emails[email] = true
+		usernames[creation.User.UsernameSkeleton] = true
+		for key, value := range values {
+			attributeValues[key][value] = true
+		}
+	}
+
+	if allOrNothing {
+		if err := entity.FirstBatchError(errs); err != nil {
+			return nil, err
+		}
+	}
+
+	var users []*entity.User
+	var indexes []int
+	for i, creation := range creations {
+		if errs[i] == nil {
+			users = append(users, creation.User)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(users) == 0 {
+		return errs, nil
+	}
+
+	writeErrs, err := s.userRepo.CreateBatch(ctx, users, allOrNothing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create users: %w", err)
+	}
+	for j, err := range writeErrs {
+		errs[indexes[j]] = err
+	}
+
+	if allOrNothing {
+		if err := entity.FirstBatchError(errs); err != nil {
+			return nil, err
+		}
+	}
+	return errs, nil
+}
+
+// BatchUpdateUsers applies partial updates to users like UpdateUser, reading
+// them with one query and writing the changed users in one transaction. Results are indexed like modifications;
+// the failures of best-effort batches are reported in them. With allOrNothing,
+// no user is updated unless all are, and the first failure is returned as an
+// entity.BatchItemError.
+func (s *UserService) BatchUpdateUsers(ctx context.Context, modifications []entity.UserModification, allOrNothing bool) ([]entity.UserModificationResult, error) {
+	if err := entity.CheckBatchSize(len(modifications)); err != nil {
+		return nil, err
+	}
+
+	results := make([]entity.UserModificationResult, len(modifications))
+	seen := make(map[uuid.UUID]bool, len(modifications))
+	ids := make([]uuid.UUID, 0, len(modifications))
+	for i, modification := range modifications {
+		if seen[modification.ID] {
+			results[i].Err = duplicateBatchItem("id")
+			continue
+		}
+		seen[modification.ID] = true
+		ids = append(ids, modification.ID)
+	}
+
+	// Read every user at once
+	existing, err := s.userRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	byID := make(map[uuid.UUID]*entity.User, len(existing))
+	for _, user := range existing {
+		byID[user.ID] = user
+	}
+
+	for i, modification := range modifications {
+		if results[i].Err != nil {
+			continue
+		}
+		user, ok := byID[modification.ID]
+		if !ok {
+			results[i].Err = entity.ErrUserNotFound
+			continue
+		}
+		if err := user.CheckVersion(modification.ExpectedVersion); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		changed, err := s.applyUpdate(ctx, user, modification.Update)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i] = entity.UserModificationResult{User: user, Changed: changed}
+	}
+
+	if allOrNothing {
+		if err := firstModificationError(results); err != nil {
+			return nil, err
+		}
+	}
+
+	// Skip users with no changes so their versions are not bumped
+	var users []*entity.User
+	var indexes []int
+	for i, result := range results {
+		if result.Err == nil && len(result.Changed) > 0 {
+			users = append(users, result.User)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(users) == 0 {
+		return results, nil
+	}
+
+	writeErrs, err := s.userRepo.UpdateBatch(ctx, users, allOrNothing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update users: %w", err)
+	}
+	for j, err := range writeErrs {
+		if err != nil {
+			results[indexes[j]] = entity.UserModificationResult{Err: err}
+		}
+	}
+
+	if allOrNothing {
+		if err := firstModificationError(results); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// BatchDeleteUsers soft deletes users in one transaction. It returns the error of
+// each user that was not deleted, indexed like deletions. With allOrNothing, no
+// user is deleted unless all are, and the first failure is returned as an
+// entity.BatchItemError.
+func (s *UserService) BatchDeleteUsers(ctx context.Context, deletions []entity.UserDeletion, allOrNothing bool) ([]error, error) {
+	if err := entity.CheckBatchSize(len(deletions)); err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(deletions))
+	seen := make(map[uuid.UUID]bool, len(deletions))
+	var pending []entity.UserDeletion
+	var indexes []int
+	for i, deletion := range deletions {
+		if seen[deletion.ID] {
+			errs[i] = duplicateBatchItem("id")
+			continue
+		}
+		seen[deletion.ID] = true
+		pending = append(pending, deletion)
+		indexes = append(indexes, i)
+	}
+
+	if allOrNothing {
+		if err := entity.FirstBatchError(errs); err != nil {
+			return nil, err
+		}
+	}
+
+	writeErrs, err := s.userRepo.DeleteBatch(ctx, pending, allOrNothing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete users: %w", err)
+	}
+	for j, err := range writeErrs {
+		errs[indexes[j]] = err
+	}
+
+	if allOrNothing {
+		if err := entity.FirstBatchError(errs); err != nil {
+			return nil, err
+		}
+	}
+	return errs, nil
+}
+
+// duplicateBatchItem reports a field naming the same user as an earlier item of a batch
+func duplicateBatchItem(field string) error {
+	return &entity.FieldViolation{
+		Field:       field,
+		Description: field + " appears more than once in the batch",
+		Err:         entity.ErrDuplicateBatchItem,
+	}
+}
+
+// uniqueAttributeValues returns the JSON encoding of the user's values of the
+// unique attributes, which compare equal exactly when the database values do
+func uniqueAttributeValues(attributes entity.Attributes, uniqueKeys []string) (map[string]string, error) {
+	values := make(map[string]string, len(uniqueKeys))
+	for _, key := range uniqueKeys {
+		value, ok := attributes[key]
+		if !ok || value == nil {
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", entity.ErrInvalidAttribute, key, err)
+		}
+		values[key] = string(encoded)
+	}
+	return values, nil
+}
+
+// firstSeenAttribute returns the first unique attribute, in key order, whose
+// value was already seen
+func firstSeenAttribute(seen map[string]map[string]bool, values map[string]string) (string, bool) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if seen[key][values[key]] {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// firstModificationError returns the first failed update of a batch as a BatchItemError, or nil
+func firstModificationError(results []entity.UserModificationResult) error {
+	errs := make([]error, len(results))
+	for i, result := range results {
+		errs[i] = result.Err
+	}
+	return entity.FirstBatchError(errs)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+)
+
+// UserChangeRetentionService prunes the user change log read by WatchUsers.
+// Watches resuming from a pruned position fail, so the retention bounds how
+// long a resume token can be used.
+type UserChangeRetentionService struct {
+	userChanges repository.UserChangeRepository
+	retention   time.Duration
+}
+
+// NewUserChangeRetentionService creates a new instance of UserChangeRetentionService.
+// A zero retention keeps changes forever.
+func NewUserChangeRetentionService(userChanges repository.UserChangeRepository, retention time.Duration) *UserChangeRetentionService {
+	return &UserChangeRetentionService{
+		userChanges: userChanges,
+		retention:   retention,
+	}
+}
+
+// PruneChanges deletes the changes older than the retention and returns the
+// number deleted
+func (s *UserChangeRetentionService) PruneChanges(ctx context.Context, now time.Time) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+
+	pruned, err := s.userChanges.Prune(ctx, now.Add(-s.retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune user changes: %w", err)
+	}
+	return pruned, nil
+}
+
+// Run prunes changes once per interval until the context is cancelled
+func (s *UserChangeRetentionService) Run(ctx context.Context, interval time.Duration) {
+	if s.retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := s.PruneChanges(ctx, time.Now())
+		if err != nil {
+			log.Printf("Failed to prune user changes: %v", err)
+		} else if pruned > 0 {
+			log.Printf("Pruned %d user changes", pruned)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
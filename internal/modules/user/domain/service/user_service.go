@@ -2,28 +2,116 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/auth"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // UserService provides domain services for user operations
 type UserService struct {
-	userRepo repository.UserRepository
+	userRepo         repository.UserRepository
+	attributeService *AttributeService
+	identifierRules  *IdentifierRuleService
+	tenantSettings   repository.TenantSettings
+	groupRoles       repository.GroupRoles
+	usernamePolicy   entity.UsernamePolicy
+	loginHistory     *LoginHistoryService
+	passwordPolicy   entity.PasswordPolicy
 }
 
 // NewUserService creates a new instance of UserService
-func NewUserService(userRepo repository.UserRepository) *UserService {
+func NewUserService(userRepo repository.UserRepository, attributeService *AttributeService, identifierRules *IdentifierRuleService, tenantSettings repository.TenantSettings, groupRoles repository.GroupRoles, usernamePolicy entity.UsernamePolicy, loginHistory *LoginHistoryService, passwordPolicy entity.PasswordPolicy) *UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		attributeService: attributeService,
+		identifierRules:  identifierRules,
+		tenantSettings:   tenantSettings,
+		groupRoles:       groupRoles,
+		usernamePolicy:   usernamePolicy,
+		loginHistory:     loginHistory,
+		passwordPolicy:   passwordPolicy,
 	}
 }
 
 // CreateUser creates a new user with password hashing
 func (s *UserService) CreateUser(ctx context.Context, user *entity.User, plainPassword string) error {
+	if err := s.prepareUser(ctx, user, plainPassword); err != nil {
+		return err
+	}
+
+	// Create user
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return nil
+}
+
+// RequireAdmin returns ErrAdminRequired unless the caller is an active
+// administrator of the current tenant. Calls without token claims have no caller
+// and are refused.
+func (s *UserService) RequireAdmin(ctx context.Context) error {
+	isAdmin, err := s.IsAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return entity.ErrAdminRequired
+	}
+	return nil
+}
+
+// IsAdmin reports whether the caller is an active user who is an administrator
+// or holds the admin role through a group of the current tenant
+func (s *UserService) IsAdmin(ctx context.Context) (bool, error) {
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return false, nil
+	}
+
+	caller, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil && !errors.Is(err, entity.ErrUserNotFound) {
+		return false, fmt.Errorf("failed to get caller: %w", err)
+	}
+	if caller == nil || !caller.IsActive {
+		return false, nil
+	}
+	if caller.IsAdmin {
+		return true, nil
+	}
+
+	roles, err := s.groupRoles.TenantRoles(ctx, caller.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get caller roles: %w", err)
+	}
+	for _, role := range roles {
+		if role == entity.AdminRole {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RequireSelfOrAdmin returns ErrAdminRequired unless the caller is the given user
+// or an active administrator of the current tenant
+func (s *UserService) RequireSelfOrAdmin(ctx context.Context, userID uuid.UUID) error {
+	if claims, ok := auth.ClaimsFromContext(ctx); ok && claims.UserID == userID {
+		return nil
+	}
+	return s.RequireAdmin(ctx)
+}
+
+// prepareUser validates and normalizes a new user, hashes its password and sets
+// the organization within which its email and username must be unique
+func (s *UserService) prepareUser(ctx context.Context, user *entity.User, plainPassword string) error {
 	// Validate email
 	email, err := entity.NewEmail(user.Email)
 	if err != nil {
@@ -32,11 +120,17 @@ func (s *UserService) CreateUser(ctx context.Context, user *entity.User, plainPa
 	user.Email = email.Value()
 
 	// Validate username
-	username, err := entity.NewUsername(user.Username)
+	username, err := s.usernamePolicy.NewUsername(user.Username)
 	if err != nil {
 		return err
 	}
 	user.Username = username.Value()
+	user.UsernameSkeleton = username.Skeleton()
+
+	// Reject reserved usernames and blocked email domains
+	if err := s.checkIdentifiers(ctx, user.Email, user.Username); err != nil {
+		return err
+	}
 
 	// Validate name
 	name, err := entity.NewPersonName(user.FirstName, user.LastName)
@@ -46,104 +140,244 @@ func (s *UserService) CreateUser(ctx context.Context, user *entity.User, plainPa
 	user.FirstName = name.FirstName
 	user.LastName = name.LastName
 
+	// Validate name readings, name order and locale
+	if user.FamilyNameKana, err = entity.NewNameReading(user.FamilyNameKana); err != nil {
+		return err
+	}
+	if user.GivenNameKana, err = entity.NewNameReading(user.GivenNameKana); err != nil {
+		return err
+	}
+	if !user.NameOrder.IsValid() {
+		return fmt.Errorf("%w: %q", entity.ErrInvalidNameOrder, user.NameOrder)
+	}
+	if user.Locale, err = entity.NormalizeLocale(user.Locale); err != nil {
+		return err
+	}
+
+	// Validate validity window
+	if err := entity.ValidateValidity(user.ValidFrom, user.ValidUntil); err != nil {
+		return err
+	}
+
+	// Validate custom attributes
+	if user.Attributes == nil {
+		user.Attributes = entity.Attributes{}
+	}
+	if err := s.attributeService.ValidateAttributes(ctx, user.Attributes); err != nil {
+		return err
+	}
+
 	// Hash password
 	hashedPassword, err := s.HashPassword(plainPassword)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 	user.Password = hashedPassword
+	now := time.Now()
+	user.PasswordChangedAt = &now
 
-	// Check if user already exists
-	emailExists, err := s.userRepo.ExistsByEmail(ctx, user.Email)
+	// Email and username uniqueness is enforced by the database within this scope
+	uniquenessOrganizationID, err := s.uniquenessOrganization(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to check email existence: %w", err)
+		return err
 	}
-	if emailExists {
-		return fmt.Errorf("%w: email already in use", entity.ErrUserAlreadyExists)
+	user.UniquenessOrganizationID = uniquenessOrganizationID
+
+	return nil
+}
+
+// UpdateUser applies a partial update to an existing user and returns the updated
+// user along with the fields whose values actually changed.
+// A non-zero expected version rejects the update if the user has changed since it was read.
+func (s *UserService) UpdateUser(ctx context.Context, userID uuid.UUID, update *entity.UserUpdate, expectedVersion int64) (*entity.User, []entity.UserField, error) {
+	// Get existing user
+	existingUser, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if existingUser == nil {
+		return nil, nil, entity.ErrUserNotFound
+	}
+	if err := existingUser.CheckVersion(expectedVersion); err != nil {
+		return nil, nil, err
 	}
 
-	usernameExists, err := s.userRepo.ExistsByUsername(ctx, user.Username)
+	changed, err := s.applyUpdate(ctx, existingUser, update)
 	if err != nil {
-		return fmt.Errorf("failed to check username existence: %w", err)
+		return nil, nil, err
 	}
-	if usernameExists {
-		return fmt.Errorf("%w: username already in use", entity.ErrUserAlreadyExists)
+
+	// Skip the write when nothing changed so the version is not bumped
+	if len(changed) == 0 {
+		return existingUser, changed, nil
 	}
 
-	// Create user
-	if err := s.userRepo.Create(ctx, user); err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
+	// Update user
+	if err := s.userRepo.Update(ctx, existingUser); err != nil {
+		return nil, nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
-	return nil
+	return existingUser, changed, nil
 }
 
-// UpdateUser updates an existing user
-func (s *UserService) UpdateUser(ctx context.Context, userID uuid.UUID, updates *entity.User) error {
-	// Get existing user
-	existingUser, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return fmt.Errorf("failed to get user: %w", err)
-	}
-	if existingUser == nil {
-		return entity.ErrUserNotFound
+// applyUpdate validates the fields listed in an update and applies them to the
+// user, returning the fields whose values changed
+func (s *UserService) applyUpdate(ctx context.Context, existingUser *entity.User, update *entity.UserUpdate) ([]entity.UserField, error) {
+	var changed []entity.UserField
+
+	// Apply fields listed in the update; email and username cannot be cleared.
+	// Their uniqueness is enforced by the database when the update is written.
+	if update.Has(entity.UserFieldEmail) {
+		email, err := entity.NewEmail(update.Email)
+		if err != nil {
+			return nil, err
+		}
+
+		if email.Value() != existingUser.Email {
+			if err := s.checkIdentifiers(ctx, email.Value(), ""); err != nil {
+				return nil, err
+			}
+			existingUser.Email = email.Value()
+			changed = append(changed, entity.UserFieldEmail)
+		}
 	}
 
-	// Update fields if provided
-	if updates.Email != "" && updates.Email != existingUser.Email {
-		email, err := entity.NewEmail(updates.Email)
+	if update.Has(entity.UserFieldUsername) {
+		username, err := s.usernamePolicy.NewUsername(update.Username)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		// Check if new email is already in use
-		emailExists, err := s.userRepo.ExistsByEmail(ctx, email.Value())
+		if username.Value() != existingUser.Username {
+			if err := s.checkIdentifiers(ctx, "", username.Value()); err != nil {
+				return nil, err
+			}
+			existingUser.Username = username.Value()
+			existingUser.UsernameSkeleton = username.Skeleton()
+			changed = append(changed, entity.UserFieldUsername)
+		}
+	}
+
+	// Validate the resulting name, since either part may come from the existing user
+	if update.Has(entity.UserFieldFirstName) || update.Has(entity.UserFieldLastName) {
+		firstName, lastName := existingUser.FirstName, existingUser.LastName
+		if update.Has(entity.UserFieldFirstName) {
+			firstName = update.FirstName
+		}
+		if update.Has(entity.UserFieldLastName) {
+			lastName = update.LastName
+		}
+		name, err := entity.NewPersonName(firstName, lastName)
 		if err != nil {
-			return fmt.Errorf("failed to check email existence: %w", err)
+			return nil, err
 		}
-		if emailExists {
-			return fmt.Errorf("%w: email already in use", entity.ErrUserAlreadyExists)
+
+		if name.FirstName != existingUser.FirstName {
+			existingUser.FirstName = name.FirstName
+			changed = append(changed, entity.UserFieldFirstName)
+		}
+		if name.LastName != existingUser.LastName {
+			existingUser.LastName = name.LastName
+			changed = append(changed, entity.UserFieldLastName)
 		}
+	}
 
-		existingUser.Email = email.Value()
+	if update.Has(entity.UserFieldFamilyNameKana) {
+		reading, err := entity.NewNameReading(update.FamilyNameKana)
+		if err != nil {
+			return nil, err
+		}
+		if reading != existingUser.FamilyNameKana {
+			existingUser.FamilyNameKana = reading
+			changed = append(changed, entity.UserFieldFamilyNameKana)
+		}
 	}
 
-	if updates.Username != "" && updates.Username != existingUser.Username {
-		username, err := entity.NewUsername(updates.Username)
+	if update.Has(entity.UserFieldGivenNameKana) {
+		reading, err := entity.NewNameReading(update.GivenNameKana)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		if reading != existingUser.GivenNameKana {
+			existingUser.GivenNameKana = reading
+			changed = append(changed, entity.UserFieldGivenNameKana)
+		}
+	}
 
-		// Check if new username is already in use
-		usernameExists, err := s.userRepo.ExistsByUsername(ctx, username.Value())
+	if update.Has(entity.UserFieldNameOrder) {
+		if !update.NameOrder.IsValid() {
+			return nil, fmt.Errorf("%w: %q", entity.ErrInvalidNameOrder, update.NameOrder)
+		}
+		if update.NameOrder != existingUser.NameOrder {
+			existingUser.NameOrder = update.NameOrder
+			changed = append(changed, entity.UserFieldNameOrder)
+		}
+	}
+
+	if update.Has(entity.UserFieldLocale) {
+		locale, err := entity.NormalizeLocale(update.Locale)
 		if err != nil {
-			return fmt.Errorf("failed to check username existence: %w", err)
+			return nil, err
 		}
-		if usernameExists {
-			return fmt.Errorf("%w: username already in use", entity.ErrUserAlreadyExists)
+		if locale != existingUser.Locale {
+			existingUser.Locale = locale
+			changed = append(changed, entity.UserFieldLocale)
 		}
+	}
 
-		existingUser.Username = username.Value()
+	// Only administrators activate, deactivate, promote or demote users
+	if (update.Has(entity.UserFieldIsActive) && update.IsActive != existingUser.IsActive) ||
+		(update.Has(entity.UserFieldIsAdmin) && update.IsAdmin != existingUser.IsAdmin) {
+		if err := s.RequireAdmin(ctx); err != nil {
+			return nil, err
+		}
 	}
 
-	if updates.FirstName != "" {
-		existingUser.FirstName = updates.FirstName
+	if update.Has(entity.UserFieldIsActive) && update.IsActive != existingUser.IsActive {
+		existingUser.IsActive = update.IsActive
+		changed = append(changed, entity.UserFieldIsActive)
 	}
 
-	if updates.LastName != "" {
-		existingUser.LastName = updates.LastName
+	if update.Has(entity.UserFieldIsAdmin) && update.IsAdmin != existingUser.IsAdmin {
+		existingUser.IsAdmin = update.IsAdmin
+		changed = append(changed, entity.UserFieldIsAdmin)
 	}
 
-	// Update user
-	if err := s.userRepo.Update(ctx, existingUser); err != nil {
-		return fmt.Errorf("failed to update user: %w", err)
+	if update.Has(entity.UserFieldValidFrom) && !equalTimes(update.ValidFrom, existingUser.ValidFrom) {
+		existingUser.ValidFrom = update.ValidFrom
+		changed = append(changed, entity.UserFieldValidFrom)
 	}
 
-	return nil
+	if update.Has(entity.UserFieldValidUntil) && !equalTimes(update.ValidUntil, existingUser.ValidUntil) {
+		existingUser.ValidUntil = update.ValidUntil
+		changed = append(changed, entity.UserFieldValidUntil)
+	}
+
+	// Check the resulting window, since either side may have come from the existing user
+	if err := entity.ValidateValidity(existingUser.ValidFrom, existingUser.ValidUntil); err != nil {
+		return nil, err
+	}
+
+	if update.Has(entity.UserFieldAttributes) {
+		attributes := update.Attributes
+		if attributes == nil {
+			attributes = entity.Attributes{}
+		}
+		if err := s.attributeService.ValidateAttributes(ctx, attributes); err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(attributes, existingUser.Attributes) {
+			existingUser.Attributes = attributes
+			changed = append(changed, entity.UserFieldAttributes)
+		}
+	}
+
+	return changed, nil
 }
 
-// ChangePassword changes a user's password
-func (s *UserService) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error {
+// ChangePassword changes a user's password.
+// A non-zero expected version rejects the change if the user has changed since it was read.
+func (s *UserService) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string, expectedVersion int64) error {
 	// Get user
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -152,20 +386,31 @@ func (s *UserService) ChangePassword(ctx context.Context, userID uuid.UUID, oldP
 	if user == nil {
 		return entity.ErrUserNotFound
 	}
+	if err := user.CheckVersion(expectedVersion); err != nil {
+		return err
+	}
 
 	// Verify old password
 	if err := s.VerifyPassword(user.Password, oldPassword); err != nil {
 		return entity.ErrInvalidCredentials
 	}
 
+	// A forced change must actually replace the temporary or expired password
+	if s.VerifyPassword(user.Password, newPassword) == nil {
+		return entity.ErrPasswordReused
+	}
+
 	// Hash new password
 	hashedPassword, err := s.HashPassword(newPassword)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// Update password
+	// Update password and lift any forced change
+	now := time.Now()
 	user.Password = hashedPassword
+	user.PasswordChangedAt = &now
+	user.MustChangePassword = false
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
@@ -173,40 +418,265 @@ func (s *UserService) ChangePassword(ctx context.Context, userID uuid.UUID, oldP
 	return nil
 }
 
-// Authenticate authenticates a user with email/username and password
-func (s *UserService) Authenticate(ctx context.Context, identifier, password string) (*entity.User, error) {
+// SetTemporaryPassword replaces a user's password with a generated one-time password
+// and forces the user to change it on next sign-in. It returns the updated user and
+// the plain temporary password, which is not stored and cannot be retrieved again.
+// A non-zero expected version rejects the reset if the user has changed since it was read.
+func (s *UserService) SetTemporaryPassword(ctx context.Context, userID uuid.UUID, expectedVersion int64) (*entity.User, string, error) {
+	// Get user
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, "", entity.ErrUserNotFound
+	}
+	if err := user.CheckVersion(expectedVersion); err != nil {
+		return nil, "", err
+	}
+
+	temporaryPassword, err := entity.GenerateTemporaryPassword()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate temporary password: %w", err)
+	}
+	hashedPassword, err := s.HashPassword(temporaryPassword)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	now := time.Now()
+	user.Password = hashedPassword
+	user.PasswordChangedAt = &now
+	user.MustChangePassword = true
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, "", fmt.Errorf("failed to set temporary password: %w", err)
+	}
+
+	return user, temporaryPassword, nil
+}
+
+// PasswordChangeRequired reports whether the user must change their password before
+// being allowed anything else, because it was set by an administrator or has expired
+func (s *UserService) PasswordChangeRequired(user *entity.User, now time.Time) bool {
+	return s.passwordPolicy.RequiresChange(user, now)
+}
+
+// MergeUsers merges the source user into the target user. Fields resolved to the
+// source take the source's value; all others keep the target's. The source's
+// memberships, login history, preferences and accepted invitations move to the
+// target, the source is soft-deleted pointing at the target, and an audit event
+// is recorded. A dry run returns the same result without keeping any change.
+// Non-zero expected versions reject the merge if either user has changed since it was read.
+func (s *UserService) MergeUsers(ctx context.Context, sourceID, targetID uuid.UUID, resolution entity.FieldResolution, sourceVersion, targetVersion int64, dryRun bool) (*entity.UserMerge, *entity.MergedReferences, error) {
+	if sourceID == targetID {
+		return nil, nil, fmt.Errorf("%w: a user cannot be merged into itself", entity.ErrInvalidMerge)
+	}
+	if err := resolution.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	source, err := s.userRepo.GetByID(ctx, sourceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get source user: %w", err)
+	}
+	if source == nil {
+		return nil, nil, entity.ErrUserNotFound
+	}
+	if err := source.CheckVersion(sourceVersion); err != nil {
+		return nil, nil, err
+	}
+
+	target, err := s.userRepo.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get target user: %w", err)
+	}
+	if target == nil {
+		return nil, nil, entity.ErrUserNotFound
+	}
+	if err := target.CheckVersion(targetVersion); err != nil {
+		return nil, nil, err
+	}
+
+	// Values taken from the source were valid on the source, so they are not
+	// validated again; uniqueness within the target's scope is enforced on write
+	changed := resolution.Apply(target, source)
+	if err := entity.ValidateValidity(target.ValidFrom, target.ValidUntil); err != nil {
+		return nil, nil, err
+	}
+
+	sourceFields := make([]string, 0, len(resolution))
+	for _, field := range entity.MergeableUserFields {
+		if resolution[field] == entity.MergeSideSource {
+			sourceFields = append(sourceFields, string(field))
+		}
+	}
+	changedFields := make([]string, len(changed))
+	for i, field := range changed {
+		changedFields[i] = string(field)
+	}
+
+	merge := &entity.UserMerge{
+		Source:        source,
+		Target:        target,
+		ChangedFields: changed,
+		Audit: &entity.AuditEvent{
+			Action:    entity.AuditActionUserMerged,
+			SubjectID: target.ID,
+			Details: entity.Attributes{
+				"source_id":       source.ID.String(),
+				"source_email":    source.Email,
+				"source_username": source.Username,
+				"source_fields":   sourceFields,
+				"changed_fields":  changedFields,
+			},
+		},
+	}
+
+	moved, err := s.userRepo.Merge(ctx, merge, dryRun)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to merge users: %w", err)
+	}
+	if !dryRun {
+		source.MergedIntoID = &target.ID
+	}
+
+	return merge, moved, nil
+}
+
+// Authenticate authenticates a user with email/username and password.
+// Every attempt is recorded in the login history, and a successful one also
+// updates the user's last login time.
+func (s *UserService) Authenticate(ctx context.Context, identifier, password string, attempt entity.LoginAttempt) (*entity.User, error) {
+	now := time.Now()
+	event := entity.NewLoginEvent(attempt, identifier, now)
+
+	user, reason, err := s.checkCredentials(ctx, identifier, password, now)
+	if user != nil {
+		event.UserID = &user.ID
+	}
+	event.FailureReason = reason
+
+	if err == nil {
+		if updateErr := s.userRepo.UpdateLastLogin(ctx, user.ID, now); updateErr != nil {
+			event.FailureReason = entity.LoginFailureInternalError
+			err = fmt.Errorf("failed to record login: %w", updateErr)
+		} else {
+			user.LastLoginAt = &now
+		}
+	}
+	event.Success = err == nil
+
+	s.loginHistory.Record(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// checkCredentials finds the user with the identifier and verifies the password,
+// returning the matched user even when the attempt fails for a reason other than
+// an unknown identifier. The user must be active and within their validity window at now.
+func (s *UserService) checkCredentials(ctx context.Context, identifier, password string, now time.Time) (*entity.User, entity.LoginFailureReason, error) {
 	var user *entity.User
 	var err error
 
-	// Try to find user by email first
-	if _, emailErr := entity.NewEmail(identifier); emailErr == nil {
-		user, err = s.userRepo.GetByEmail(ctx, identifier)
+	// Try to find user by email first; both lookups are case-insensitive
+	if email, emailErr := entity.NewEmail(identifier); emailErr == nil {
+		user, err = s.userRepo.GetByEmail(ctx, email.Value())
 	}
 
 	// If not found by email, try username
 	if user == nil {
-		user, err = s.userRepo.GetByUsername(ctx, identifier)
+		user, err = s.userRepo.GetByUsername(ctx, entity.NormalizeUsername(identifier))
 	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to find user: %w", err)
+	if errors.Is(err, entity.ErrUserNotFound) || (err == nil && user == nil) {
+		return nil, entity.LoginFailureUnknownIdentifier, entity.ErrInvalidCredentials
 	}
-
-	if user == nil {
-		return nil, entity.ErrInvalidCredentials
+	if err != nil {
+		return nil, entity.LoginFailureInternalError, fmt.Errorf("failed to find user: %w", err)
 	}
 
 	// Verify password
 	if err := s.VerifyPassword(user.Password, password); err != nil {
-		return nil, entity.ErrInvalidCredentials
+		return user, entity.LoginFailureInvalidPassword, entity.ErrInvalidCredentials
 	}
 
 	// Check if user is active
 	if !user.IsActive {
-		return nil, fmt.Errorf("user account is not active")
+		return user, entity.LoginFailureUserInactive, entity.ErrUserInactive
 	}
 
-	return user, nil
+	// Expired users are denied even before the expiry job deactivates them
+	if !user.IsValidAt(now) {
+		return user, entity.LoginFailureOutsideValidity, entity.ErrUserOutsideValidity
+	}
+
+	return user, entity.LoginFailureNone, nil
+}
+
+// EmailInUse checks if an email is already taken where it must be unique for the current tenant
+func (s *UserService) EmailInUse(ctx context.Context, email string) (bool, error) {
+	uniqueCtx, err := s.uniquenessContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	exists, err := s.userRepo.ExistsByEmail(uniqueCtx, email)
+	if err != nil {
+		return false, fmt.Errorf("failed to check email existence: %w", err)
+	}
+	return exists, nil
+}
+
+// CheckEmailAllowed rejects emails from blocked domains or outside the domain allowlist
+func (s *UserService) CheckEmailAllowed(ctx context.Context, email string) error {
+	return s.checkIdentifiers(ctx, email, "")
+}
+
+// checkIdentifiers rejects reserved usernames and blocked email domains.
+// Without identifier rules, every identifier is allowed.
+func (s *UserService) checkIdentifiers(ctx context.Context, email, username string) error {
+	if s.identifierRules == nil {
+		return nil
+	}
+	return s.identifierRules.CheckIdentifiers(ctx, email, username)
+}
+
+// uniquenessContext returns the context in which email and username uniqueness
+// is checked: the current tenant for organization-scoped tenants, otherwise all tenants
+func (s *UserService) uniquenessContext(ctx context.Context) (context.Context, error) {
+	organizationID, err := s.uniquenessOrganization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if organizationID != nil {
+		return ctx, nil
+	}
+	return tenant.Unscoped(ctx), nil
+}
+
+// uniquenessOrganization returns the organization within which email and username
+// must be unique: the current tenant if it is organization-scoped, otherwise nil
+func (s *UserService) uniquenessOrganization(ctx context.Context) (*uuid.UUID, error) {
+	organizationID, ok := tenant.OrganizationIDFromContext(ctx)
+	if !ok || s.tenantSettings == nil {
+		return nil, nil
+	}
+
+	scope, err := s.tenantSettings.UniquenessScope(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant uniqueness scope: %w", err)
+	}
+	if scope == tenant.UniquenessOrganization {
+		return &organizationID, nil
+	}
+	return nil, nil
+}
+
+// ValidateAttributeFilter checks that a custom attribute filter only uses indexed attributes
+func (s *UserService) ValidateAttributeFilter(ctx context.Context, attributes entity.Attributes) error {
+	return s.attributeService.ValidateFilter(ctx, attributes)
 }
 
 // HashPassword hashes a plain text password
@@ -227,3 +697,11 @@ func (s *UserService) HashPassword(password string) (string, error) {
 func (s *UserService) VerifyPassword(hashedPassword, plainPassword string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(plainPassword))
 }
+
+// equalTimes reports whether two optional times are both unset or the same instant
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
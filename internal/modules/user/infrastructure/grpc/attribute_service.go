@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/mapper"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+)
+
+// CreateAttributeDefinition registers a new custom user attribute
+func (s *UserServiceServer) CreateAttributeDefinition(ctx context.Context, req *pb.CreateAttributeDefinitionRequest) (*pb.CreateAttributeDefinitionResponse, error) {
+	// Validate request
+	if req.Key == "" {
+		return nil, apperrors.MissingField("key")
+	}
+	if req.Type == pb.AttributeType_ATTRIBUTE_TYPE_UNSPECIFIED {
+		return nil, apperrors.MissingField("type")
+	}
+
+	// Convert request to DTO
+	createDTO := mapper.CreateAttributeDefinitionRequestToDTO(req)
+
+	// Create attribute definition
+	definitionDTO, err := s.attributeUseCase.CreateAttributeDefinition(ctx, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTO to proto
+	return &pb.CreateAttributeDefinitionResponse{
+		AttributeDefinition: mapper.AttributeDefinitionDTOToProto(definitionDTO),
+	}, nil
+}
+
+// ListAttributeDefinitions lists all registered custom user attributes
+func (s *UserServiceServer) ListAttributeDefinitions(ctx context.Context, req *pb.ListAttributeDefinitionsRequest) (*pb.ListAttributeDefinitionsResponse, error) {
+	// List attribute definitions
+	definitionDTOs, err := s.attributeUseCase.ListAttributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to proto
+	definitions := make([]*pb.AttributeDefinition, len(definitionDTOs))
+	for i, definitionDTO := range definitionDTOs {
+		definitions[i] = mapper.AttributeDefinitionDTOToProto(definitionDTO)
+	}
+
+	return &pb.ListAttributeDefinitionsResponse{
+		AttributeDefinitions: definitions,
+	}, nil
+}
+
+// UpdateAttributeDefinition updates a custom user attribute definition
+func (s *UserServiceServer) UpdateAttributeDefinition(ctx context.Context, req *pb.UpdateAttributeDefinitionRequest) (*pb.UpdateAttributeDefinitionResponse, error) {
+	// Validate request
+	if req.Id == "" {
+		return nil, apperrors.MissingField("id")
+	}
+
+	// Convert request to DTO
+	updateDTO, err := mapper.UpdateAttributeDefinitionRequestToDTO(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update attribute definition
+	definitionDTO, err := s.attributeUseCase.UpdateAttributeDefinition(ctx, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTO to proto
+	return &pb.UpdateAttributeDefinitionResponse{
+		AttributeDefinition: mapper.AttributeDefinitionDTOToProto(definitionDTO),
+	}, nil
+}
+
+// DeleteAttributeDefinition removes a custom user attribute definition
+func (s *UserServiceServer) DeleteAttributeDefinition(ctx context.Context, req *pb.DeleteAttributeDefinitionRequest) (*pb.DeleteAttributeDefinitionResponse, error) {
+	// Validate request
+	if req.Id == "" {
+		return nil, apperrors.MissingField("id")
+	}
+
+	// Delete attribute definition
+	if err := s.attributeUseCase.DeleteAttributeDefinition(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteAttributeDefinitionResponse{
+		Success: true,
+		Message: "Attribute definition deleted successfully",
+	}, nil
+}
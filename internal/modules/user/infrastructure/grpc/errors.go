@@ -0,0 +1,160 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+)
+
+// errorRules maps the user module's domain errors to application errors
+var errorRules = apperrors.Rules(
+	// Invalid arguments
+	apperrors.Rule{Err: entity.ErrInvalidEmail, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_EMAIL",
+		Translations: map[string]string{"ja": "メールアドレスが正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidUsername, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_USERNAME",
+		Translations: map[string]string{"ja": "ユーザー名が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidName, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_NAME",
+		Translations: map[string]string{"ja": "氏名が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidNameReading, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_NAME_READING",
+		Translations: map[string]string{"ja": "氏名の読みが正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidNameOrder, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_NAME_ORDER",
+		Translations: map[string]string{"ja": "氏名の表示順が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidLocale, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_LOCALE",
+		Translations: map[string]string{"ja": "ロケールが正しくありません"}},
+	apperrors.Rule{Err: entity.ErrPasswordTooShort, Code: apperrors.CodeInvalidArgument, Reason: "PASSWORD_TOO_SHORT",
+		Translations: map[string]string{"ja": "パスワードは8文字以上で入力してください"}},
+	apperrors.Rule{Err: entity.ErrPasswordReused, Code: apperrors.CodeInvalidArgument, Reason: "PASSWORD_REUSED",
+		Translations: map[string]string{"ja": "新しいパスワードは現在のパスワードと異なるものにしてください"}},
+	apperrors.Rule{Err: entity.ErrInvalidValidity, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_VALIDITY",
+		Translations: map[string]string{"ja": "有効期間が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidTimeRange, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_TIME_RANGE",
+		Translations: map[string]string{"ja": "期間の指定が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidFilter, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_FILTER",
+		Translations: map[string]string{"ja": "絞り込み条件が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidSort, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_SORT",
+		Translations: map[string]string{"ja": "並び順の指定が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidPageToken, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_PAGE_TOKEN",
+		Translations: map[string]string{"ja": "ページトークンが正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidResumeToken, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_RESUME_TOKEN",
+		Translations: map[string]string{"ja": "再開トークンが正しくありません"}},
+	apperrors.Rule{Err: entity.ErrResumeTokenExpired, Code: apperrors.CodeFailedPrecondition, Reason: "RESUME_TOKEN_EXPIRED",
+		Translations: map[string]string{"ja": "再開トークンの有効期限が切れています"}},
+	apperrors.Rule{Err: entity.ErrInvalidBatch, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_BATCH",
+		Translations: map[string]string{"ja": "一括処理の件数が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrDuplicateBatchItem, Code: apperrors.CodeInvalidArgument, Reason: "DUPLICATE_BATCH_ITEM",
+		Translations: map[string]string{"ja": "同じユーザーが一括処理に複数回含まれています"}},
+	apperrors.Rule{Err: entity.ErrInvalidMerge, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_MERGE",
+		Translations: map[string]string{"ja": "ユーザーの統合内容が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidUserID, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_USER_ID",
+		Translations: map[string]string{"ja": "ユーザーIDが正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidETag, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_ETAG",
+		Translations: map[string]string{"ja": "ETagが正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidUpdateMask, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_UPDATE_MASK",
+		Translations: map[string]string{"ja": "更新マスクが正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidAttribute, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_ATTRIBUTE",
+		Translations: map[string]string{"ja": "カスタム属性が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidAttributeDefinition, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_ATTRIBUTE_DEFINITION",
+		Translations: map[string]string{"ja": "属性定義が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrIdentifierNotAllowed, Code: apperrors.CodeInvalidArgument, Reason: "IDENTIFIER_NOT_ALLOWED",
+		Translations: map[string]string{"ja": "このユーザー名またはメールアドレスは使用できません"}},
+	apperrors.Rule{Err: entity.ErrInvalidIdentifierRule, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_IDENTIFIER_RULE",
+		Translations: map[string]string{"ja": "識別子ルールが正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidInvitation, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_INVITATION",
+		Translations: map[string]string{"ja": "招待が正しくありません"}},
+	apperrors.Rule{Err: entity.ErrInvalidInvitationID, Code: apperrors.CodeInvalidArgument, Reason: "INVALID_INVITATION_ID",
+		Translations: map[string]string{"ja": "招待IDが正しくありません"}},
+
+	// Missing resources
+	apperrors.Rule{Err: entity.ErrUserNotFound, Code: apperrors.CodeNotFound, Reason: "USER_NOT_FOUND",
+		Translations: map[string]string{"ja": "ユーザーが見つかりません"}},
+	apperrors.Rule{Err: entity.ErrAttributeDefinitionNotFound, Code: apperrors.CodeNotFound, Reason: "ATTRIBUTE_DEFINITION_NOT_FOUND",
+		Translations: map[string]string{"ja": "属性定義が見つかりません"}},
+	apperrors.Rule{Err: entity.ErrIdentifierRuleNotFound, Code: apperrors.CodeNotFound, Reason: "IDENTIFIER_RULE_NOT_FOUND",
+		Translations: map[string]string{"ja": "識別子ルールが見つかりません"}},
+	apperrors.Rule{Err: entity.ErrInvitationNotFound, Code: apperrors.CodeNotFound, Reason: "INVITATION_NOT_FOUND",
+		Translations: map[string]string{"ja": "招待が見つかりません"}},
+
+	// Conflicts with existing resources
+	apperrors.Rule{Err: entity.ErrUserAlreadyExists, Code: apperrors.CodeAlreadyExists, Reason: "USER_ALREADY_EXISTS",
+		Translations: map[string]string{"ja": "ユーザーは既に存在します"}},
+	apperrors.Rule{Err: entity.ErrAttributeDefinitionAlreadyExists, Code: apperrors.CodeAlreadyExists, Reason: "ATTRIBUTE_DEFINITION_ALREADY_EXISTS",
+		Translations: map[string]string{"ja": "属性定義は既に存在します"}},
+	apperrors.Rule{Err: entity.ErrIdentifierRuleAlreadyExists, Code: apperrors.CodeAlreadyExists, Reason: "IDENTIFIER_RULE_ALREADY_EXISTS",
+		Translations: map[string]string{"ja": "識別子ルールは既に存在します"}},
+	apperrors.Rule{Err: entity.ErrInvitationAlreadyExists, Code: apperrors.CodeAlreadyExists, Reason: "INVITATION_ALREADY_EXISTS",
+		Translations: map[string]string{"ja": "招待は既に存在します"}},
+
+	// Stale etags and lost concurrent updates: clients re-read and retry
+	apperrors.Rule{Err: entity.ErrETagMismatch, Code: apperrors.CodeAborted, Reason: "ETAG_MISMATCH",
+		Translations: map[string]string{"ja": "ユーザーは他の操作により更新されています"}},
+	apperrors.Rule{Err: entity.ErrConcurrentModification, Code: apperrors.CodeAborted, Reason: "CONCURRENT_MODIFICATION",
+		Translations: map[string]string{"ja": "ユーザーは同時に更新されました"}},
+
+	// Requests the current state does not allow
+	apperrors.Rule{Err: entity.ErrInvitationExpired, Code: apperrors.CodeFailedPrecondition, Reason: "INVITATION_EXPIRED",
+		Translations: map[string]string{"ja": "招待の有効期限が切れています"}},
+	apperrors.Rule{Err: entity.ErrInvitationNotPending, Code: apperrors.CodeFailedPrecondition, Reason: "INVITATION_NOT_PENDING",
+		Translations: map[string]string{"ja": "招待は既に処理されています"}},
+	apperrors.Rule{Err: entity.ErrUserInactive, Code: apperrors.CodeFailedPrecondition, Reason: "USER_INACTIVE",
+		Translations: map[string]string{"ja": "アカウントが有効ではありません"}},
+	apperrors.Rule{Err: entity.ErrUserOutsideValidity, Code: apperrors.CodeFailedPrecondition, Reason: "USER_OUTSIDE_VALIDITY",
+		Translations: map[string]string{"ja": "アカウントの有効期間外です"}},
+	apperrors.Rule{Err: entity.ErrPasswordChangeRequired, Code: apperrors.CodeFailedPrecondition, Reason: "PASSWORD_CHANGE_REQUIRED",
+		Translations: map[string]string{"ja": "パスワードの変更が必要です"}},
+
+	// Authentication
+	apperrors.Rule{Err: entity.ErrInvalidCredentials, Code: apperrors.CodeUnauthenticated, Reason: "INVALID_CREDENTIALS",
+		Translations: map[string]string{"ja": "認証情報が正しくありません"}},
+
+	// Authorization
+	apperrors.Rule{Err: entity.ErrAdminRequired, Code: apperrors.CodePermissionDenied, Reason: "ADMIN_REQUIRED",
+		Translations: map[string]string{"ja": "管理者権限が必要です"}},
+)
+
+// ErrorTranslator converts the user module's domain errors to application errors.
+// Field violations keep the code and reason of their underlying error and name
+// the offending field in a BadRequest detail. Errors of a batch item do the same
+// with fields under requests[index], and report the index in ErrorInfo metadata.
+func ErrorTranslator() apperrors.Translator {
+	var translate apperrors.Translator
+	translate = func(err error) *apperrors.Error {
+		var itemErr *entity.BatchItemError
+		if errors.As(err, &itemErr) {
+			return batchItemError(itemErr, apperrors.Translate(itemErr.Err, translate))
+		}
+
+		var violation *entity.FieldViolation
+		if errors.As(err, &violation) {
+			appErr := errorRules(violation.Err)
+			if appErr == nil {
+				appErr = apperrors.New(apperrors.CodeInvalidArgument, apperrors.ReasonInvalidField, violation.Error())
+			}
+			return appErr.WithViolation(violation.Field, violation.Description).WithCause(err)
+		}
+		return errorRules(err)
+	}
+	return translate
+}
+
+// batchItemError places the violations of a batch item's error under requests[index]
+func batchItemError(itemErr *entity.BatchItemError, appErr *apperrors.Error) *apperrors.Error {
+	prefix := fmt.Sprintf("requests[%d]", itemErr.Index)
+	violations := appErr.Violations
+	if len(violations) == 0 && appErr.Code == apperrors.CodeInvalidArgument {
+		violations = []apperrors.FieldViolation{{Description: appErr.Message}}
+	}
+
+	itemAppErr := appErr.WithCause(itemErr)
+	itemAppErr.Violations = nil
+	for _, v := range violations {
+		field := prefix
+		if v.Field != "" {
+			field += "." + v.Field
+		}
+		itemAppErr = itemAppErr.WithViolation(field, v.Description)
+	}
+	return itemAppErr.WithMetadata("index", strconv.Itoa(itemErr.Index))
+}
@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/mapper"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+)
+
+// CreateIdentifierRule reserves a username or blocks or allows an email domain
+func (s *UserServiceServer) CreateIdentifierRule(ctx context.Context, req *pb.CreateIdentifierRuleRequest) (*pb.CreateIdentifierRuleResponse, error) {
+	// Validate request
+	if req.Kind == pb.IdentifierRuleKind_IDENTIFIER_RULE_KIND_UNSPECIFIED {
+		return nil, apperrors.MissingField("kind")
+	}
+	if req.Value == "" {
+		return nil, apperrors.MissingField("value")
+	}
+
+	// Convert request to DTO
+	createDTO := mapper.CreateIdentifierRuleRequestToDTO(req)
+
+	// Create identifier rule
+	ruleDTO, err := s.identifierRuleUseCase.CreateIdentifierRule(ctx, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTO to proto
+	return &pb.CreateIdentifierRuleResponse{
+		IdentifierRule: mapper.IdentifierRuleDTOToProto(ruleDTO),
+	}, nil
+}
+
+// ListIdentifierRules lists reserved usernames and email domain rules
+func (s *UserServiceServer) ListIdentifierRules(ctx context.Context, req *pb.ListIdentifierRulesRequest) (*pb.ListIdentifierRulesResponse, error) {
+	// List identifier rules
+	ruleDTOs, err := s.identifierRuleUseCase.ListIdentifierRules(ctx, mapper.IdentifierRuleKindFromProto(req.Kind))
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to proto
+	rules := make([]*pb.IdentifierRule, len(ruleDTOs))
+	for i, ruleDTO := range ruleDTOs {
+		rules[i] = mapper.IdentifierRuleDTOToProto(ruleDTO)
+	}
+
+	return &pb.ListIdentifierRulesResponse{
+		IdentifierRules: rules,
+	}, nil
+}
+
+// DeleteIdentifierRule removes an identifier rule
+func (s *UserServiceServer) DeleteIdentifierRule(ctx context.Context, req *pb.DeleteIdentifierRuleRequest) (*pb.DeleteIdentifierRuleResponse, error) {
+	// Validate request
+	if req.Id == "" {
+		return nil, apperrors.MissingField("id")
+	}
+
+	// Delete identifier rule
+	if err := s.identifierRuleUseCase.DeleteIdentifierRule(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteIdentifierRuleResponse{
+		Success: true,
+		Message: "Identifier rule deleted successfully",
+	}, nil
+}
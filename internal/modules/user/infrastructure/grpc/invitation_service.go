@@ -0,0 +1,123 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/mapper"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+	commonpb "github.com/gigi434/sample-grpc-server/pkg/generated/common"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+)
+
+// InviteUser creates a pending invitation for an email address
+func (s *UserServiceServer) InviteUser(ctx context.Context, req *pb.InviteUserRequest) (*pb.InviteUserResponse, error) {
+	// Validate request
+	if req.Email == "" {
+		return nil, apperrors.MissingField("email")
+	}
+
+	// Convert request to DTO
+	inviteDTO, err := mapper.InviteUserRequestToDTO(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invite user
+	resultDTO, err := s.invitationUseCase.InviteUser(ctx, inviteDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTO to proto
+	return &pb.InviteUserResponse{
+		Invitation: mapper.InvitationDTOToProto(resultDTO.Invitation),
+		Token:      resultDTO.Token,
+	}, nil
+}
+
+// AcceptInvitation creates the invited user from an invitation token
+func (s *UserServiceServer) AcceptInvitation(ctx context.Context, req *pb.AcceptInvitationRequest) (*pb.AcceptInvitationResponse, error) {
+	// Validate request
+	if req.Token == "" {
+		return nil, apperrors.MissingField("token")
+	}
+	if req.Username == "" {
+		return nil, apperrors.MissingField("username")
+	}
+	if req.Password == "" {
+		return nil, apperrors.MissingField("password")
+	}
+
+	// Accept invitation
+	userDTO, err := s.invitationUseCase.AcceptInvitation(ctx, mapper.AcceptInvitationRequestToDTO(req))
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTO to proto
+	return &pb.AcceptInvitationResponse{
+		User: mapper.UserDTOToProto(userDTO),
+	}, nil
+}
+
+// RevokeInvitation revokes a pending invitation
+func (s *UserServiceServer) RevokeInvitation(ctx context.Context, req *pb.RevokeInvitationRequest) (*pb.RevokeInvitationResponse, error) {
+	// Validate request
+	if req.Id == "" {
+		return nil, apperrors.MissingField("id")
+	}
+
+	// Revoke invitation
+	if err := s.invitationUseCase.RevokeInvitation(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &pb.RevokeInvitationResponse{
+		Success: true,
+		Message: "Invitation revoked successfully",
+	}, nil
+}
+
+// ListInvitations lists pending invitations
+func (s *UserServiceServer) ListInvitations(ctx context.Context, req *pb.ListInvitationsRequest) (*pb.ListInvitationsResponse, error) {
+	// Default pagination
+	page := 1
+	pageSize := 10
+
+	if req.Pagination != nil {
+		if req.Pagination.Page > 0 {
+			page = int(req.Pagination.Page)
+		}
+		if req.Pagination.PageSize > 0 {
+			pageSize = int(req.Pagination.PageSize)
+		}
+		// Limit page size to prevent abuse
+		if pageSize > 100 {
+			pageSize = 100
+		}
+	}
+
+	// List invitations
+	listDTO, err := s.invitationUseCase.ListInvitations(ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to proto
+	invitations := make([]*pb.Invitation, len(listDTO.Invitations))
+	for i, invitationDTO := range listDTO.Invitations {
+		invitations[i] = mapper.InvitationDTOToProto(invitationDTO)
+	}
+
+	return &pb.ListInvitationsResponse{
+		Invitations: invitations,
+		Pagination: &commonpb.PaginationResponse{
+			Page:        int32(listDTO.Page),
+			PageSize:    int32(listDTO.PageSize),
+			TotalItems:  int32(listDTO.TotalItems),
+			TotalPages:  int32(listDTO.TotalPages),
+			HasNext:     listDTO.Page < listDTO.TotalPages,
+			HasPrevious: listDTO.Page > 1,
+		},
+	}, nil
+}
@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/mapper"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+	commonpb "github.com/gigi434/sample-grpc-server/pkg/generated/common"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ListLoginEvents lists a user's sign-in attempts, newest first
+func (s *UserServiceServer) ListLoginEvents(ctx context.Context, req *pb.ListLoginEventsRequest) (*pb.ListLoginEventsResponse, error) {
+	// Validate request
+	if req.UserId == "" {
+		return nil, apperrors.MissingField("user_id")
+	}
+
+	// Default pagination
+	page := 1
+	pageSize := 10
+
+	if req.Pagination != nil {
+		if req.Pagination.Page > 0 {
+			page = int(req.Pagination.Page)
+		}
+		if req.Pagination.PageSize > 0 {
+			pageSize = int(req.Pagination.PageSize)
+		}
+		// Limit page size to prevent abuse
+		if pageSize > 100 {
+			pageSize = 100
+		}
+	}
+
+	// Convert time range
+	var from, to *time.Time
+	if req.StartTime != nil {
+		startTime := req.StartTime.AsTime()
+		from = &startTime
+	}
+	if req.EndTime != nil {
+		endTime := req.EndTime.AsTime()
+		to = &endTime
+	}
+
+	// List login events
+	listDTO, err := s.userUseCase.ListLoginEvents(ctx, req.UserId, from, to, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTOs to proto
+	events := make([]*pb.LoginEvent, len(listDTO.Events))
+	for i, eventDTO := range listDTO.Events {
+		events[i] = mapper.LoginEventDTOToProto(eventDTO)
+	}
+
+	return &pb.ListLoginEventsResponse{
+		Events: events,
+		Pagination: &commonpb.PaginationResponse{
+			Page:        int32(listDTO.Page),
+			PageSize:    int32(listDTO.PageSize),
+			TotalItems:  int32(listDTO.TotalItems),
+			TotalPages:  int32(listDTO.TotalPages),
+			HasNext:     listDTO.Page < listDTO.TotalPages,
+			HasPrevious: listDTO.Page > 1,
+		},
+	}, nil
+}
+
+// clientInfo returns the IP address and user agent of the caller. The first
+// X-Forwarded-For address is preferred so that clients behind a proxy are identified.
+func clientInfo(ctx context.Context) (ipAddress, userAgent string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("user-agent"); len(values) > 0 {
+		userAgent = values[0]
+	}
+	if values := md.Get("x-forwarded-for"); len(values) > 0 {
+		if forwarded := strings.TrimSpace(strings.Split(values[0], ",")[0]); forwarded != "" {
+			return forwarded, userAgent
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ipAddress = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ipAddress); err == nil {
+			ipAddress = host
+		}
+	}
+	return ipAddress, userAgent
+}
@@ -0,0 +1,167 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/mapper"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/server"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// BatchCreateUsers creates multiple users, either all or nothing or each on its own
+func (s *UserServiceServer) BatchCreateUsers(ctx context.Context, req *pb.BatchCreateUsersRequest) (*pb.BatchCreateUsersResponse, error) {
+	// Validate request
+	if err := validateBatchSize(len(req.Requests)); err != nil {
+		return nil, err
+	}
+
+	// Items missing required fields fail here; the others go to the use case
+	results := make([]*pb.BatchUserResult, len(req.Requests))
+	var createDTOs []*dto.CreateUserDTO
+	var indexes []int
+	for i, item := range req.Requests {
+		if err := validateCreateUserRequest(item); err != nil {
+			if req.AllOrNothing {
+				return nil, batchItemFailure(i, err)
+			}
+			results[i] = batchUserResultToProto(ctx, &dto.BatchUserResultDTO{Err: err})
+			continue
+		}
+		createDTOs = append(createDTOs, mapper.CreateUserRequestToDTO(item))
+		indexes = append(indexes, i)
+	}
+
+	if len(createDTOs) > 0 {
+		// Create users
+		created, err := s.userUseCase.BatchCreateUsers(ctx, createDTOs, req.AllOrNothing)
+		if err != nil {
+			return nil, err
+		}
+		for j, result := range created {
+			results[indexes[j]] = batchUserResultToProto(ctx, result)
+		}
+	}
+
+	return &pb.BatchCreateUsersResponse{Results: results}, nil
+}
+
+// BatchUpdateUsers updates multiple users, either all or nothing or each on its own
+func (s *UserServiceServer) BatchUpdateUsers(ctx context.Context, req *pb.BatchUpdateUsersRequest) (*pb.BatchUpdateUsersResponse, error) {
+	// Validate request
+	if err := validateBatchSize(len(req.Requests)); err != nil {
+		return nil, err
+	}
+
+	// Items without an ID or with an invalid update mask fail here; the others go to the use case
+	results := make([]*pb.BatchUserResult, len(req.Requests))
+	var updateDTOs []*dto.UpdateUserDTO
+	var indexes []int
+	for i, item := range req.Requests {
+		updateDTO, err := batchUpdateUserDTO(item)
+		if err != nil {
+			if req.AllOrNothing {
+				return nil, batchItemFailure(i, err)
+			}
+			results[i] = batchUserResultToProto(ctx, &dto.BatchUserResultDTO{Err: err})
+			continue
+		}
+		updateDTOs = append(updateDTOs, updateDTO)
+		indexes = append(indexes, i)
+	}
+
+	if len(updateDTOs) > 0 {
+		// Update users
+		updated, err := s.userUseCase.BatchUpdateUsers(ctx, updateDTOs, req.AllOrNothing)
+		if err != nil {
+			return nil, err
+		}
+		for j, result := range updated {
+			results[indexes[j]] = batchUserResultToProto(ctx, result)
+		}
+	}
+
+	return &pb.BatchUpdateUsersResponse{Results: results}, nil
+}
+
+// BatchDeleteUsers soft deletes multiple users, either all or nothing or each on its own
+func (s *UserServiceServer) BatchDeleteUsers(ctx context.Context, req *pb.BatchDeleteUsersRequest) (*pb.BatchDeleteUsersResponse, error) {
+	// Validate request
+	if err := validateBatchSize(len(req.Requests)); err != nil {
+		return nil, err
+	}
+
+	// Items without an ID fail here; the others go to the use case
+	results := make([]*pb.BatchUserResult, len(req.Requests))
+	var deleteDTOs []*dto.DeleteUserDTO
+	var indexes []int
+	for i, item := range req.Requests {
+		if item.Id == "" {
+			err := apperrors.MissingField("id")
+			if req.AllOrNothing {
+				return nil, batchItemFailure(i, err)
+			}
+			results[i] = batchUserResultToProto(ctx, &dto.BatchUserResultDTO{Err: err})
+			continue
+		}
+		deleteDTOs = append(deleteDTOs, &dto.DeleteUserDTO{ID: item.Id, ETag: item.Etag})
+		indexes = append(indexes, i)
+	}
+
+	if len(deleteDTOs) > 0 {
+		// Delete users
+		deleted, err := s.userUseCase.BatchDeleteUsers(ctx, deleteDTOs, req.AllOrNothing)
+		if err != nil {
+			return nil, err
+		}
+		for j, result := range deleted {
+			results[indexes[j]] = batchUserResultToProto(ctx, result)
+		}
+	}
+
+	return &pb.BatchDeleteUsersResponse{Results: results}, nil
+}
+
+// validateBatchSize checks the number of items of a batch request
+func validateBatchSize(size int) error {
+	if size == 0 {
+		return apperrors.MissingField("requests")
+	}
+	return entity.CheckBatchSize(size)
+}
+
+// batchUpdateUserDTO validates and converts one item of a batch update like UpdateUser
+func batchUpdateUserDTO(req *pb.UpdateUserRequest) (*dto.UpdateUserDTO, error) {
+	if req.Id == "" {
+		return nil, apperrors.MissingField("id")
+	}
+	return mapper.UpdateUserRequestToDTO(req)
+}
+
+// batchItemFailure fails an all-or-nothing batch with the error of one item.
+// The error is translated here because application errors, such as those of
+// missing fields, pass through the error interceptor untranslated.
+func batchItemFailure(index int, err error) error {
+	return ErrorTranslator()(&entity.BatchItemError{Index: index, Err: err})
+}
+
+// batchUserResultToProto converts the outcome of one item of a batch write, with
+// the status the corresponding single-user RPC would have returned
+func batchUserResultToProto(ctx context.Context, result *dto.BatchUserResultDTO) *pb.BatchUserResult {
+	if result.Err != nil {
+		return &pb.BatchUserResult{Status: server.ErrorStatus(ctx, result.Err, ErrorTranslator()).Proto()}
+	}
+
+	protoResult := &pb.BatchUserResult{Status: &spb.Status{}}
+	if result.User != nil {
+		protoResult.User = mapper.UserDTOToProto(result.User)
+	}
+	if result.ChangedFields != nil {
+		protoResult.ChangedFields = &fieldmaskpb.FieldMask{Paths: result.ChangedFields}
+	}
+	return protoResult
+}
@@ -0,0 +1,177 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/mapper"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ExportUsers streams every user matching a filter, oldest first. Each batch is
+// sent before the next one is read, so a slow client holds back the export
+// through flow control rather than buffering it in memory.
+func (s *UserServiceServer) ExportUsers(req *pb.ExportUsersRequest, stream pb.UserService_ExportUsersServer) error {
+	ctx := stream.Context()
+
+	encoder, err := newUserExportEncoder(req.Format)
+	if err != nil {
+		return err
+	}
+
+	err = s.userUseCase.ExportUsers(ctx, listFilterToDTO(req.Filter, req.FilterExpression), int(req.BatchSize), func(userDTOs []*dto.UserDTO) error {
+		users := make([]*pb.User, len(userDTOs))
+		for i, userDTO := range userDTOs {
+			users[i] = mapper.UserDTOToProto(userDTO)
+		}
+		resp, err := encoder.encode(users)
+		if err != nil {
+			return err
+		}
+		return stream.Send(resp)
+	})
+	if err != nil && ctx.Err() != nil {
+		// The client went away or the deadline passed
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return err
+}
+
+// userExportEncoder encodes batches of exported users into response messages
+type userExportEncoder interface {
+	encode(users []*pb.User) (*pb.ExportUsersResponse, error)
+}
+
+// newUserExportEncoder returns the encoder of an export format
+func newUserExportEncoder(format pb.ExportFormat) (userExportEncoder, error) {
+	switch format {
+	case pb.ExportFormat_EXPORT_FORMAT_UNSPECIFIED:
+		return protoExportEncoder{}, nil
+	case pb.ExportFormat_EXPORT_FORMAT_CSV:
+		return &csvExportEncoder{}, nil
+	case pb.ExportFormat_EXPORT_FORMAT_NDJSON:
+		return ndjsonExportEncoder{}, nil
+	default:
+		return nil, apperrors.InvalidField("format", fmt.Sprintf("unknown export format %v", format))
+	}
+}
+
+// protoExportEncoder sends users as User messages
+type protoExportEncoder struct{}
+
+func (protoExportEncoder) encode(users []*pb.User) (*pb.ExportUsersResponse, error) {
+	return &pb.ExportUsersResponse{
+		Batch: &pb.ExportUsersResponse_Users{Users: &pb.UserBatch{Users: users}},
+	}, nil
+}
+
+// ndjsonExportEncoder sends users as newline-delimited JSON
+type ndjsonExportEncoder struct{}
+
+func (ndjsonExportEncoder) encode(users []*pb.User) (*pb.ExportUsersResponse, error) {
+	var buf bytes.Buffer
+	for _, user := range users {
+		line, err := protojson.Marshal(user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode user %s: %w", user.Id, err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return &pb.ExportUsersResponse{Batch: &pb.ExportUsersResponse_Chunk{Chunk: buf.Bytes()}}, nil
+}
+
+// csvExportEncoder sends users as CSV rows, preceded by a header row in the first chunk
+type csvExportEncoder struct {
+	headerWritten bool
+}
+
+// userCSVColumns are the columns of CSV exports
+var userCSVColumns = []struct {
+	name  string
+	value func(user *pb.User) string
+}{
+	{"id", func(u *pb.User) string { return u.Id }},
+	{"email", func(u *pb.User) string { return u.Email }},
+	{"username", func(u *pb.User) string { return u.Username }},
+	{"first_name", func(u *pb.User) string { return u.FirstName }},
+	{"last_name", func(u *pb.User) string { return u.LastName }},
+	{"family_name_kana", func(u *pb.User) string { return u.FamilyNameKana }},
+	{"given_name_kana", func(u *pb.User) string { return u.GivenNameKana }},
+	{"status", func(u *pb.User) string { return strings.TrimPrefix(u.Status.String(), "USER_STATUS_") }},
+	{"is_active", func(u *pb.User) string { return strconv.FormatBool(u.IsActive) }},
+	{"is_admin", func(u *pb.User) string { return strconv.FormatBool(u.IsAdmin) }},
+	{"locale", func(u *pb.User) string { return u.Locale }},
+	{"created_at", func(u *pb.User) string { return formatExportTime(u.CreatedAt) }},
+	{"updated_at", func(u *pb.User) string { return formatExportTime(u.UpdatedAt) }},
+	{"last_login_at", func(u *pb.User) string { return formatExportTime(u.LastLoginAt) }},
+	{"valid_from", func(u *pb.User) string { return formatExportTime(u.ValidFrom) }},
+	{"valid_until", func(u *pb.User) string { return formatExportTime(u.ValidUntil) }},
+	{"attributes", func(u *pb.User) string {
+		if len(u.Attributes.GetFields()) == 0 {
+			return ""
+		}
+		attributes, err := protojson.Marshal(u.Attributes)
+		if err != nil {
+			return ""
+		}
+		return string(attributes)
+	}},
+}
+
+func (e *csvExportEncoder) encode(users []*pb.User) (*pb.ExportUsersResponse, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if !e.headerWritten {
+		header := make([]string, len(userCSVColumns))
+		for i, column := range userCSVColumns {
+			header[i] = column.name
+		}
+		if err := w.Write(header); err != nil {
+			return nil, fmt.Errorf("failed to encode csv header: %w", err)
+		}
+		e.headerWritten = true
+	}
+
+	record := make([]string, len(userCSVColumns))
+	for _, user := range users {
+		for i, column := range userCSVColumns {
+			record[i] = escapeCSVFormula(column.value(user))
+		}
+		if err := w.Write(record); err != nil {
+			return nil, fmt.Errorf("failed to encode user %s: %w", user.Id, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to encode users: %w", err)
+	}
+	return &pb.ExportUsersResponse{Batch: &pb.ExportUsersResponse_Chunk{Chunk: buf.Bytes()}}, nil
+}
+
+// formatExportTime formats a timestamp as RFC 3339, or empty when unset
+func formatExportTime(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.AsTime().Format(time.RFC3339Nano)
+}
+
+// escapeCSVFormula prefixes cells spreadsheets would evaluate as formulas with a
+// single quote, so that exported user input cannot run in the reader's spreadsheet
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -2,41 +2,46 @@ package grpc
 
 import (
 	"context"
+	"errors"
 
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/mapper"
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/usecase"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/server"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
 	commonpb "github.com/gigi434/sample-grpc-server/pkg/generated/common"
 	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
 	"github.com/google/uuid"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // UserServiceServer implements the UserService gRPC server
 type UserServiceServer struct {
 	pb.UnimplementedUserServiceServer
-	userUseCase *usecase.UserUseCase
+	userUseCase           *usecase.UserUseCase
+	attributeUseCase      *usecase.AttributeUseCase
+	invitationUseCase     *usecase.InvitationUseCase
+	identifierRuleUseCase *usecase.IdentifierRuleUseCase
 }
 
 // NewUserServiceServer creates a new UserServiceServer instance
-func NewUserServiceServer(userUseCase *usecase.UserUseCase) *UserServiceServer {
+func NewUserServiceServer(userUseCase *usecase.UserUseCase, attributeUseCase *usecase.AttributeUseCase, invitationUseCase *usecase.InvitationUseCase, identifierRuleUseCase *usecase.IdentifierRuleUseCase) *UserServiceServer {
 	return &UserServiceServer{
-		userUseCase: userUseCase,
+		userUseCase:           userUseCase,
+		attributeUseCase:      attributeUseCase,
+		invitationUseCase:     invitationUseCase,
+		identifierRuleUseCase: identifierRuleUseCase,
 	}
 }
 
 // CreateUser creates a new user
 func (s *UserServiceServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
 	// Validate request
-	if req.Email == "" {
-		return nil, status.Error(codes.InvalidArgument, "email is required")
-	}
-	if req.Username == "" {
-		return nil, status.Error(codes.InvalidArgument, "username is required")
-	}
-	if req.Password == "" {
-		return nil, status.Error(codes.InvalidArgument, "password is required")
+	if err := validateCreateUserRequest(req); err != nil {
+		return nil, err
 	}
 
 	// Convert request to DTO
@@ -45,7 +50,7 @@ func (s *UserServiceServer) CreateUser(ctx context.Context, req *pb.CreateUserRe
 	// Create user
 	userDTO, err := s.userUseCase.CreateUser(ctx, createDTO)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
 	// Convert DTO to proto
@@ -54,17 +59,31 @@ func (s *UserServiceServer) CreateUser(ctx context.Context, req *pb.CreateUserRe
 	}, nil
 }
 
+// validateCreateUserRequest checks the fields a user cannot be created without
+func validateCreateUserRequest(req *pb.CreateUserRequest) error {
+	if req.Email == "" {
+		return apperrors.MissingField("email")
+	}
+	if req.Username == "" {
+		return apperrors.MissingField("username")
+	}
+	if req.Password == "" {
+		return apperrors.MissingField("password")
+	}
+	return nil
+}
+
 // GetUser retrieves a user by ID
 func (s *UserServiceServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
 	// Validate request
 	if req.Id == "" {
-		return nil, status.Error(codes.InvalidArgument, "id is required")
+		return nil, apperrors.MissingField("id")
 	}
 
 	// Get user
 	userDTO, err := s.userUseCase.GetUser(ctx, req.Id)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, err
 	}
 
 	// Convert DTO to proto
@@ -91,17 +110,17 @@ func (s *UserServiceServer) ListUsers(ctx context.Context, req *pb.ListUsersRequ
 			pageSize = 100
 		}
 	}
-
-	// Convert filter
-	var filter *dto.FilterDTO
-	if req.Filter != nil {
-		filter = mapper.ListUsersFilterToDTO(req.Filter)
+	pageDTO := &dto.PageRequestDTO{Page: page, PageSize: pageSize}
+	if req.Pagination != nil {
+		pageDTO.PageToken = req.Pagination.PageToken
+		pageDTO.IncludeTotal = req.Pagination.IncludeTotal
 	}
+	sort := mapper.PaginationSortToDTO(req.Pagination)
 
 	// List users
-	listDTO, err := s.userUseCase.ListUsers(ctx, page, pageSize, filter)
+	listDTO, err := s.userUseCase.ListUsers(ctx, pageDTO, listFilterToDTO(req.Filter, req.FilterExpression), sort)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
 	// Convert DTOs to proto
@@ -111,18 +130,16 @@ func (s *UserServiceServer) ListUsers(ctx context.Context, req *pb.ListUsersRequ
 	}
 
 	// Create pagination response
-	hasNext := listDTO.Page < listDTO.TotalPages
-	hasPrevious := listDTO.Page > 1
-
 	return &pb.ListUsersResponse{
 		Users: users,
 		Pagination: &commonpb.PaginationResponse{
-			Page:        int32(listDTO.Page),
-			PageSize:    int32(listDTO.PageSize),
-			TotalItems:  int32(listDTO.TotalItems),
-			TotalPages:  int32(listDTO.TotalPages),
-			HasNext:     hasNext,
-			HasPrevious: hasPrevious,
+			Page:          int32(listDTO.Page),
+			PageSize:      int32(listDTO.PageSize),
+			TotalItems:    int32(listDTO.TotalItems),
+			TotalPages:    int32(listDTO.TotalPages),
+			HasNext:       listDTO.HasNext,
+			HasPrevious:   listDTO.HasPrevious,
+			NextPageToken: listDTO.NextPageToken,
 		},
 	}, nil
 }
@@ -131,24 +148,25 @@ func (s *UserServiceServer) ListUsers(ctx context.Context, req *pb.ListUsersRequ
 func (s *UserServiceServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
 	// Validate request
 	if req.Id == "" {
-		return nil, status.Error(codes.InvalidArgument, "id is required")
+		return nil, apperrors.MissingField("id")
 	}
 
 	// Convert request to DTO
 	updateDTO, err := mapper.UpdateUserRequestToDTO(req)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 
 	// Update user
-	userDTO, err := s.userUseCase.UpdateUser(ctx, updateDTO)
+	userDTO, changedFields, err := s.userUseCase.UpdateUser(ctx, updateDTO)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
 	// Convert DTO to proto
 	return &pb.UpdateUserResponse{
-		User: mapper.UserDTOToProto(userDTO),
+		User:          mapper.UserDTOToProto(userDTO),
+		ChangedFields: &fieldmaskpb.FieldMask{Paths: changedFields},
 	}, nil
 }
 
@@ -156,13 +174,13 @@ func (s *UserServiceServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRe
 func (s *UserServiceServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
 	// Validate request
 	if req.Id == "" {
-		return nil, status.Error(codes.InvalidArgument, "id is required")
+		return nil, apperrors.MissingField("id")
 	}
 
 	// Delete user
-	err := s.userUseCase.DeleteUser(ctx, req.Id, req.HardDelete)
+	err := s.userUseCase.DeleteUser(ctx, req.Id, req.HardDelete, req.Etag)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
 	return &pb.DeleteUserResponse{
@@ -171,34 +189,87 @@ func (s *UserServiceServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRe
 	}, nil
 }
 
+// MergeUsers merges a duplicate user into the user that survives, or previews the merge
+func (s *UserServiceServer) MergeUsers(ctx context.Context, req *pb.MergeUsersRequest) (*pb.MergeUsersResponse, error) {
+	// Validate request
+	if req.SourceId == "" {
+		return nil, apperrors.MissingField("source_id")
+	}
+	if req.TargetId == "" {
+		return nil, apperrors.MissingField("target_id")
+	}
+
+	// Convert request to DTO
+	mergeDTO := mapper.MergeUsersRequestToDTO(req)
+
+	// Merge users
+	result, err := s.userUseCase.MergeUsers(ctx, mergeDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTO to proto
+	return mapper.MergeUsersResultDTOToProto(result), nil
+}
+
 // BatchGetUsers retrieves multiple users by IDs
 func (s *UserServiceServer) BatchGetUsers(ctx context.Context, req *pb.BatchGetUsersRequest) (*pb.BatchGetUsersResponse, error) {
 	// Validate request
 	if len(req.Ids) == 0 {
-		return nil, status.Error(codes.InvalidArgument, "at least one id is required")
+		return nil, apperrors.MissingField("ids")
 	}
 
 	// Limit batch size to prevent abuse
 	if len(req.Ids) > 100 {
-		return nil, status.Error(codes.InvalidArgument, "batch size cannot exceed 100")
+		return nil, apperrors.InvalidField("ids", "batch size cannot exceed 100")
 	}
 
 	// Get users
-	users, notFound, err := s.userUseCase.BatchGetUsers(ctx, req.Ids)
+	results, err := s.userUseCase.BatchGetUsers(ctx, req.Ids)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
 	// Convert DTOs to proto
-	protoUsers := make(map[string]*pb.User)
-	for id, userDTO := range users {
-		protoUsers[id] = mapper.UserDTOToProto(userDTO)
+	resp := &pb.BatchGetUsersResponse{
+		Users:      make(map[string]*pb.User),
+		NotFound:   make([]string, 0),
+		InvalidIds: make([]string, 0),
+	}
+	for _, result := range results {
+		var user *pb.User
+		switch {
+		case errors.Is(result.Err, entity.ErrInvalidUserID):
+			resp.InvalidIds = append(resp.InvalidIds, result.ID)
+		case result.Err != nil:
+			resp.NotFound = append(resp.NotFound, result.ID)
+		default:
+			user = mapper.UserDTOToProto(result.User)
+			resp.Users[result.ID] = user
+		}
+
+		if req.Ordered {
+			protoResult := &pb.BatchGetUserResult{Id: result.ID, User: user, Status: &spb.Status{}}
+			if result.Err != nil {
+				protoResult.Status = server.ErrorStatus(ctx, result.Err, ErrorTranslator()).Proto()
+			}
+			resp.Results = append(resp.Results, protoResult)
+		}
 	}
 
-	return &pb.BatchGetUsersResponse{
-		Users:    protoUsers,
-		NotFound: notFound,
-	}, nil
+	return resp, nil
+}
+
+// listFilterToDTO converts the filter and filter expression of a listing; nil when both are empty
+func listFilterToDTO(filter *pb.ListUsersFilter, expression string) *dto.FilterDTO {
+	filterDTO := mapper.ListUsersFilterToDTO(filter)
+	if expression != "" {
+		if filterDTO == nil {
+			filterDTO = &dto.FilterDTO{}
+		}
+		filterDTO.Expression = expression
+	}
+	return filterDTO
 }
 
 // SearchUsers searches users by various criteria
@@ -219,12 +290,18 @@ func (s *UserServiceServer) SearchUsers(ctx context.Context, req *pb.SearchUsers
 			pageSize = 100
 		}
 	}
+	pageDTO := &dto.PageRequestDTO{Page: page, PageSize: pageSize}
+	if req.Pagination != nil {
+		pageDTO.PageToken = req.Pagination.PageToken
+		pageDTO.IncludeTotal = req.Pagination.IncludeTotal
+	}
+	sort := mapper.PaginationSortToDTO(req.Pagination)
 
 	// Create search DTO
 	searchDTO := &dto.SearchUsersDTO{
-		Query:    req.Query,
-		Page:     page,
-		PageSize: pageSize,
+		Query:      req.Query,
+		Pagination: pageDTO,
+		Sort:       sort,
 	}
 
 	if req.Filter != nil {
@@ -234,7 +311,7 @@ func (s *UserServiceServer) SearchUsers(ctx context.Context, req *pb.SearchUsers
 	// Search users
 	listDTO, err := s.userUseCase.SearchUsers(ctx, searchDTO)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
 	// Convert DTOs to proto
@@ -242,22 +319,25 @@ func (s *UserServiceServer) SearchUsers(ctx context.Context, req *pb.SearchUsers
 	for i, userDTO := range listDTO.Users {
 		users[i] = mapper.UserDTOToProto(userDTO)
 	}
+	matches := make([]*pb.UserMatch, len(listDTO.Matches))
+	for i, matchDTO := range listDTO.Matches {
+		matches[i] = mapper.UserMatchDTOToProto(matchDTO)
+	}
 
 	// Create pagination response
-	hasNext := listDTO.Page < listDTO.TotalPages
-	hasPrevious := listDTO.Page > 1
-
 	return &pb.SearchUsersResponse{
 		Users: users,
 		Pagination: &commonpb.PaginationResponse{
-			Page:        int32(listDTO.Page),
-			PageSize:    int32(listDTO.PageSize),
-			TotalItems:  int32(listDTO.TotalItems),
-			TotalPages:  int32(listDTO.TotalPages),
-			HasNext:     hasNext,
-			HasPrevious: hasPrevious,
+			Page:          int32(listDTO.Page),
+			PageSize:      int32(listDTO.PageSize),
+			TotalItems:    int32(listDTO.TotalItems),
+			TotalPages:    int32(listDTO.TotalPages),
+			HasNext:       listDTO.HasNext,
+			HasPrevious:   listDTO.HasPrevious,
+			NextPageToken: listDTO.NextPageToken,
 		},
-		TotalMatches: int32(listDTO.TotalItems),
+		TotalMatches: int32(listDTO.TotalMatches),
+		Matches:      matches,
 	}, nil
 }
 
@@ -265,19 +345,19 @@ func (s *UserServiceServer) SearchUsers(ctx context.Context, req *pb.SearchUsers
 func (s *UserServiceServer) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
 	// Validate request
 	if req.UserId == "" {
-		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+		return nil, apperrors.MissingField("user_id")
 	}
 	if req.OldPassword == "" {
-		return nil, status.Error(codes.InvalidArgument, "old_password is required")
+		return nil, apperrors.MissingField("old_password")
 	}
 	if req.NewPassword == "" {
-		return nil, status.Error(codes.InvalidArgument, "new_password is required")
+		return nil, apperrors.MissingField("new_password")
 	}
 
 	// Parse user ID
 	userID, err := uuid.Parse(req.UserId)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+		return nil, apperrors.InvalidField("user_id", "must be a UUID")
 	}
 
 	// Create DTO
@@ -285,11 +365,12 @@ func (s *UserServiceServer) ChangePassword(ctx context.Context, req *pb.ChangePa
 		UserID:      userID,
 		OldPassword: req.OldPassword,
 		NewPassword: req.NewPassword,
+		ETag:        req.Etag,
 	}
 
 	// Change password
 	if err := s.userUseCase.ChangePassword(ctx, changeDTO); err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
 	return &pb.ChangePasswordResponse{
@@ -302,32 +383,59 @@ func (s *UserServiceServer) ChangePassword(ctx context.Context, req *pb.ChangePa
 func (s *UserServiceServer) AuthenticateUser(ctx context.Context, req *pb.AuthenticateUserRequest) (*pb.AuthenticateUserResponse, error) {
 	// Validate request
 	if req.Identifier == "" {
-		return nil, status.Error(codes.InvalidArgument, "identifier is required")
+		return nil, apperrors.MissingField("identifier")
 	}
 	if req.Password == "" {
-		return nil, status.Error(codes.InvalidArgument, "password is required")
+		return nil, apperrors.MissingField("password")
 	}
 
 	// Create DTO
+	ipAddress, userAgent := clientInfo(ctx)
 	authDTO := &dto.AuthenticateDTO{
 		Identifier: req.Identifier,
 		Password:   req.Password,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
 	}
 
 	// Authenticate user
-	userDTO, err := s.userUseCase.AuthenticateUser(ctx, authDTO)
+	authentication, err := s.userUseCase.AuthenticateUser(ctx, authDTO)
 	if err != nil {
-		return &pb.AuthenticateUserResponse{
-			Success: false,
-			Message: "Invalid credentials",
-		}, nil
+		return nil, err
+	}
+
+	message := "Authentication successful"
+	if authentication.PasswordChangeRequired {
+		message = "Password change required"
 	}
 
 	// Convert DTO to proto
 	return &pb.AuthenticateUserResponse{
-		User:    mapper.UserDTOToProto(userDTO),
-		Success: true,
-		Message: "Authentication successful",
-		// TODO: Generate JWT token here
+		User:                   mapper.UserDTOToProto(authentication.User),
+		Success:                true,
+		Message:                message,
+		Token:                  &authentication.Token,
+		PasswordChangeRequired: authentication.PasswordChangeRequired,
+		TokenExpiresAt:         timestamppb.New(authentication.TokenExpiresAt),
+	}, nil
+}
+
+// SetTemporaryPassword replaces a user's password with a one-time password that must be changed
+func (s *UserServiceServer) SetTemporaryPassword(ctx context.Context, req *pb.SetTemporaryPasswordRequest) (*pb.SetTemporaryPasswordResponse, error) {
+	// Validate request
+	if req.Id == "" {
+		return nil, apperrors.MissingField("id")
+	}
+
+	// Set temporary password
+	result, err := s.userUseCase.SetTemporaryPassword(ctx, req.Id, req.Etag)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert DTO to proto
+	return &pb.SetTemporaryPasswordResponse{
+		User:              mapper.UserDTOToProto(result.User),
+		TemporaryPassword: result.TemporaryPassword,
 	}, nil
 }
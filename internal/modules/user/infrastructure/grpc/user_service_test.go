@@ -0,0 +1,190 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/usecase"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/service"
+	"github.com/gigi434/sample-grpc-server/internal/shared/auth"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+	"github.com/google/uuid"
+)
+
+// stubUserRepository serves users from memory and records updates. Methods it
+// does not override panic through the nil embedded interface, so a test fails
+// loudly when a handler reaches further than the test expects.
+type stubUserRepository struct {
+	repository.UserRepository
+	users   map[uuid.UUID]*entity.User
+	updated []uuid.UUID
+}
+
+func newStubUserRepository(users ...*entity.User) *stubUserRepository {
+	repo := &stubUserRepository{users: make(map[uuid.UUID]*entity.User, len(users))}
+	for _, user := range users {
+		repo.users[user.ID] = user
+	}
+	return repo
+}
+
+func (r *stubUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, entity.ErrUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *stubUserRepository) Update(ctx context.Context, user *entity.User) error {
+	r.updated = append(r.updated, user.ID)
+	return nil
+}
+
+// stubGroupRoles serves the group roles of users from memory
+type stubGroupRoles map[uuid.UUID][]string
+
+func (r stubGroupRoles) TenantRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	return r[userID], nil
+}
+
+// newTestServer returns a UserServiceServer whose user use case reads users from repo
+func newTestServer(repo repository.UserRepository, groupRoles stubGroupRoles) *UserServiceServer {
+	userService := service.NewUserService(repo, nil, nil, nil, groupRoles, entity.UsernamePolicy{}, nil, entity.PasswordPolicy{})
+	userUseCase := usecase.NewUserUseCase(repo, userService, nil, nil, nil, nil)
+	return NewUserServiceServer(userUseCase, nil, nil, nil)
+}
+
+// callerContext returns a context carrying the token claims of the given user
+func callerContext(userID uuid.UUID) context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{UserID: userID, Scope: auth.ScopeFull})
+}
+
+// errorCode returns the application error code a handler error is sent to clients with
+func errorCode(err error) apperrors.Code {
+	return apperrors.Translate(err, ErrorTranslator()).Code
+}
+
+func TestSetTemporaryPasswordRequiresAdmin(t *testing.T) {
+	admin := &entity.User{ID: uuid.New(), IsAdmin: true, IsActive: true}
+	member := &entity.User{ID: uuid.New(), IsActive: true}
+	inactiveAdmin := &entity.User{ID: uuid.New(), IsAdmin: true}
+	groupAdmin := &entity.User{ID: uuid.New(), IsActive: true}
+	groupRoles := stubGroupRoles{
+		member.ID:     {"support"},
+		groupAdmin.ID: {entity.AdminRole, "support"},
+	}
+	target := &entity.User{ID: uuid.New(), IsActive: true}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want apperrors.Code
+	}{
+		{name: "admin", ctx: callerContext(admin.ID)},
+		{name: "admin through a group", ctx: callerContext(groupAdmin.ID)},
+		{name: "non-admin", ctx: callerContext(member.ID), want: apperrors.CodePermissionDenied},
+		{name: "inactive admin", ctx: callerContext(inactiveAdmin.ID), want: apperrors.CodePermissionDenied},
+		{name: "unknown caller", ctx: callerContext(uuid.New()), want: apperrors.CodePermissionDenied},
+		{name: "no caller", ctx: context.Background(), want: apperrors.CodePermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newStubUserRepository(admin, member, inactiveAdmin, groupAdmin, target)
+			server := newTestServer(repo, groupRoles)
+
+			resp, err := server.SetTemporaryPassword(tt.ctx, &pb.SetTemporaryPasswordRequest{Id: target.ID.String()})
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("SetTemporaryPassword() error = %v", err)
+				}
+				if resp.TemporaryPassword == "" {
+					t.Errorf("SetTemporaryPassword() returned no temporary password")
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("SetTemporaryPassword() error = nil, want %s", tt.want)
+			}
+			if got := errorCode(err); got != tt.want {
+				t.Errorf("SetTemporaryPassword() error code = %s, want %s (%v)", got, tt.want, err)
+			}
+			if len(repo.updated) != 0 {
+				t.Errorf("SetTemporaryPassword() updated %v, want no update", repo.updated)
+			}
+		})
+	}
+}
+
+func TestMergeUsersRequiresAdmin(t *testing.T) {
+	member := &entity.User{ID: uuid.New(), IsActive: true}
+	source := &entity.User{ID: uuid.New(), IsActive: true}
+	target := &entity.User{ID: uuid.New(), IsActive: true}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		dryRun bool
+	}{
+		{name: "non-admin", ctx: callerContext(member.ID)},
+		{name: "non-admin dry run", ctx: callerContext(member.ID), dryRun: true},
+		{name: "no caller", ctx: context.Background()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(newStubUserRepository(member, source, target), nil)
+
+			_, err := server.MergeUsers(tt.ctx, &pb.MergeUsersRequest{
+				SourceId: source.ID.String(),
+				TargetId: target.ID.String(),
+				DryRun:   tt.dryRun,
+			})
+			if got := errorCode(err); err == nil || got != apperrors.CodePermissionDenied {
+				t.Errorf("MergeUsers() error = %v, want %s", err, apperrors.CodePermissionDenied)
+			}
+		})
+	}
+}
+
+func TestListLoginEventsRequiresSelfOrAdmin(t *testing.T) {
+	member := &entity.User{ID: uuid.New(), IsActive: true}
+	other := &entity.User{ID: uuid.New(), IsActive: true}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{name: "another user", ctx: callerContext(member.ID)},
+		{name: "no caller", ctx: context.Background()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(newStubUserRepository(member, other), nil)
+
+			_, err := server.ListLoginEvents(tt.ctx, &pb.ListLoginEventsRequest{UserId: other.ID.String()})
+			if got := errorCode(err); err == nil || got != apperrors.CodePermissionDenied {
+				t.Errorf("ListLoginEvents() error = %v, want %s", err, apperrors.CodePermissionDenied)
+			}
+		})
+	}
+}
+
+func TestListUsersRejectsSuspendedStatus(t *testing.T) {
+	admin := &entity.User{ID: uuid.New(), IsAdmin: true, IsActive: true}
+	server := newTestServer(newStubUserRepository(admin), nil)
+
+	status := pb.UserStatus_USER_STATUS_SUSPENDED
+	_, err := server.ListUsers(callerContext(admin.ID), &pb.ListUsersRequest{
+		Filter: &pb.ListUsersFilter{Status: &status},
+	})
+	if got := errorCode(err); err == nil || got != apperrors.CodeInvalidArgument {
+		t.Errorf("ListUsers() error = %v, want %s", err, apperrors.CodeInvalidArgument)
+	}
+}
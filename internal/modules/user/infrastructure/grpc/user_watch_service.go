@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/dto"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/application/mapper"
+	pb "github.com/gigi434/sample-grpc-server/pkg/generated/v1/user"
+	"google.golang.org/grpc/status"
+)
+
+// WatchUsers streams changes to users matching a filter as they commit, until
+// the client cancels. Each response is sent before the next changes are read,
+// so a slow client holds back its own watch rather than buffering it in memory.
+func (s *UserServiceServer) WatchUsers(req *pb.WatchUsersRequest, stream pb.UserService_WatchUsersServer) error {
+	ctx := stream.Context()
+
+	err := s.userUseCase.WatchUsers(ctx, listFilterToDTO(req.Filter, req.FilterExpression), req.ResumeToken, func(changeDTOs []*dto.UserChangeDTO, resumeToken string) error {
+		changes := make([]*pb.UserChange, len(changeDTOs))
+		for i, changeDTO := range changeDTOs {
+			changes[i] = mapper.UserChangeDTOToProto(changeDTO)
+		}
+		return stream.Send(&pb.WatchUsersResponse{Changes: changes, ResumeToken: resumeToken})
+	})
+	if err != nil && ctx.Err() != nil {
+		// The client went away or the deadline passed
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return err
+}
@@ -0,0 +1,26 @@
+package notification
+
+import (
+	"context"
+	"log"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+)
+
+// logNotifier implements repository.AccountNotifier by writing notifications to
+// the server log. It only records that a notification was due, by user ID, so that
+// no contact details end up in the log; delivering them is left to a mail or
+// messaging integration configured in its place.
+type logNotifier struct{}
+
+// NewLogNotifier creates a new instance of AccountNotifier that logs notifications
+func NewLogNotifier() repository.AccountNotifier {
+	return &logNotifier{}
+}
+
+// NotifyAccountExpired logs that a user's account expired
+func (n *logNotifier) NotifyAccountExpired(ctx context.Context, user *entity.User) error {
+	log.Printf("Notify user %s: account expired at %s and was deactivated", user.ID, user.ValidUntil)
+	return nil
+}
@@ -0,0 +1,135 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// attributeDefinitionRepository implements repository.AttributeDefinitionRepository
+type attributeDefinitionRepository struct {
+	// We don't store the DB connection here, we get it from config singleton
+}
+
+// NewAttributeDefinitionRepository creates a new instance of AttributeDefinitionRepository
+func NewAttributeDefinitionRepository() repository.AttributeDefinitionRepository {
+	return &attributeDefinitionRepository{}
+}
+
+// getDB gets the database connection, joining the transaction of the context if any
+func (r *attributeDefinitionRepository) getDB(ctx context.Context) (*gorm.DB, error) {
+	return database.Conn(ctx)
+}
+
+// Create creates a new attribute definition
+func (r *attributeDefinitionRepository) Create(ctx context.Context, definition *entity.AttributeDefinition) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	// The indexes enforcing a unique attribute are created along with it
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(definition).Error; err != nil {
+			return fmt.Errorf("failed to create attribute definition: %w", err)
+		}
+		if definition.Unique {
+			return database.CreateAttributeIndexes(tx, definition.Key)
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves an attribute definition by ID
+func (r *attributeDefinitionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.AttributeDefinition, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var definition entity.AttributeDefinition
+	if err := db.WithContext(ctx).First(&definition, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrAttributeDefinitionNotFound
+		}
+		return nil, fmt.Errorf("failed to get attribute definition by ID: %w", err)
+	}
+	return &definition, nil
+}
+
+// GetByKey retrieves an attribute definition by key
+func (r *attributeDefinitionRepository) GetByKey(ctx context.Context, key string) (*entity.AttributeDefinition, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var definition entity.AttributeDefinition
+	if err := db.WithContext(ctx).Where("key = ?", key).First(&definition).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrAttributeDefinitionNotFound
+		}
+		return nil, fmt.Errorf("failed to get attribute definition by key: %w", err)
+	}
+	return &definition, nil
+}
+
+// List retrieves all attribute definitions ordered by key
+func (r *attributeDefinitionRepository) List(ctx context.Context) ([]*entity.AttributeDefinition, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var definitions []*entity.AttributeDefinition
+	if err := db.WithContext(ctx).Order("key ASC").Find(&definitions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list attribute definitions: %w", err)
+	}
+	return definitions, nil
+}
+
+// Update updates an existing attribute definition
+func (r *attributeDefinitionRepository) Update(ctx context.Context, definition *entity.AttributeDefinition) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	// Indexes enforce the attribute's uniqueness exactly while it is unique
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(definition).Error; err != nil {
+			return fmt.Errorf("failed to update attribute definition: %w", err)
+		}
+		if definition.Unique {
+			return database.CreateAttributeIndexes(tx, definition.Key)
+		}
+		return database.DropAttributeIndexes(tx, definition.Key)
+	})
+}
+
+// Delete deletes an attribute definition
+func (r *attributeDefinitionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var definition entity.AttributeDefinition
+		result := tx.Clauses(clause.Returning{}).Where("id = ?", id).Delete(&definition)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete attribute definition: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrAttributeDefinitionNotFound
+		}
+		return database.DropAttributeIndexes(tx, definition.Key)
+	})
+}
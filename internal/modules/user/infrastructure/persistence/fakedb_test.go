@@ -0,0 +1,120 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeDriver is a database/sql driver that records the transaction control
+// statements it receives and succeeds at everything, so that tests can check how
+// code drives transactions without a database
+type fakeDriver struct {
+	mu  sync.Mutex
+	log []string
+}
+
+// fakeDrivers registers one driver per test, as sql.Register cannot unregister
+var fakeDrivers sync.Map
+
+// newFakeDB returns a gorm DB backed by a fakeDriver
+func newFakeDB(t *testing.T) (*gorm.DB, *fakeDriver) {
+	t.Helper()
+	fake := &fakeDriver{}
+	name := "fake-" + t.Name()
+	if _, loaded := fakeDrivers.LoadOrStore(name, fake); loaded {
+		t.Fatalf("fake driver %s registered twice", name)
+	}
+	sql.Register(name, fake)
+
+	sqlDB, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	return db, fake
+}
+
+// statements returns the statements recorded so far
+func (d *fakeDriver) statements() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.log...)
+}
+
+func (d *fakeDriver) record(statement string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, statement)
+}
+
+// Open implements driver.Driver
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+// Prepare implements driver.Conn
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fake driver does not prepare statements")
+}
+
+// Close implements driver.Conn
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+// Begin implements driver.Conn
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.driver.record("BEGIN")
+	return &fakeTx{driver: c.driver}, nil
+}
+
+// ExecContext implements driver.ExecerContext, recording savepoint statements
+// without their names
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	switch {
+	case strings.HasPrefix(query, "ROLLBACK TO SAVEPOINT "):
+		c.driver.record("ROLLBACK TO SAVEPOINT")
+	case strings.HasPrefix(query, "SAVEPOINT "):
+		c.driver.record("SAVEPOINT")
+	default:
+		c.driver.record(query)
+	}
+	return driver.RowsAffected(0), nil
+}
+
+type fakeTx struct {
+	driver *fakeDriver
+}
+
+// Commit implements driver.Tx
+func (tx *fakeTx) Commit() error {
+	tx.driver.record("COMMIT")
+	return nil
+}
+
+// Rollback implements driver.Tx
+func (tx *fakeTx) Rollback() error {
+	tx.driver.record("ROLLBACK")
+	return nil
+}
@@ -0,0 +1,106 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/database"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// identifierRuleRepository implements repository.IdentifierRuleRepository
+type identifierRuleRepository struct {
+	// We don't store the DB connection here, we get it from config singleton
+}
+
+// NewIdentifierRuleRepository creates a new instance of IdentifierRuleRepository
+func NewIdentifierRuleRepository() repository.IdentifierRuleRepository {
+	return &identifierRuleRepository{}
+}
+
+// getDB gets the database connection, joining the transaction of the context if any
+func (r *identifierRuleRepository) getDB(ctx context.Context) (*gorm.DB, error) {
+	return database.Conn(ctx)
+}
+
+// Create creates a new identifier rule
+func (r *identifierRuleRepository) Create(ctx context.Context, rule *entity.IdentifierRule) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	if err := db.WithContext(ctx).Create(rule).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return fmt.Errorf("%w: %s %q", entity.ErrIdentifierRuleAlreadyExists, rule.Kind, rule.Value)
+		}
+		return fmt.Errorf("failed to create identifier rule: %w", err)
+	}
+	return nil
+}
+
+// CreateMissing creates the rules that do not exist yet and returns how many were created
+func (r *identifierRuleRepository) CreateMissing(ctx context.Context, rules []*entity.IdentifierRule) (int64, error) {
+	if len(rules) == 0 {
+		return 0, nil
+	}
+
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	result := db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "kind"}, {Name: "value"}},
+			DoNothing: true,
+		}).
+		Create(&rules)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to create identifier rules: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// List retrieves identifier rules ordered by kind and value
+func (r *identifierRuleRepository) List(ctx context.Context, kind entity.IdentifierRuleKind) ([]*entity.IdentifierRule, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := db.WithContext(ctx)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+
+	var rules []*entity.IdentifierRule
+	if err := query.Order("kind ASC, value ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list identifier rules: %w", err)
+	}
+	return rules, nil
+}
+
+// Delete deletes an identifier rule
+func (r *identifierRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	result := db.WithContext(ctx).Delete(&entity.IdentifierRule{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete identifier rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrIdentifierRuleNotFound
+	}
+	return nil
+}
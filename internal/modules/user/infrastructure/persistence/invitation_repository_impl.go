@@ -0,0 +1,191 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/database"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// invitationRepository implements repository.InvitationRepository
+type invitationRepository struct {
+	// We don't store the DB connection here, we get it from config singleton
+}
+
+// NewInvitationRepository creates a new instance of InvitationRepository
+func NewInvitationRepository() repository.InvitationRepository {
+	return &invitationRepository{}
+}
+
+// getDB gets the database connection, joining the transaction of the context if any
+func (r *invitationRepository) getDB(ctx context.Context) (*gorm.DB, error) {
+	return database.Conn(ctx)
+}
+
+// scoped returns a query restricted to invitations of the current tenant
+func (r *invitationRepository) scoped(ctx context.Context, db *gorm.DB) *gorm.DB {
+	return db.WithContext(ctx).Scopes(tenant.ScopeOrganization(ctx, "organization_id"))
+}
+
+// pendingScoped returns a query restricted to invitations of the current tenant,
+// or to invitations outside any organization when no tenant is set, so that a
+// request without a tenant does not list every tenant's invitations
+func (r *invitationRepository) pendingScoped(ctx context.Context, db *gorm.DB) *gorm.DB {
+	query := r.scoped(ctx, db)
+	if _, ok := tenant.OrganizationIDFromContext(ctx); !ok && !tenant.IsUnscoped(ctx) {
+		query = query.Where("organization_id IS NULL")
+	}
+	return query
+}
+
+// Create creates a new invitation
+func (r *invitationRepository) Create(ctx context.Context, invitation *entity.Invitation) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	if err := db.WithContext(ctx).Create(invitation).Error; err != nil {
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an invitation by ID within the current tenant
+func (r *invitationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Invitation, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var invitation entity.Invitation
+	if err := r.scoped(ctx, db).First(&invitation, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrInvitationNotFound
+		}
+		return nil, fmt.Errorf("failed to get invitation by ID: %w", err)
+	}
+	return &invitation, nil
+}
+
+// GetByTokenHash retrieves an invitation by the hash of its token, across all tenants
+func (r *invitationRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entity.Invitation, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var invitation entity.Invitation
+	if err := db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&invitation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrInvitationNotFound
+		}
+		return nil, fmt.Errorf("failed to get invitation by token: %w", err)
+	}
+	return &invitation, nil
+}
+
+// ExistsPending checks if an unexpired pending invitation exists for an email in an organization
+func (r *invitationRepository) ExistsPending(ctx context.Context, organizationID *uuid.UUID, email string, now time.Time) (bool, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := db.WithContext(ctx).
+		Model(&entity.Invitation{}).
+		Where("email = ? AND status = ? AND expires_at > ?", email, entity.InvitationStatusPending, now)
+	if organizationID != nil {
+		query = query.Where("organization_id = ?", *organizationID)
+	} else {
+		query = query.Where("organization_id IS NULL")
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check pending invitation: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ListPending retrieves unexpired pending invitations of the current tenant with
+// pagination, or those outside any organization when no tenant is set
+func (r *invitationRepository) ListPending(ctx context.Context, now time.Time, offset, limit int) ([]*entity.Invitation, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var invitations []*entity.Invitation
+	if err := r.pendingScoped(ctx, db).
+		Where("status = ? AND expires_at > ?", entity.InvitationStatusPending, now).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// CountPending returns the number of unexpired pending invitations of the current
+// tenant, or of those outside any organization when no tenant is set
+func (r *invitationRepository) CountPending(ctx context.Context, now time.Time) (int64, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var count int64
+	if err := r.pendingScoped(ctx, db).
+		Model(&entity.Invitation{}).
+		Where("status = ? AND expires_at > ?", entity.InvitationStatusPending, now).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count invitations: %w", err)
+	}
+	return count, nil
+}
+
+// MarkAccepted records that a pending invitation was accepted by a user
+func (r *invitationRepository) MarkAccepted(ctx context.Context, id, userID uuid.UUID, at time.Time) error {
+	return r.transition(ctx, id, map[string]interface{}{
+		"status":           entity.InvitationStatusAccepted,
+		"accepted_at":      at,
+		"accepted_user_id": userID,
+	})
+}
+
+// MarkRevoked records that a pending invitation was revoked
+func (r *invitationRepository) MarkRevoked(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.transition(ctx, id, map[string]interface{}{
+		"status":     entity.InvitationStatusRevoked,
+		"revoked_at": at,
+	})
+}
+
+// transition updates a pending invitation, failing if it is no longer pending
+func (r *invitationRepository) transition(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	result := db.WithContext(ctx).
+		Model(&entity.Invitation{}).
+		Where("id = ? AND status = ?", id, entity.InvitationStatusPending).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update invitation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrInvitationNotPending
+	}
+	return nil
+}
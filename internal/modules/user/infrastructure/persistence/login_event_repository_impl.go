@@ -0,0 +1,84 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/database"
+	"gorm.io/gorm"
+)
+
+// loginEventRepository implements repository.LoginEventRepository
+type loginEventRepository struct {
+	// We don't store the DB connection here, we get it from config singleton
+}
+
+// NewLoginEventRepository creates a new instance of LoginEventRepository
+func NewLoginEventRepository() repository.LoginEventRepository {
+	return &loginEventRepository{}
+}
+
+// getDB gets the database connection, joining the transaction of the context if any
+func (r *loginEventRepository) getDB(ctx context.Context) (*gorm.DB, error) {
+	return database.Conn(ctx)
+}
+
+// filtered returns a query restricted to login events matching the filter
+func (r *loginEventRepository) filtered(ctx context.Context, db *gorm.DB, filter *repository.LoginEventFilter) *gorm.DB {
+	query := db.WithContext(ctx).Model(&entity.LoginEvent{}).Where("user_id = ?", filter.UserID)
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	return query
+}
+
+// Create records a login event
+func (r *loginEventRepository) Create(ctx context.Context, event *entity.LoginEvent) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	if err := db.WithContext(ctx).Create(event).Error; err != nil {
+		return fmt.Errorf("failed to create login event: %w", err)
+	}
+	return nil
+}
+
+// List retrieves login events matching the filter, newest first, with pagination
+func (r *loginEventRepository) List(ctx context.Context, filter *repository.LoginEventFilter, offset, limit int) ([]*entity.LoginEvent, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var events []*entity.LoginEvent
+	if err := r.filtered(ctx, db, filter).
+		Order("created_at DESC").
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list login events: %w", err)
+	}
+	return events, nil
+}
+
+// Count returns the number of login events matching the filter
+func (r *loginEventRepository) Count(ctx context.Context, filter *repository.LoginEventFilter) (int64, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var count int64
+	if err := r.filtered(ctx, db, filter).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count login events: %w", err)
+	}
+	return count, nil
+}
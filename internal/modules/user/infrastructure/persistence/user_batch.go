@@ -0,0 +1,317 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// batchChunkSize is the most rows one statement of a batch writes, keeping the
+// bind parameters of the widest statement well below the Postgres limit
+const batchChunkSize = 500
+
+// errBatchFailed rolls back the transaction of an atomic batch with failed items
+var errBatchFailed = errors.New("batch has failed items")
+
+// batchUpdateColumns are the columns UpdateBatch writes, which are the ones
+// UserService can change, with the types of their VALUES list entries
+var batchUpdateColumns = []struct {
+	name  string
+	cast  string
+	value func(user *entity.User) interface{}
+}{
+	{"email", "text", func(u *entity.User) interface{} { return u.Email }},
+	{"username", "text", func(u *entity.User) interface{} { return u.Username }},
+	{"username_skeleton", "text", func(u *entity.User) interface{} { return u.UsernameSkeleton }},
+	{"first_name", "text", func(u *entity.User) interface{} { return u.FirstName }},
+	{"last_name", "text", func(u *entity.User) interface{} { return u.LastName }},
+	{"family_name_kana", "text", func(u *entity.User) interface{} { return u.FamilyNameKana }},
+	{"given_name_kana", "text", func(u *entity.User) interface{} { return u.GivenNameKana }},
+	{"name_order", "text", func(u *entity.User) interface{} { return string(u.NameOrder) }},
+	{"locale", "text", func(u *entity.User) interface{} { return u.Locale }},
+	{"is_active", "boolean", func(u *entity.User) interface{} { return u.IsActive }},
+	{"is_admin", "boolean", func(u *entity.User) interface{} { return u.IsAdmin }},
+	{"valid_from", "timestamptz", func(u *entity.User) interface{} { return nullIfNil(u.ValidFrom) }},
+	{"valid_until", "timestamptz", func(u *entity.User) interface{} { return nullIfNil(u.ValidUntil) }},
+	{"attributes", "jsonb", func(u *entity.User) interface{} { return u.Attributes }},
+}
+
+// valuesFrom is the FROM clause of an UPDATE joining a VALUES list
+type valuesFrom struct {
+	expr clause.Expr
+}
+
+// Name implements clause.Interface
+func (valuesFrom) Name() string {
+	return "FROM"
+}
+
+// Build implements clause.Expression
+func (f valuesFrom) Build(builder clause.Builder) {
+	f.expr.Build(builder)
+}
+
+// MergeClause implements clause.Interface
+func (f valuesFrom) MergeClause(c *clause.Clause) {
+	c.Expression = f
+}
+
+// isUniqueViolation reports whether an error is a Postgres unique violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
+
+// writeBatch runs the write of a batch in a transaction. The write first runs for
+// all items at once in a savepoint; if that hits a unique violation, it is rolled
+// back and retried item by item, each in its own savepoint, to find the items that
+// conflict. Writes record other item failures in errs themselves; any other error
+// fails the whole batch.
+func writeBatch(ctx context.Context, db *gorm.DB, size int, atomic bool, write func(tx *gorm.DB, from, to int, errs []error) error) ([]error, error) {
+	errs := make([]error, size)
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Transaction(func(tx *gorm.DB) error {
+			return write(tx, 0, size, errs)
+		})
+		if err != nil && !isUniqueViolation(err) {
+			return err
+		}
+
+		if err != nil {
+			for i := range errs {
+				errs[i] = nil
+				err := tx.Transaction(func(tx *gorm.DB) error {
+					return write(tx, i, i+1, errs)
+				})
+				if err != nil && !isUniqueViolation(err) {
+					return err
+				}
+				if err != nil {
+					errs[i] = translateUniqueViolation(err)
+				}
+			}
+		}
+
+		if atomic && entity.FirstBatchError(errs) != nil {
+			return errBatchFailed
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchFailed) {
+		return nil, err
+	}
+	return errs, nil
+}
+
+// CreateBatch creates users with multi-row inserts and, within a tenant, makes
+// them members of that tenant
+func (r *userRepository) CreateBatch(ctx context.Context, users []*entity.User, atomic bool) ([]error, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	organizationID, inTenant := tenant.OrganizationIDFromContext(ctx)
+	return writeBatch(ctx, db, len(users), atomic, func(tx *gorm.DB, from, to int, errs []error) error {
+		if err := tx.CreateInBatches(users[from:to], batchChunkSize).Error; err != nil {
+			return fmt.Errorf("failed to create users: %w", err)
+		}
+
+		if inTenant {
+			memberships := make([]map[string]interface{}, 0, to-from)
+			for _, user := range users[from:to] {
+				memberships = append(memberships, map[string]interface{}{
+					"organization_id": organizationID,
+					"user_id":         user.ID,
+				})
+			}
+			if err := tx.Table(tenant.MembershipTable).CreateInBatches(memberships, batchChunkSize).Error; err != nil {
+				return fmt.Errorf("failed to add users to organization: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateBatch updates users that still have the versions they were read with.
+// Each chunk of users is one UPDATE ... FROM (VALUES ...) joining the users on
+// ID and version; the users it does not return have changed since.
+func (r *userRepository) UpdateBatch(ctx context.Context, users []*entity.User, atomic bool) ([]error, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	now := time.Now()
+	errs, err := writeBatch(ctx, db, len(users), atomic, func(tx *gorm.DB, from, to int, errs []error) error {
+		for start := from; start < to; start += batchChunkSize {
+			end := start + batchChunkSize
+			if end > to {
+				end = to
+			}
+			ids, err := r.updateUsers(ctx, tx, users[start:end], now)
+			if err != nil {
+				return fmt.Errorf("failed to update users: %w", err)
+			}
+
+			updated := make(map[uuid.UUID]bool, len(ids))
+			for _, id := range ids {
+				updated[id] = true
+			}
+			for i := start; i < end; i++ {
+				if !updated[users[i].ID] {
+					errs[i] = entity.ErrConcurrentModification
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Only a committed write moves the users to their next version
+	if !atomic || entity.FirstBatchError(errs) == nil {
+		for i, user := range users {
+			if errs[i] == nil {
+				user.Version++
+				user.UpdatedAt = now
+			}
+		}
+	}
+	return errs, nil
+}
+
+// updateUsers writes users with one UPDATE and returns the IDs of the updated users
+func (r *userRepository) updateUsers(ctx context.Context, tx *gorm.DB, users []*entity.User, now time.Time) ([]uuid.UUID, error) {
+	columns := []string{"id", "version"}
+	set := map[string]interface{}{
+		"version":    gorm.Expr("users.version + 1"),
+		"updated_at": now,
+	}
+	for _, c := range batchUpdateColumns {
+		columns = append(columns, c.name)
+		set[c.name] = gorm.Expr("v." + c.name)
+	}
+
+	rows := make([]string, len(users))
+	args := make([]interface{}, 0, len(users)*len(columns))
+	for i, user := range users {
+		placeholders := []string{"?::uuid", "?::bigint"}
+		args = append(args, user.ID, user.Version)
+		for _, c := range batchUpdateColumns {
+			placeholders = append(placeholders, "?::"+c.cast)
+			args = append(args, c.value(user))
+		}
+		rows[i] = "(" + strings.Join(placeholders, ", ") + ")"
+	}
+	from := valuesFrom{expr: clause.Expr{
+		SQL:  "(VALUES " + strings.Join(rows, ", ") + ") AS v(" + strings.Join(columns, ", ") + ")",
+		Vars: args,
+	}}
+
+	var updated []entity.User
+	if err := r.scoped(ctx, tx).
+		Model(&updated).
+		Clauses(from, clause.Returning{Columns: []clause.Column{{Table: "users", Name: "id"}}}).
+		Where("users.id = v.id AND users.version = v.version").
+		Updates(set).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(updated))
+	for i, user := range updated {
+		ids[i] = user.ID
+	}
+	return ids, nil
+}
+
+// DeleteBatch soft deletes users with one UPDATE. Users it does not delete are
+// missing, or have changed since they were read when an expected version is given.
+func (r *userRepository) DeleteBatch(ctx context.Context, deletions []entity.UserDeletion, atomic bool) ([]error, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var ids []uuid.UUID
+	var versioned [][]interface{}
+	for _, deletion := range deletions {
+		if deletion.ExpectedVersion == 0 {
+			ids = append(ids, deletion.ID)
+		} else {
+			versioned = append(versioned, []interface{}{deletion.ID, deletion.ExpectedVersion})
+		}
+	}
+	var conditions []string
+	var args []interface{}
+	if len(ids) > 0 {
+		conditions = append(conditions, "users.id IN ?")
+		args = append(args, ids)
+	}
+	if len(versioned) > 0 {
+		conditions = append(conditions, "(users.id, users.version) IN ?")
+		args = append(args, versioned)
+	}
+
+	errs := make([]error, len(deletions))
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var deleted []entity.User
+		if err := r.scoped(ctx, tx).
+			Clauses(clause.Returning{Columns: []clause.Column{{Table: "users", Name: "id"}}}).
+			Where(strings.Join(conditions, " OR "), args...).
+			Delete(&deleted).Error; err != nil {
+			return fmt.Errorf("failed to delete users: %w", err)
+		}
+
+		isDeleted := make(map[uuid.UUID]bool, len(deleted))
+		for _, user := range deleted {
+			isDeleted[user.ID] = true
+		}
+		var missing []uuid.UUID
+		for _, deletion := range deletions {
+			if !isDeleted[deletion.ID] && deletion.ExpectedVersion != 0 {
+				missing = append(missing, deletion.ID)
+			}
+		}
+
+		// Users with an expected version that still exist have changed since
+		exists := make(map[uuid.UUID]bool, len(missing))
+		if len(missing) > 0 {
+			var existing []uuid.UUID
+			if err := r.scoped(ctx, tx).Model(&entity.User{}).Where("id IN ?", missing).Pluck("id", &existing).Error; err != nil {
+				return fmt.Errorf("failed to check user existence: %w", err)
+			}
+			for _, id := range existing {
+				exists[id] = true
+			}
+		}
+
+		for i, deletion := range deletions {
+			switch {
+			case isDeleted[deletion.ID]:
+			case exists[deletion.ID]:
+				errs[i] = entity.ErrConcurrentModification
+			default:
+				errs[i] = entity.ErrUserNotFound
+			}
+		}
+		if atomic && entity.FirstBatchError(errs) != nil {
+			return errBatchFailed
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchFailed) {
+		return nil, err
+	}
+	return errs, nil
+}
@@ -0,0 +1,153 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestWriteBatch(t *testing.T) {
+	errWrite := errors.New("connection lost")
+	emailTaken := &pgconn.PgError{Code: uniqueViolationCode, ConstraintName: "idx_users_email_lower_global"}
+	otherTaken := &pgconn.PgError{Code: uniqueViolationCode, ConstraintName: "users_pkey"}
+
+	tests := []struct {
+		name   string
+		size   int
+		atomic bool
+		// conflicts are the unique violations of items, which fail any write including them
+		conflicts map[int]error
+		// failed are items the write records as failed itself
+		failed map[int]error
+		// broken is the error of every write, failing the batch
+		broken error
+
+		wantErr        error
+		wantItemErrs   []error
+		wantFields     map[int]string
+		wantStatements []string
+	}{
+		{
+			name:           "all written at once",
+			size:           3,
+			wantItemErrs:   []error{nil, nil, nil},
+			wantStatements: []string{"BEGIN", "SAVEPOINT", "COMMIT"},
+		},
+		{
+			name:         "conflict retried item by item",
+			size:         3,
+			conflicts:    map[int]error{1: emailTaken},
+			wantItemErrs: []error{nil, entity.ErrUserAlreadyExists, nil},
+			wantFields:   map[int]string{1: "email"},
+			wantStatements: []string{"BEGIN",
+				"SAVEPOINT", "ROLLBACK TO SAVEPOINT",
+				"SAVEPOINT", "SAVEPOINT", "ROLLBACK TO SAVEPOINT", "SAVEPOINT",
+				"COMMIT"},
+		},
+		{
+			name:         "conflict on unknown index",
+			size:         2,
+			conflicts:    map[int]error{0: otherTaken},
+			wantItemErrs: []error{entity.ErrUserAlreadyExists, nil},
+			wantStatements: []string{"BEGIN",
+				"SAVEPOINT", "ROLLBACK TO SAVEPOINT",
+				"SAVEPOINT", "ROLLBACK TO SAVEPOINT", "SAVEPOINT",
+				"COMMIT"},
+		},
+		{
+			name:           "items failed by the write",
+			size:           3,
+			failed:         map[int]error{2: entity.ErrConcurrentModification},
+			wantItemErrs:   []error{nil, nil, entity.ErrConcurrentModification},
+			wantStatements: []string{"BEGIN", "SAVEPOINT", "COMMIT"},
+		},
+		{
+			name:           "atomic batch with failed item rolls back",
+			size:           2,
+			atomic:         true,
+			failed:         map[int]error{0: entity.ErrConcurrentModification},
+			wantItemErrs:   []error{entity.ErrConcurrentModification, nil},
+			wantStatements: []string{"BEGIN", "SAVEPOINT", "ROLLBACK"},
+		},
+		{
+			name:         "atomic batch with conflict rolls back",
+			size:         2,
+			atomic:       true,
+			conflicts:    map[int]error{1: emailTaken},
+			wantItemErrs: []error{nil, entity.ErrUserAlreadyExists},
+			wantFields:   map[int]string{1: "email"},
+			wantStatements: []string{"BEGIN",
+				"SAVEPOINT", "ROLLBACK TO SAVEPOINT",
+				"SAVEPOINT", "SAVEPOINT", "ROLLBACK TO SAVEPOINT",
+				"ROLLBACK"},
+		},
+		{
+			name:           "atomic batch without failures commits",
+			size:           2,
+			atomic:         true,
+			wantItemErrs:   []error{nil, nil},
+			wantStatements: []string{"BEGIN", "SAVEPOINT", "COMMIT"},
+		},
+		{
+			name:           "other error fails the batch",
+			size:           2,
+			broken:         errWrite,
+			wantErr:        errWrite,
+			wantStatements: []string{"BEGIN", "SAVEPOINT", "ROLLBACK TO SAVEPOINT", "ROLLBACK"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t)
+
+			errs, err := writeBatch(context.Background(), db, tt.size, tt.atomic, func(tx *gorm.DB, from, to int, errs []error) error {
+				if tt.broken != nil {
+					return tt.broken
+				}
+				for i := from; i < to; i++ {
+					if conflict, ok := tt.conflicts[i]; ok {
+						return conflict
+					}
+				}
+				for i := from; i < to; i++ {
+					errs[i] = tt.failed[i]
+				}
+				return nil
+			})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("writeBatch() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("writeBatch() error = %v", err)
+			}
+
+			if tt.wantItemErrs != nil {
+				if len(errs) != len(tt.wantItemErrs) {
+					t.Fatalf("writeBatch() returned %d item errors, want %d", len(errs), len(tt.wantItemErrs))
+				}
+				for i, want := range tt.wantItemErrs {
+					if !errors.Is(errs[i], want) {
+						t.Errorf("item %d error = %v, want %v", i, errs[i], want)
+					}
+				}
+			}
+			for i, field := range tt.wantFields {
+				var violation *entity.FieldViolation
+				if !errors.As(errs[i], &violation) || violation.Field != field {
+					t.Errorf("item %d error = %v, want a violation of %s", i, errs[i], field)
+				}
+			}
+			if got := fake.statements(); !reflect.DeepEqual(got, tt.wantStatements) {
+				t.Errorf("statements = %q, want %q", got, tt.wantStatements)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/database"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+const (
+	// userChangeChannel is the channel the users trigger notifies on commit
+	userChangeChannel = "user_changes"
+
+	// userChangeRelistenDelay is how long the listener waits before reconnecting
+	userChangeRelistenDelay = 5 * time.Second
+)
+
+// userChangeHorizon selects the oldest transaction still running. Every change
+// made by an older transaction has either committed or rolled back.
+const userChangeHorizon = "SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint"
+
+// userChangeSubject is the user a change belongs to for tenant scoping. Merged
+// users lose their memberships to the user they were merged into, so their
+// deletion belongs to that user.
+const userChangeSubject = "COALESCE((user_changes.after_data->>'merged_into_id')::uuid, user_changes.user_id)"
+
+// userChangeSnapshots turns the snapshots of a change into rows of users, so that
+// user filters apply to them as they do to the table
+const userChangeSnapshots = "(VALUES (user_changes.before_data), (user_changes.after_data)) AS s(snapshot), " +
+	"jsonb_populate_record(NULL::users, s.snapshot) AS users"
+
+// userChangeRepository implements repository.UserChangeRepository. A single
+// connection listens for commit notifications on behalf of every subscriber
+// while there are any.
+type userChangeRepository struct {
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+	stopListen  context.CancelFunc
+}
+
+// NewUserChangeRepository creates a new instance of UserChangeRepository
+func NewUserChangeRepository() repository.UserChangeRepository {
+	return &userChangeRepository{subscribers: make(map[chan struct{}]struct{})}
+}
+
+// getDB gets the database connection, joining the transaction of the context if any
+func (r *userChangeRepository) getDB(ctx context.Context) (*gorm.DB, error) {
+	return database.Conn(ctx)
+}
+
+// horizon returns the ID of the oldest transaction still running
+func (r *userChangeRepository) horizon(ctx context.Context, db *gorm.DB) (int64, error) {
+	var horizon int64
+	if err := db.WithContext(ctx).Raw(userChangeHorizon).Scan(&horizon).Error; err != nil {
+		return 0, fmt.Errorf("failed to get transaction horizon: %w", err)
+	}
+	return horizon, nil
+}
+
+// CurrentPosition returns the position after every change committed so far
+func (r *userChangeRepository) CurrentPosition(ctx context.Context) (repository.UserChangePosition, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return repository.UserChangePosition{}, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	horizon, err := r.horizon(ctx, db)
+	if err != nil {
+		return repository.UserChangePosition{}, err
+	}
+	return repository.UserChangePosition{TxID: horizon}, nil
+}
+
+// ListAfter retrieves the changes after a position whose user matches the filter
+// before or after the change. Changes are only read up to the oldest running
+// transaction, whose changes and those of any later transaction may still commit.
+func (r *userChangeRepository) ListAfter(ctx context.Context, after repository.UserChangePosition, filter *repository.UserFilter, limit int) ([]*entity.UserChange, repository.UserChangePosition, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, after, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	// The horizon is taken before reading, so that every change below it is
+	// visible to the read
+	horizon, err := r.horizon(ctx, db)
+	if err != nil {
+		return nil, after, err
+	}
+
+	// Snapshots are matched like users of the table, soft deletion included
+	matching := db.Model(&entity.User{}).Table(userChangeSnapshots).
+		Select("1").
+		Where("s.snapshot IS NOT NULL")
+	matching, err = applyUserFilter(matching, filter)
+	if err != nil {
+		return nil, after, err
+	}
+
+	var changes []*entity.UserChange
+	if err := db.WithContext(ctx).
+		Scopes(tenant.ScopeUsers(ctx, userChangeSubject)).
+		Where("(user_changes.txid, user_changes.seq) > (?, ?)", after.TxID, after.Seq).
+		Where("user_changes.txid < ?", horizon).
+		Where("EXISTS (?)", matching).
+		Order("user_changes.txid, user_changes.seq").
+		Limit(limit).
+		Find(&changes).Error; err != nil {
+		return nil, after, fmt.Errorf("failed to list user changes: %w", err)
+	}
+
+	// A full page may be followed by more changes below the horizon
+	if len(changes) == limit {
+		last := changes[len(changes)-1]
+		return changes, repository.UserChangePosition{TxID: last.TxID, Seq: last.Seq}, nil
+	}
+	if horizon > after.TxID {
+		return changes, repository.UserChangePosition{TxID: horizon}, nil
+	}
+	return changes, after, nil
+}
+
+// PrunedPosition returns the position up to which changes have been pruned
+func (r *userChangeRepository) PrunedPosition(ctx context.Context) (repository.UserChangePosition, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return repository.UserChangePosition{}, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var positions []repository.UserChangePosition
+	if err := db.WithContext(ctx).Raw("SELECT txid AS tx_id, seq FROM user_changes_pruned").Scan(&positions).Error; err != nil {
+		return repository.UserChangePosition{}, fmt.Errorf("failed to get pruned position: %w", err)
+	}
+	if len(positions) == 0 {
+		return repository.UserChangePosition{}, nil
+	}
+	return positions[0], nil
+}
+
+// Prune deletes the changes up to the latest one made before a time by a
+// transaction that has ended, and records the position pruned up to
+func (r *userChangeRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var pruned int64
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		horizon, err := r.horizon(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		var positions []repository.UserChangePosition
+		if err := tx.Raw(`SELECT txid AS tx_id, seq FROM user_changes
+			WHERE changed_at < ? AND txid < ?
+			ORDER BY txid DESC, seq DESC LIMIT 1`, before, horizon).Scan(&positions).Error; err != nil {
+			return fmt.Errorf("failed to find changes to prune: %w", err)
+		}
+		if len(positions) == 0 {
+			return nil
+		}
+		last := positions[0]
+
+		result := tx.Exec("DELETE FROM user_changes WHERE (txid, seq) <= (?, ?)", last.TxID, last.Seq)
+		if result.Error != nil {
+			return fmt.Errorf("failed to prune user changes: %w", result.Error)
+		}
+		pruned = result.RowsAffected
+
+		// Concurrent pruners only ever move the position forward
+		if err := tx.Exec(`INSERT INTO user_changes_pruned (id, txid, seq) VALUES (true, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET txid = EXCLUDED.txid, seq = EXCLUDED.seq
+			WHERE (user_changes_pruned.txid, user_changes_pruned.seq) < (EXCLUDED.txid, EXCLUDED.seq)`,
+			last.TxID, last.Seq).Error; err != nil {
+			return fmt.Errorf("failed to record pruned position: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return pruned, nil
+}
+
+// Subscribe returns a channel notified when changes may have been committed,
+// starting the listener for the first subscriber
+func (r *userChangeRepository) Subscribe(ctx context.Context) <-chan struct{} {
+	notify := make(chan struct{}, 1)
+
+	r.mu.Lock()
+	r.subscribers[notify] = struct{}{}
+	if r.stopListen == nil {
+		listenCtx, cancel := context.WithCancel(context.Background())
+		r.stopListen = cancel
+		go r.listen(listenCtx)
+	}
+	r.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.subscribers, notify)
+		if len(r.subscribers) == 0 && r.stopListen != nil {
+			r.stopListen()
+			r.stopListen = nil
+		}
+	}()
+	return notify
+}
+
+// broadcast notifies every subscriber without blocking; a subscriber that has
+// not received the previous notification yet still has one pending
+func (r *userChangeRepository) broadcast() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for notify := range r.subscribers {
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// listen relays commit notifications to subscribers until ctx is done,
+// reconnecting after failures
+func (r *userChangeRepository) listen(ctx context.Context) {
+	for {
+		err := r.listenOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Failed to listen for user changes: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(userChangeRelistenDelay):
+		}
+	}
+}
+
+// listenOnce holds a pooled connection listening on the user change channel
+func (r *userChangeRepository) listenOnce(ctx context.Context) error {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unsupported database driver %T", driverConn)
+		}
+		pgxConn := stdlibConn.Conn()
+
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+userChangeChannel); err != nil {
+			return fmt.Errorf("failed to listen: %w", err)
+		}
+		// The connection goes back to the pool, which must not hand out a listening one.
+		// Connections broken by the cancelled wait are discarded by the pool instead.
+		defer func() {
+			unlistenCtx, cancel := context.WithTimeout(context.Background(), userChangeRelistenDelay)
+			defer cancel()
+			_, _ = pgxConn.Exec(unlistenCtx, "UNLISTEN "+userChangeChannel)
+		}()
+		// Wake subscribers for changes committed while no one was listening
+		r.broadcast()
+
+		for {
+			if _, err := pgxConn.WaitForNotification(ctx); err != nil {
+				return fmt.Errorf("failed to wait for notification: %w", err)
+			}
+			r.broadcast()
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package persistence
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/shared/filter"
+)
+
+// userStatusConditions are the conditions status values in filter expressions compile to
+var userStatusConditions = map[string]string{
+	"ACTIVE":   "is_active = TRUE",
+	"INACTIVE": "is_active = FALSE",
+}
+
+// compileUserFilter compiles a checked filter expression into a parameterised SQL
+// condition. Field names are looked up in the user filter schema before use,
+// so only User's column names reach the SQL; values are always bound.
+func compileUserFilter(expr filter.Expr) (string, []interface{}, error) {
+	var sql strings.Builder
+	var args []interface{}
+	if err := compileUserFilterExpr(&sql, &args, expr); err != nil {
+		return "", nil, err
+	}
+	return sql.String(), args, nil
+}
+
+func compileUserFilterExpr(sql *strings.Builder, args *[]interface{}, expr filter.Expr) error {
+	switch e := expr.(type) {
+	case *filter.And:
+		return compileUserFilterList(sql, args, e.Operands, " AND ")
+	case *filter.Or:
+		return compileUserFilterList(sql, args, e.Operands, " OR ")
+	case *filter.Not:
+		sql.WriteString("NOT ")
+		return compileUserFilterList(sql, args, []filter.Expr{e.Operand}, "")
+	case *filter.Restriction:
+		return compileUserRestriction(sql, args, e)
+	default:
+		return fmt.Errorf("unsupported filter expression %T", expr)
+	}
+}
+
+// compileUserFilterList compiles parenthesized operands joined by a logical operator
+func compileUserFilterList(sql *strings.Builder, args *[]interface{}, operands []filter.Expr, operator string) error {
+	sql.WriteString("(")
+	for i, operand := range operands {
+		if i > 0 {
+			sql.WriteString(operator)
+		}
+		if err := compileUserFilterExpr(sql, args, operand); err != nil {
+			return err
+		}
+	}
+	sql.WriteString(")")
+	return nil
+}
+
+// compileUserRestriction compiles a field comparison. String equality and ":"
+// ignore case, like the other user filters.
+func compileUserRestriction(sql *strings.Builder, args *[]interface{}, r *filter.Restriction) error {
+	field, ok := entity.UserFilterSchema()[r.Field]
+	if !ok {
+		return fmt.Errorf("unknown filter field %q", r.Field)
+	}
+	column := r.Field
+
+	switch {
+	case r.Op == filter.OpNone:
+		sql.WriteString(column + " = TRUE")
+	case r.Arg.Any:
+		sql.WriteString(column + " IS NOT NULL")
+	case field.Type == filter.TypeEnum:
+		condition, ok := userStatusConditions[fmt.Sprint(r.Arg.Value)]
+		if r.Field != entity.UserFilterStatusField || !ok {
+			return fmt.Errorf("unknown value %v of filter field %q", r.Arg.Value, r.Field)
+		}
+		if r.Op == filter.OpNotEqual {
+			sql.WriteString("NOT ")
+		}
+		sql.WriteString("(" + condition + ")")
+	case field.Type == filter.TypeString && (r.Op == filter.OpEqual || r.Op == filter.OpNotEqual || r.Op == filter.OpHas):
+		value := strings.ToLower(fmt.Sprint(r.Arg.Value))
+		if r.Op == filter.OpNotEqual {
+			sql.WriteString("NOT ")
+		}
+		switch {
+		case r.Op == filter.OpHas:
+			sql.WriteString("lower(" + column + ") LIKE ?")
+			*args = append(*args, "%"+escapeLike(value)+"%")
+		case r.Arg.Prefix || r.Arg.Suffix:
+			pattern := escapeLike(value)
+			if r.Arg.Suffix {
+				pattern = "%" + pattern
+			}
+			if r.Arg.Prefix {
+				pattern += "%"
+			}
+			sql.WriteString("lower(" + column + ") LIKE ?")
+			*args = append(*args, pattern)
+		default:
+			sql.WriteString("lower(" + column + ") = ?")
+			*args = append(*args, value)
+		}
+	default:
+		sql.WriteString(column + " " + string(r.Op) + " ?")
+		*args = append(*args, r.Arg.Value)
+	}
+	return nil
+}
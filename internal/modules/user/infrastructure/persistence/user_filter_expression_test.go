@@ -0,0 +1,161 @@
+package persistence
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/shared/filter"
+)
+
+func TestCompileUserFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "string equality ignores case",
+			input:    `email = "Taro@Example.com"`,
+			wantSQL:  "lower(email) = ?",
+			wantArgs: []interface{}{"taro@example.com"},
+		},
+		{
+			name:     "string inequality",
+			input:    "username != taro",
+			wantSQL:  "NOT lower(username) = ?",
+			wantArgs: []interface{}{"taro"},
+		},
+		{
+			name:     "has matches substrings literally",
+			input:    `email : "50%_off"`,
+			wantSQL:  "lower(email) LIKE ?",
+			wantArgs: []interface{}{`%50\%\_off%`},
+		},
+		{
+			name:     "prefix",
+			input:    `username = "adm*"`,
+			wantSQL:  "lower(username) LIKE ?",
+			wantArgs: []interface{}{"adm%"},
+		},
+		{
+			name:     "suffix",
+			input:    `email = "*@Example.com"`,
+			wantSQL:  "lower(email) LIKE ?",
+			wantArgs: []interface{}{"%@example.com"},
+		},
+		{
+			name:     "negated infix",
+			input:    `email != "*test*"`,
+			wantSQL:  "NOT lower(email) LIKE ?",
+			wantArgs: []interface{}{"%test%"},
+		},
+		{
+			name:     "string ordering is case sensitive",
+			input:    "last_name >= M",
+			wantSQL:  "last_name >= ?",
+			wantArgs: []interface{}{"M"},
+		},
+		{
+			name:    "bare bool",
+			input:   "is_admin",
+			wantSQL: "is_admin = TRUE",
+		},
+		{
+			name:     "bool comparison",
+			input:    "is_active = false",
+			wantSQL:  "is_active = ?",
+			wantArgs: []interface{}{false},
+		},
+		{
+			name:     "int comparison",
+			input:    "version > 2",
+			wantSQL:  "version > ?",
+			wantArgs: []interface{}{int64(2)},
+		},
+		{
+			name:     "timestamp comparison",
+			input:    `created_at < "2026-01-01T00:00:00Z"`,
+			wantSQL:  "created_at < ?",
+			wantArgs: []interface{}{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:    "presence",
+			input:   "last_login_at:*",
+			wantSQL: "last_login_at IS NOT NULL",
+		},
+		{
+			name:    "status",
+			input:   "status = ACTIVE",
+			wantSQL: "(is_active = TRUE)",
+		},
+		{
+			name:    "negated status",
+			input:   "status != INACTIVE",
+			wantSQL: "NOT (is_active = FALSE)",
+		},
+		{
+			name:     "and",
+			input:    "is_admin version > 1",
+			wantSQL:  "(is_admin = TRUE AND version > ?)",
+			wantArgs: []interface{}{int64(1)},
+		},
+		{
+			name:     "or within and",
+			input:    "is_admin AND (version = 1 OR username : bot)",
+			wantSQL:  "(is_admin = TRUE AND (version = ? OR lower(username) LIKE ?))",
+			wantArgs: []interface{}{int64(1), "%bot%"},
+		},
+		{
+			name:     "not",
+			input:    "NOT (is_admin OR version = 1)",
+			wantSQL:  "NOT ((is_admin = TRUE OR version = ?))",
+			wantArgs: []interface{}{int64(1)},
+		},
+		{
+			name:     "minus",
+			input:    "-username : bot",
+			wantSQL:  "NOT (lower(username) LIKE ?)",
+			wantArgs: []interface{}{"%bot%"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := filter.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if err := entity.UserFilterSchema().Check(expr); err != nil {
+				t.Fatalf("Check(%q) error = %v", tt.input, err)
+			}
+
+			sql, args, err := compileUserFilter(expr)
+			if err != nil {
+				t.Fatalf("compileUserFilter(%q) error = %v", tt.input, err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("compileUserFilter(%q) =\n\t%s\nwant\n\t%s", tt.input, sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("compileUserFilter(%q) args = %#v, want %#v", tt.input, args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestUserFilterSchemaExcludesUnfilterableFields(t *testing.T) {
+	for _, field := range []string{"password", "attributes", "deleted_at", "merged_into_id"} {
+		t.Run(field, func(t *testing.T) {
+			expr, err := filter.Parse(field + ":*")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if err := entity.UserFilterSchema().Check(expr); err == nil {
+				t.Fatalf("Check(%q) accepted an unfilterable field", field)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"gorm.io/gorm"
+)
+
+// errDryRun rolls back the transaction of a dry-run merge
+var errDryRun = errors.New("dry run")
+
+// membershipRoleRank orders organization membership roles from least to most
+// privileged, matching organization.MembershipRole.Rank
+const membershipRoleRank = `CASE %s WHEN 'owner' THEN 3 WHEN 'admin' THEN 2 ELSE 1 END`
+
+// Merge moves everything linked to the source user onto the target user and
+// soft-deletes the source, or rolls all of it back for a dry run
+func (r *userRepository) Merge(ctx context.Context, merge *entity.UserMerge, dryRun bool) (*entity.MergedReferences, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	source, target := merge.Source, merge.Target
+	targetVersion := target.Version
+	var moved entity.MergedReferences
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Release the source's email and username first so the target can take them
+		result := tx.Scopes(tenant.ScopeUsers(ctx, "users.id")).
+			Model(&entity.User{}).
+			Where("id = ? AND version = ?", source.ID, source.Version).
+			Updates(map[string]interface{}{
+				"deleted_at":     time.Now(),
+				"merged_into_id": target.ID,
+				"version":        gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete source user: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrConcurrentModification
+		}
+
+		expectedVersion := target.Version
+		target.Version = expectedVersion + 1
+		result = tx.Scopes(tenant.ScopeUsers(ctx, "users.id")).
+			Model(target).
+			Where("version = ?", expectedVersion).
+			Select("*").
+			Omit(unownedUserColumns...).
+			Updates(target)
+		if result.Error != nil {
+			target.Version = expectedVersion
+			return fmt.Errorf("failed to update target user: %w", translateUniqueViolation(result.Error))
+		}
+		if result.RowsAffected == 0 {
+			target.Version = expectedVersion
+			return entity.ErrConcurrentModification
+		}
+
+		// Organizations both users belong to keep the more privileged role
+		if err := tx.Exec(`UPDATE `+tenant.MembershipTable+` t SET role = s.role
+			FROM `+tenant.MembershipTable+` s
+			WHERE t.user_id = ? AND s.user_id = ? AND s.organization_id = t.organization_id
+				AND `+fmt.Sprintf(membershipRoleRank, "s.role")+` > `+fmt.Sprintf(membershipRoleRank, "t.role"),
+			target.ID, source.ID).Error; err != nil {
+			return fmt.Errorf("failed to merge organization roles: %w", err)
+		}
+		if err := tx.Exec(`DELETE FROM `+tenant.MembershipTable+` s
+			WHERE s.user_id = ? AND EXISTS (
+				SELECT 1 FROM `+tenant.MembershipTable+` t WHERE t.user_id = ? AND t.organization_id = s.organization_id)`,
+			source.ID, target.ID).Error; err != nil {
+			return fmt.Errorf("failed to merge organization memberships: %w", err)
+		}
+		result = tx.Table(tenant.MembershipTable).Where("user_id = ?", source.ID).Update("user_id", target.ID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to move organization memberships: %w", result.Error)
+		}
+		moved.OrganizationMemberships = result.RowsAffected
+
+		// Group roles follow group membership, so moving memberships moves the roles
+		if err := tx.Exec(`DELETE FROM group_members s
+			WHERE s.member_type = 'user' AND s.member_id = ? AND EXISTS (
+				SELECT 1 FROM group_members t
+				WHERE t.member_type = 'user' AND t.member_id = ? AND t.group_id = s.group_id)`,
+			source.ID, target.ID).Error; err != nil {
+			return fmt.Errorf("failed to merge group memberships: %w", err)
+		}
+		result = tx.Table("group_members").
+			Where("member_type = 'user' AND member_id = ?", source.ID).
+			Update("member_id", target.ID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to move group memberships: %w", result.Error)
+		}
+		moved.GroupMemberships = result.RowsAffected
+
+		result = tx.Model(&entity.LoginEvent{}).Where("user_id = ?", source.ID).Update("user_id", target.ID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to move login events: %w", result.Error)
+		}
+		moved.LoginEvents = result.RowsAffected
+
+		result = tx.Model(&entity.Invitation{}).Where("accepted_user_id = ?", source.ID).Update("accepted_user_id", target.ID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to move invitations: %w", result.Error)
+		}
+		moved.Invitations = result.RowsAffected
+
+		// The target's own preferences win; the source's are only kept if it has none
+		result = tx.Exec(`UPDATE user_preferences SET user_id = ?
+			WHERE user_id = ? AND NOT EXISTS (SELECT 1 FROM user_preferences WHERE user_id = ?)`,
+			target.ID, source.ID, target.ID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to move preferences: %w", result.Error)
+		}
+		moved.Preferences = result.RowsAffected > 0
+		if err := tx.Exec(`DELETE FROM user_preferences WHERE user_id = ?`, source.ID).Error; err != nil {
+			return fmt.Errorf("failed to delete source preferences: %w", err)
+		}
+
+		if merge.Audit != nil {
+			if err := tx.Create(merge.Audit).Error; err != nil {
+				return fmt.Errorf("failed to record audit event: %w", err)
+			}
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		// Nothing was written, so the preview keeps the target's current etag
+		target.Version = targetVersion
+		return &moved, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &moved, nil
+}
@@ -2,16 +2,24 @@ package persistence
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
-	"github.com/gigi434/sample-grpc-server/internal/config"
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/gigi434/sample-grpc-server/internal/shared/database"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
+// uniqueViolationCode is the Postgres SQLSTATE for unique_violation
+const uniqueViolationCode = "23505"
+
 // userRepository implements repository.UserRepository
 type userRepository struct {
 	// We don't store the DB connection here, we get it from config singleton
@@ -22,33 +30,82 @@ func NewUserRepository() repository.UserRepository {
 	return &userRepository{}
 }
 
-// getDB gets the database connection from the singleton
-func (r *userRepository) getDB() (*gorm.DB, error) {
-	return config.GetDB()
+// uniqueFields maps the unique indexes on users to the field each one protects
+var uniqueFields = map[string]string{
+	"idx_users_email_lower_global":             "email",
+	"idx_users_email_lower_organization":       "email",
+	"idx_users_username_skeleton_global":       "username",
+	"idx_users_username_skeleton_organization": "username",
+}
+
+// translateUniqueViolation converts a unique violation on one of the users indexes
+// into entity.ErrUserAlreadyExists, as a field violation when the field is known
+func translateUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolationCode {
+		return err
+	}
+	if field, ok := uniqueFields[pgErr.ConstraintName]; ok {
+		return &entity.FieldViolation{
+			Field:       field,
+			Description: field + " already in use",
+			Err:         entity.ErrUserAlreadyExists,
+		}
+	}
+	if key, ok := database.AttributeIndexKey(pgErr.ConstraintName); ok {
+		return &entity.FieldViolation{
+			Field:       "attributes." + key,
+			Description: key + " already in use",
+			Err:         entity.ErrUserAlreadyExists,
+		}
+	}
+	return fmt.Errorf("%w: %s", entity.ErrUserAlreadyExists, pgErr.ConstraintName)
+}
+
+// getDB gets the database connection, joining the transaction of the context if any
+func (r *userRepository) getDB(ctx context.Context) (*gorm.DB, error) {
+	return database.Conn(ctx)
 }
 
-// Create creates a new user
+// scoped returns a query restricted to users of the current tenant
+func (r *userRepository) scoped(ctx context.Context, db *gorm.DB) *gorm.DB {
+	return db.WithContext(ctx).Scopes(tenant.ScopeUsers(ctx, "users.id"))
+}
+
+// Create creates a new user and, within a tenant, makes it a member of that tenant
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
-	db, err := r.getDB()
+	db, err := r.getDB(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get database connection: %w", err)
 	}
 
-	if err := db.WithContext(ctx).Create(user).Error; err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
-	}
-	return nil
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return fmt.Errorf("failed to create user: %w", translateUniqueViolation(err))
+		}
+
+		if organizationID, ok := tenant.OrganizationIDFromContext(ctx); ok {
+			membership := map[string]interface{}{
+				"organization_id": organizationID,
+				"user_id":         user.ID,
+			}
+			if err := tx.Table(tenant.MembershipTable).Create(membership).Error; err != nil {
+				return fmt.Errorf("failed to add user to organization: %w", err)
+			}
+		}
+		return nil
+	})
 }
 
 // GetByID retrieves a user by ID
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
-	db, err := r.getDB()
+	db, err := r.getDB(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database connection: %w", err)
 	}
 
 	var user entity.User
-	if err := db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+	if err := r.scoped(ctx, db).First(&user, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, entity.ErrUserNotFound
 		}
@@ -57,15 +114,33 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Use
 	return &user, nil
 }
 
-// GetByEmail retrieves a user by email
+// GetByIDs retrieves the users with the given IDs with a single WHERE id IN (...) query
+func (r *userRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var users []*entity.User
+	if err := r.scoped(ctx, db).Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to get users by IDs: %w", err)
+	}
+	return users, nil
+}
+
+// GetByEmail retrieves a user by email, ignoring case
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
-	db, err := r.getDB()
+	db, err := r.getDB(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database connection: %w", err)
 	}
 
 	var user entity.User
-	if err := db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+	if err := r.scoped(ctx, db).Where("lower(email) = lower(?)", email).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, entity.ErrUserNotFound
 		}
@@ -74,15 +149,15 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.
 	return &user, nil
 }
 
-// GetByUsername retrieves a user by username
+// GetByUsername retrieves a user by username, ignoring case
 func (r *userRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
-	db, err := r.getDB()
+	db, err := r.getDB(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database connection: %w", err)
 	}
 
 	var user entity.User
-	if err := db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
+	if err := r.scoped(ctx, db).Where("lower(username) = lower(?)", username).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, entity.ErrUserNotFound
 		}
@@ -91,45 +166,113 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*e
 	return &user, nil
 }
 
-// Update updates an existing user
+// unownedUserColumns are the users columns Update does not write: besides the
+// immutable ones, sign-ins, deletion and merging set theirs through their own
+// statements, some without bumping the version, so a full-row update would undo them
+var unownedUserColumns = []string{"id", "created_at", "last_login_at", "deleted_at", "merged_into_id"}
+
+// Update updates an existing user if it still has the version it was read with.
+// This is a conditional UPDATE ... WHERE version = ?, so concurrent writers cannot
+// silently overwrite each other.
 func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
-	db, err := r.getDB()
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	expectedVersion := user.Version
+	user.Version = expectedVersion + 1
+
+	result := r.scoped(ctx, db).
+		Model(user).
+		Where("version = ?", expectedVersion).
+		Select("*").
+		Omit(unownedUserColumns...).
+		Updates(user)
+	if result.Error != nil {
+		user.Version = expectedVersion
+		return fmt.Errorf("failed to update user: %w", translateUniqueViolation(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		user.Version = expectedVersion
+		return entity.ErrConcurrentModification
+	}
+	return nil
+}
+
+// UpdateLastLogin records a successful sign-in without changing the user's version
+func (r *userRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	db, err := r.getDB(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get database connection: %w", err)
 	}
 
-	if err := db.WithContext(ctx).Save(user).Error; err != nil {
-		return fmt.Errorf("failed to update user: %w", err)
+	// UpdateColumn skips hooks and updated_at so the user's etag stays valid
+	if err := db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).UpdateColumn("last_login_at", at).Error; err != nil {
+		return fmt.Errorf("failed to update last login: %w", err)
 	}
 	return nil
 }
 
-// Delete soft deletes a user
-func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	db, err := r.getDB()
+// Delete soft deletes a user, optionally only if it still has the expected version
+func (r *userRepository) Delete(ctx context.Context, id uuid.UUID, expectedVersion int64) error {
+	db, err := r.getDB(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get database connection: %w", err)
 	}
 
-	result := db.WithContext(ctx).Delete(&entity.User{}, "id = ?", id)
+	query := r.scoped(ctx, db)
+	if expectedVersion != 0 {
+		query = query.Where("version = ?", expectedVersion)
+	}
+
+	result := query.Delete(&entity.User{}, "id = ?", id)
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete user: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
+		if expectedVersion != 0 {
+			exists, err := r.Exists(ctx, id)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return entity.ErrConcurrentModification
+			}
+		}
 		return entity.ErrUserNotFound
 	}
 	return nil
 }
 
+// ListExpired retrieves active users whose validity window ended at or before now, earliest expiry first
+func (r *userRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*entity.User, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var users []*entity.User
+	if err := r.scoped(ctx, db).
+		Where("is_active = ? AND valid_until <= ?", true, now).
+		Order("valid_until ASC").
+		Order("id ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to list expired users: %w", err)
+	}
+	return users, nil
+}
+
 // List retrieves users with pagination
 func (r *userRepository) List(ctx context.Context, offset, limit int) ([]*entity.User, error) {
-	db, err := r.getDB()
+	db, err := r.getDB(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database connection: %w", err)
 	}
 
 	var users []*entity.User
-	if err := db.WithContext(ctx).
+	if err := r.scoped(ctx, db).
 		Offset(offset).
 		Limit(limit).
 		Find(&users).Error; err != nil {
@@ -140,13 +283,13 @@ func (r *userRepository) List(ctx context.Context, offset, limit int) ([]*entity
 
 // Count returns the total number of users
 func (r *userRepository) Count(ctx context.Context) (int64, error) {
-	db, err := r.getDB()
+	db, err := r.getDB(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get database connection: %w", err)
 	}
 
 	var count int64
-	if err := db.WithContext(ctx).Model(&entity.User{}).Count(&count).Error; err != nil {
+	if err := r.scoped(ctx, db).Model(&entity.User{}).Count(&count).Error; err != nil {
 		return 0, fmt.Errorf("failed to count users: %w", err)
 	}
 	return count, nil
@@ -154,13 +297,13 @@ func (r *userRepository) Count(ctx context.Context) (int64, error) {
 
 // Exists checks if a user exists by ID
 func (r *userRepository) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
-	db, err := r.getDB()
+	db, err := r.getDB(ctx)
 	if err != nil {
 		return false, fmt.Errorf("failed to get database connection: %w", err)
 	}
 
 	var count int64
-	if err := db.WithContext(ctx).
+	if err := r.scoped(ctx, db).
 		Model(&entity.User{}).
 		Where("id = ?", id).
 		Count(&count).Error; err != nil {
@@ -169,34 +312,34 @@ func (r *userRepository) Exists(ctx context.Context, id uuid.UUID) (bool, error)
 	return count > 0, nil
 }
 
-// ExistsByEmail checks if a user exists by email
+// ExistsByEmail checks if a user exists by email, ignoring case
 func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
-	db, err := r.getDB()
+	db, err := r.getDB(ctx)
 	if err != nil {
 		return false, fmt.Errorf("failed to get database connection: %w", err)
 	}
 
 	var count int64
-	if err := db.WithContext(ctx).
+	if err := r.scoped(ctx, db).
 		Model(&entity.User{}).
-		Where("email = ?", email).
+		Where("lower(email) = lower(?)", email).
 		Count(&count).Error; err != nil {
 		return false, fmt.Errorf("failed to check email existence: %w", err)
 	}
 	return count > 0, nil
 }
 
-// ExistsByUsername checks if a user exists by username
+// ExistsByUsername checks if a user exists by username, ignoring case
 func (r *userRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
-	db, err := r.getDB()
+	db, err := r.getDB(ctx)
 	if err != nil {
 		return false, fmt.Errorf("failed to get database connection: %w", err)
 	}
 
 	var count int64
-	if err := db.WithContext(ctx).
+	if err := r.scoped(ctx, db).
 		Model(&entity.User{}).
-		Where("username = ?", username).
+		Where("lower(username) = lower(?)", username).
 		Count(&count).Error; err != nil {
 		return false, fmt.Errorf("failed to check username existence: %w", err)
 	}
@@ -205,41 +348,35 @@ func (r *userRepository) ExistsByUsername(ctx context.Context, username string)
 
 // ListWithOptions retrieves users with advanced filtering and sorting
 func (r *userRepository) ListWithOptions(ctx context.Context, opts *repository.ListOptions) ([]*entity.User, error) {
-	db, err := r.getDB()
+	db, err := r.getDB(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database connection: %w", err)
 	}
 
-	query := db.WithContext(ctx).Model(&entity.User{})
+	query := r.scoped(ctx, db).Model(&entity.User{})
 
 	// Apply filters
-	if opts.Filter != nil {
-		if opts.Filter.Email != nil {
-			query = query.Where("email LIKE ?", "%"+*opts.Filter.Email+"%")
-		}
-		if opts.Filter.Username != nil {
-			query = query.Where("username LIKE ?", "%"+*opts.Filter.Username+"%")
-		}
-		if opts.Filter.IsActive != nil {
-			query = query.Where("is_active = ?", *opts.Filter.IsActive)
-		}
-		if opts.Filter.IsAdmin != nil {
-			query = query.Where("is_admin = ?", *opts.Filter.IsAdmin)
-		}
+	query, err = applyUserFilter(query, opts.Filter)
+	if err != nil {
+		return nil, err
 	}
 
-	// Apply sorting
-	if opts.Sort != nil {
-		order := fmt.Sprintf("%s %s", opts.Sort.Field, opts.Sort.Order)
-		query = query.Order(order)
-	} else {
-		query = query.Order("created_at DESC")
+	// Apply sorting; keyset pagination continues after the cursor
+	keys, err := keysetSortKeys(opts.Sort, opts.After)
+	if err != nil {
+		return nil, err
+	}
+	if opts.After != nil {
+		query = applyKeyset(query, keys)
 	}
+	query = applyKeysetOrder(query, keys)
 
 	// Apply pagination
+	if opts.After == nil {
+		query = query.Offset(opts.Offset)
+	}
 	var users []*entity.User
 	if err := query.
-		Offset(opts.Offset).
 		Limit(opts.Limit).
 		Find(&users).Error; err != nil {
 		return nil, fmt.Errorf("failed to list users with options: %w", err)
@@ -250,27 +387,17 @@ func (r *userRepository) ListWithOptions(ctx context.Context, opts *repository.L
 
 // CountWithFilter returns the count of users matching the filter
 func (r *userRepository) CountWithFilter(ctx context.Context, filter *repository.UserFilter) (int64, error) {
-	db, err := r.getDB()
+	db, err := r.getDB(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get database connection: %w", err)
 	}
 
-	query := db.WithContext(ctx).Model(&entity.User{})
+	query := r.scoped(ctx, db).Model(&entity.User{})
 
 	// Apply filters
-	if filter != nil {
-		if filter.Email != nil {
-			query = query.Where("email LIKE ?", "%"+*filter.Email+"%")
-		}
-		if filter.Username != nil {
-			query = query.Where("username LIKE ?", "%"+*filter.Username+"%")
-		}
-		if filter.IsActive != nil {
-			query = query.Where("is_active = ?", *filter.IsActive)
-		}
-		if filter.IsAdmin != nil {
-			query = query.Where("is_admin = ?", *filter.IsAdmin)
-		}
+	query, err = applyUserFilter(query, filter)
+	if err != nil {
+		return 0, err
 	}
 
 	var count int64
@@ -280,3 +407,301 @@ func (r *userRepository) CountWithFilter(ctx context.Context, filter *repository
 
 	return count, nil
 }
+
+// applyUserFilter restricts the query to users matching the filter
+func applyUserFilter(query *gorm.DB, filter *repository.UserFilter) (*gorm.DB, error) {
+	if filter == nil {
+		return query, nil
+	}
+
+	if filter.Email != nil {
+		query = applyStringFilter(query, "email", *filter.Email)
+	}
+	if filter.Username != nil {
+		query = applyStringFilter(query, "username", *filter.Username)
+	}
+	if filter.FirstName != nil {
+		query = applyStringFilter(query, "first_name", *filter.FirstName)
+	}
+	if filter.LastName != nil {
+		query = applyStringFilter(query, "last_name", *filter.LastName)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+	if filter.IsAdmin != nil {
+		query = query.Where("is_admin = ?", *filter.IsAdmin)
+	}
+	if filter.Status != nil {
+		switch *filter.Status {
+		case entity.UserStatusActive:
+			query = query.Where("is_active = ?", true)
+		case entity.UserStatusInactive:
+			query = query.Where("is_active = ?", false)
+		default:
+			return nil, fmt.Errorf("unknown user status %q", *filter.Status)
+		}
+	}
+	if len(filter.IDs) > 0 {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	if len(filter.ExcludeIDs) > 0 {
+		query = query.Where("id NOT IN ?", filter.ExcludeIDs)
+	}
+	if filter.CreatedAt != nil {
+		query = applyTimeRange(query, "created_at", *filter.CreatedAt)
+	}
+	if filter.UpdatedAt != nil {
+		query = applyTimeRange(query, "updated_at", *filter.UpdatedAt)
+	}
+	if filter.Reading != nil {
+		query = applyReadingFilter(query, *filter.Reading)
+	}
+	if filter.ExpiringBefore != nil {
+		query = applyExpiringFilter(query, *filter.ExpiringBefore)
+	}
+	if len(filter.Attributes) > 0 {
+		containment, err := json.Marshal(filter.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode attribute filter: %w", err)
+		}
+		query = query.Where("attributes @> ?::jsonb", string(containment))
+	}
+	if filter.Expression != nil {
+		condition, args, err := compileUserFilter(filter.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile filter expression: %w", err)
+		}
+		query = query.Where(condition, args...)
+	}
+	return query, nil
+}
+
+// applyStringFilter matches a column case-insensitively. Exact matches use the
+// lower(column) indexes, and prefix matches on email and username the
+// text_pattern_ops indexes; contains matches scan.
+func applyStringFilter(query *gorm.DB, column string, filter repository.StringFilter) *gorm.DB {
+	value := strings.ToLower(filter.Value)
+	switch filter.Match {
+	case repository.StringMatchExact:
+		return query.Where("lower("+column+") = ?", value)
+	case repository.StringMatchPrefix:
+		return query.Where("lower("+column+") LIKE ?", escapeLike(value)+"%")
+	default:
+		return query.Where("lower("+column+") LIKE ?", "%"+escapeLike(value)+"%")
+	}
+}
+
+// applyTimeRange matches a timestamp column within the range
+func applyTimeRange(query *gorm.DB, column string, timeRange repository.TimeRange) *gorm.DB {
+	if timeRange.From != nil {
+		query = query.Where(column+" >= ?", *timeRange.From)
+	}
+	if timeRange.To != nil {
+		query = query.Where(column+" < ?", *timeRange.To)
+	}
+	return query
+}
+
+// escapeLike escapes LIKE wildcards so the value matches literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// applyReadingFilter matches a katakana substring of the family and given name
+// readings. Spaces are ignored so "ヤマダタロウ" matches "ヤマダ" + "タロウ".
+func applyReadingFilter(query *gorm.DB, reading string) *gorm.DB {
+	reading = strings.ReplaceAll(reading, " ", "")
+	return query.Where("replace(family_name_kana || given_name_kana, ' ', '') LIKE ?", "%"+escapeLike(reading)+"%")
+}
+
+// applyExpiringFilter matches active users whose validity window is still open
+// but ends at or before the given time
+func applyExpiringFilter(query *gorm.DB, before time.Time) *gorm.DB {
+	return query.Where("is_active = ? AND valid_until > ? AND valid_until <= ?", true, time.Now(), before)
+}
+
+// sortKey is one ORDER BY term of a keyset-paginated listing
+type sortKey struct {
+	expr string
+	desc bool
+	// nullable keys sort NULLs last in either direction
+	nullable bool
+	// value is the cursor's value for the key, nil when it is NULL
+	value interface{}
+}
+
+// sortColumn is an expression users are sorted by for a sort field
+type sortColumn struct {
+	expr string
+	// nullable columns sort NULLs last in either direction
+	nullable bool
+	// value returns the cursor's value for the column, nil when it is NULL
+	value func(cursor *repository.UserCursor) interface{}
+}
+
+// sortColumns whitelists the expressions each sort field orders by. Sort fields
+// from requests are only ever looked up here, never formatted into SQL.
+var sortColumns = map[entity.UserSortField][]sortColumn{
+	entity.UserSortCreatedAt: {
+		{expr: "created_at", value: func(c *repository.UserCursor) interface{} { return c.CreatedAt }},
+	},
+	entity.UserSortUpdatedAt: {
+		{expr: "updated_at", value: func(c *repository.UserCursor) interface{} { return c.UpdatedAt }},
+	},
+	entity.UserSortEmail: {
+		{expr: "email", value: func(c *repository.UserCursor) interface{} { return c.Email }},
+	},
+	entity.UserSortUsername: {
+		{expr: "username", value: func(c *repository.UserCursor) interface{} { return c.Username }},
+	},
+	entity.UserSortFirstName: {
+		{expr: "first_name", value: func(c *repository.UserCursor) interface{} { return c.FirstName }},
+	},
+	entity.UserSortLastName: {
+		{expr: "last_name", value: func(c *repository.UserCursor) interface{} { return c.LastName }},
+	},
+	// Family then given name reading; users without a reading sort last
+	entity.UserSortReading: {
+		{expr: "NULLIF(family_name_kana, '')", nullable: true, value: func(c *repository.UserCursor) interface{} { return nullIfEmpty(c.FamilyNameKana) }},
+		{expr: "NULLIF(given_name_kana, '')", nullable: true, value: func(c *repository.UserCursor) interface{} { return nullIfEmpty(c.GivenNameKana) }},
+	},
+	entity.UserSortLastLoginAt: {
+		{expr: "last_login_at", nullable: true, value: func(c *repository.UserCursor) interface{} { return nullIfNil(c.LastLoginAt) }},
+	},
+	entity.UserSortValidUntil: {
+		{expr: "valid_until", nullable: true, value: func(c *repository.UserCursor) interface{} { return nullIfNil(c.ValidUntil) }},
+	},
+}
+
+// keysetSortKeys returns the ORDER BY terms of the sort, ending with ID in the
+// direction of the last key so that every user has a unique position, with the
+// cursor's values when one is given. Without sort keys the newest users come first.
+func keysetSortKeys(sort *repository.UserSortOptions, cursor *repository.UserCursor) ([]sortKey, error) {
+	if cursor == nil {
+		cursor = &repository.UserCursor{}
+	}
+	userSort := entity.DefaultUserSort
+	if sort != nil && len(sort.Keys) > 0 {
+		userSort = sort.Keys
+	}
+
+	var keys []sortKey
+	for _, key := range userSort {
+		columns, ok := sortColumns[key.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown sort field %q", entity.ErrInvalidSort, key.Field)
+		}
+		for _, column := range columns {
+			keys = append(keys, sortKey{
+				expr:     column.expr,
+				desc:     key.Descending,
+				nullable: column.nullable,
+				value:    column.value(cursor),
+			})
+		}
+	}
+	keys = append(keys, sortKey{expr: "id", desc: keys[len(keys)-1].desc, value: cursor.ID})
+	return keys, nil
+}
+
+// nullIfEmpty returns nil for an empty string, as the NULLIF sort expressions do
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// nullIfNil returns nil for a nil time rather than a typed nil pointer
+func nullIfNil(value *time.Time) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// applyKeysetOrder orders the query by the sort keys
+func applyKeysetOrder(query *gorm.DB, keys []sortKey) *gorm.DB {
+	for _, key := range keys {
+		order := key.expr + " ASC"
+		if key.desc {
+			order = key.expr + " DESC"
+		}
+		if key.nullable {
+			order += " NULLS LAST"
+		}
+		query = query.Order(order)
+	}
+	return query
+}
+
+// applyKeyset restricts the query to rows sorting after the cursor values of the
+// sort keys. Non-nullable keys in one direction compare as a row, e.g.
+// (created_at, id) < (?, ?), which the (created_at, id) index serves directly;
+// otherwise the comparison is expanded key by key with NULLs sorting last.
+func applyKeyset(query *gorm.DB, keys []sortKey) *gorm.DB {
+	uniform := true
+	for _, key := range keys {
+		if key.nullable || key.desc != keys[0].desc {
+			uniform = false
+		}
+	}
+	if uniform {
+		exprs := make([]string, len(keys))
+		placeholders := make([]string, len(keys))
+		values := make([]interface{}, len(keys))
+		for i, key := range keys {
+			exprs[i] = key.expr
+			placeholders[i] = "?"
+			values[i] = key.value
+		}
+		op := ">"
+		if keys[0].desc {
+			op = "<"
+		}
+		return query.Where("("+strings.Join(exprs, ", ")+") "+op+" ("+strings.Join(placeholders, ", ")+")", values...)
+	}
+
+	var disjuncts []string
+	var values []interface{}
+	for i, key := range keys {
+		// Nothing sorts after NULL since NULLs sort last
+		if key.nullable && key.value == nil {
+			continue
+		}
+
+		var conjuncts []string
+		var conjunctValues []interface{}
+		for _, prior := range keys[:i] {
+			if prior.value == nil {
+				conjuncts = append(conjuncts, prior.expr+" IS NULL")
+			} else {
+				conjuncts = append(conjuncts, prior.expr+" = ?")
+				conjunctValues = append(conjunctValues, prior.value)
+			}
+		}
+
+		op := " > ?"
+		if key.desc {
+			op = " < ?"
+		}
+		after := key.expr + op
+		if key.nullable {
+			after = "(" + after + " OR " + key.expr + " IS NULL)"
+		}
+		conjuncts = append(conjuncts, after)
+		conjunctValues = append(conjunctValues, key.value)
+
+		if len(conjuncts) == 1 {
+			disjuncts = append(disjuncts, after)
+		} else {
+			disjuncts = append(disjuncts, "("+strings.Join(conjuncts, " AND ")+")")
+		}
+		values = append(values, conjunctValues...)
+	}
+	if len(disjuncts) == 0 {
+		return query.Where("FALSE")
+	}
+	return query.Where("("+strings.Join(disjuncts, " OR ")+")", values...)
+}
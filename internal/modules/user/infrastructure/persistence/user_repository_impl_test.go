@@ -0,0 +1,194 @@
+package persistence
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// whereOf builds a users query with a dry run and returns its condition, without
+// the soft deletion condition, along with its bound values. Gorm parenthesizes
+// conditions with OR that are combined with the soft deletion condition.
+func whereOf(t *testing.T, build func(query *gorm.DB) *gorm.DB) (string, []interface{}) {
+	t.Helper()
+	db, _ := newFakeDB(t)
+	statement := build(db.Session(&gorm.Session{DryRun: true}).Model(&entity.User{})).Find(&[]entity.User{}).Statement
+
+	sql := statement.SQL.String()
+	where := strings.Index(sql, " WHERE ")
+	if where < 0 {
+		t.Fatalf("query has no condition: %s", sql)
+	}
+	sql = strings.TrimSuffix(sql[where+len(" WHERE "):], ` AND "users"."deleted_at" IS NULL`)
+	if idx := strings.Index(sql, " ORDER BY "); idx >= 0 {
+		sql = sql[:idx]
+	}
+	return sql, statement.Vars
+}
+
+func TestApplyKeyset(t *testing.T) {
+	id := uuid.MustParse("6f1c2a8e-3b7d-4e0a-9c55-1d2e3f4a5b6c")
+	createdAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	lastLoginAt := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		sort     []entity.UserSortKey
+		cursor   *repository.UserCursor
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			name:     "default sort compares as a row",
+			cursor:   &repository.UserCursor{ID: id, CreatedAt: createdAt},
+			wantSQL:  "(created_at, id) < ($1, $2)",
+			wantVars: []interface{}{createdAt, id},
+		},
+		{
+			name:     "ascending compares as a row",
+			sort:     []entity.UserSortKey{{Field: entity.UserSortEmail}},
+			cursor:   &repository.UserCursor{ID: id, Email: "a@example.com"},
+			wantSQL:  "(email, id) > ($1, $2)",
+			wantVars: []interface{}{"a@example.com", id},
+		},
+		{
+			name:     "several keys in one direction compare as a row",
+			sort:     []entity.UserSortKey{{Field: entity.UserSortLastName}, {Field: entity.UserSortFirstName}},
+			cursor:   &repository.UserCursor{ID: id, LastName: "Yamada", FirstName: "Taro"},
+			wantSQL:  "(last_name, first_name, id) > ($1, $2, $3)",
+			wantVars: []interface{}{"Yamada", "Taro", id},
+		},
+		{
+			name:    "mixed directions expand key by key",
+			sort:    []entity.UserSortKey{{Field: entity.UserSortLastName}, {Field: entity.UserSortCreatedAt, Descending: true}},
+			cursor:  &repository.UserCursor{ID: id, LastName: "Yamada", CreatedAt: createdAt},
+			wantSQL: "((last_name > $1 OR (last_name = $2 AND created_at < $3) OR (last_name = $4 AND created_at = $5 AND id < $6)))",
+			wantVars: []interface{}{"Yamada",
+				"Yamada", createdAt,
+				"Yamada", createdAt, id},
+		},
+		{
+			name:     "nullable key includes NULLs after the cursor",
+			sort:     []entity.UserSortKey{{Field: entity.UserSortLastLoginAt, Descending: true}},
+			cursor:   &repository.UserCursor{ID: id, LastLoginAt: &lastLoginAt},
+			wantSQL:  "(((last_login_at < $1 OR last_login_at IS NULL) OR (last_login_at = $2 AND id < $3)))",
+			wantVars: []interface{}{lastLoginAt, lastLoginAt, id},
+		},
+		{
+			name:     "cursor at NULL only continues among NULLs",
+			sort:     []entity.UserSortKey{{Field: entity.UserSortLastLoginAt}},
+			cursor:   &repository.UserCursor{ID: id},
+			wantSQL:  "(((last_login_at IS NULL AND id > $1)))",
+			wantVars: []interface{}{id},
+		},
+		{
+			name:    "reading sorts by both readings",
+			sort:    []entity.UserSortKey{{Field: entity.UserSortReading}},
+			cursor:  &repository.UserCursor{ID: id, FamilyNameKana: "ヤマダ", GivenNameKana: "タロウ"},
+			wantSQL: "(((NULLIF(family_name_kana, '') > $1 OR NULLIF(family_name_kana, '') IS NULL) OR (NULLIF(family_name_kana, '') = $2 AND (NULLIF(given_name_kana, '') > $3 OR NULLIF(given_name_kana, '') IS NULL)) OR (NULLIF(family_name_kana, '') = $4 AND NULLIF(given_name_kana, '') = $5 AND id > $6)))",
+			wantVars: []interface{}{"ヤマダ",
+				"ヤマダ", "タロウ",
+				"ヤマダ", "タロウ", id},
+		},
+		{
+			name:     "empty reading is NULL",
+			sort:     []entity.UserSortKey{{Field: entity.UserSortReading}},
+			cursor:   &repository.UserCursor{ID: id, FamilyNameKana: "ヤマダ"},
+			wantSQL:  "(((NULLIF(family_name_kana, '') > $1 OR NULLIF(family_name_kana, '') IS NULL) OR (NULLIF(family_name_kana, '') = $2 AND NULLIF(given_name_kana, '') IS NULL AND id > $3)))",
+			wantVars: []interface{}{"ヤマダ", "ヤマダ", id},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := keysetSortKeys(&repository.UserSortOptions{Keys: tt.sort}, tt.cursor)
+			if err != nil {
+				t.Fatalf("keysetSortKeys() error = %v", err)
+			}
+
+			sql, vars := whereOf(t, func(query *gorm.DB) *gorm.DB { return applyKeyset(query, keys) })
+			if sql != tt.wantSQL {
+				t.Errorf("condition =\n\t%s\nwant\n\t%s", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(vars, tt.wantVars) {
+				t.Errorf("values = %v, want %v", vars, tt.wantVars)
+			}
+		})
+	}
+}
+
+func TestApplyKeysetOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		sort []entity.UserSortKey
+		want string
+	}{
+		{name: "default", want: "created_at DESC,id DESC"},
+		{name: "ascending", sort: []entity.UserSortKey{{Field: entity.UserSortEmail}}, want: "email ASC,id ASC"},
+		{
+			name: "id follows the last key",
+			sort: []entity.UserSortKey{{Field: entity.UserSortLastName, Descending: true}, {Field: entity.UserSortCreatedAt}},
+			want: "last_name DESC,created_at ASC,id ASC",
+		},
+		{
+			name: "nullable keys sort NULLs last",
+			sort: []entity.UserSortKey{{Field: entity.UserSortValidUntil, Descending: true}},
+			want: "valid_until DESC NULLS LAST,id DESC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := keysetSortKeys(&repository.UserSortOptions{Keys: tt.sort}, nil)
+			if err != nil {
+				t.Fatalf("keysetSortKeys() error = %v", err)
+			}
+
+			db, _ := newFakeDB(t)
+			sql := applyKeysetOrder(db.Session(&gorm.Session{DryRun: true}).Model(&entity.User{}), keys).Find(&[]entity.User{}).Statement.SQL.String()
+			order := sql[strings.Index(sql, " ORDER BY ")+len(" ORDER BY "):]
+			if order != tt.want {
+				t.Errorf("order = %s, want %s", order, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeysetSortKeysRejectsUnknownField(t *testing.T) {
+	_, err := keysetSortKeys(&repository.UserSortOptions{Keys: []entity.UserSortKey{{Field: "password"}}}, nil)
+	if !errors.Is(err, entity.ErrInvalidSort) {
+		t.Fatalf("keysetSortKeys() error = %v, want ErrInvalidSort", err)
+	}
+}
+
+func TestUpdateLeavesUnownedColumns(t *testing.T) {
+	db, _ := newFakeDB(t)
+	user := &entity.User{ID: uuid.New(), Email: "user@example.com", Version: 2}
+	statement := db.Session(&gorm.Session{DryRun: true}).
+		Model(user).
+		Select("*").
+		Omit(unownedUserColumns...).
+		Updates(user).Statement
+
+	sql := statement.SQL.String()
+	set := sql[:strings.Index(sql, " WHERE ")]
+	if !strings.Contains(set, `"email"=`) {
+		t.Fatalf("update does not set email: %s", sql)
+	}
+	// Sign-ins, deletion and merging write these without going through Update
+	for _, column := range []string{"id", "created_at", "last_login_at", "deleted_at", "merged_into_id"} {
+		if statement.Schema.LookUpField(column) == nil {
+			t.Errorf("users has no column %s", column)
+		}
+		if strings.Contains(set, `"`+column+`"=`) {
+			t.Errorf("update sets %s: %s", column, sql)
+		}
+	}
+}
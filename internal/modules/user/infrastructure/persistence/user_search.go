@@ -0,0 +1,233 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/repository"
+	"gorm.io/gorm"
+)
+
+// searchColumns are the columns searches match by trigram word similarity and
+// prefix. Each has a GIN gin_trgm_ops index on lower(column).
+var searchColumns = []struct {
+	field  entity.UserSearchField
+	column string
+}{
+	{entity.UserSearchEmail, "email"},
+	{entity.UserSearchUsername, "username"},
+	{entity.UserSearchFirstName, "first_name"},
+	{entity.UserSearchLastName, "last_name"},
+}
+
+// searchReadingExpr is the reading kana queries match, as in applyReadingFilter
+const searchReadingExpr = "replace(family_name_kana || given_name_kana, ' ', '')"
+
+// userSearchRow is a user with the relevance columns the search subquery selects
+type userSearchRow struct {
+	entity.User `gorm:"embedded"`
+
+	SearchScore      float64
+	MatchedEmail     bool
+	MatchedUsername  bool
+	MatchedFirstName bool
+	MatchedLastName  bool
+	MatchedReading   bool
+}
+
+// matchedFields lists the fields the query matched
+func (row *userSearchRow) matchedFields() []entity.UserSearchField {
+	matched := map[entity.UserSearchField]bool{
+		entity.UserSearchEmail:     row.MatchedEmail,
+		entity.UserSearchUsername:  row.MatchedUsername,
+		entity.UserSearchFirstName: row.MatchedFirstName,
+		entity.UserSearchLastName:  row.MatchedLastName,
+		entity.UserSearchReading:   row.MatchedReading,
+	}
+	var fields []entity.UserSearchField
+	for _, field := range entity.SearchableUserFields {
+		if matched[field] {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// userSearchTerms holds the conditions of a search query with their arguments
+type userSearchTerms struct {
+	// matches are the per-field conditions, in the order of searchColumns then reading
+	matches []string
+	args    [][]interface{}
+	// similarity is the best trigram word similarity of the query to a field
+	similarity     string
+	similarityArgs []interface{}
+	// prefix holds when the query is a prefix of a field or occurs in the reading
+	prefix     string
+	prefixArgs []interface{}
+}
+
+// newUserSearchTerms builds the conditions of a non-empty search query. Fields
+// match when the query is similar to a word of the field (pg_trgm's <% operator,
+// which uses pg_trgm.word_similarity_threshold) or a prefix of it. Kana queries
+// also match a substring of the name reading.
+func newUserSearchTerms(query string) *userSearchTerms {
+	lowered := strings.ToLower(query)
+	prefix := escapeLike(lowered) + "%"
+
+	terms := &userSearchTerms{}
+	var similarities, prefixes []string
+	for _, c := range searchColumns {
+		column := "lower(" + c.column + ")"
+		terms.matches = append(terms.matches, "(? <% "+column+" OR "+column+" LIKE ?)")
+		terms.args = append(terms.args, []interface{}{lowered, prefix})
+		similarities = append(similarities, "word_similarity(?, "+column+")")
+		terms.similarityArgs = append(terms.similarityArgs, lowered)
+		prefixes = append(prefixes, column+" LIKE ?")
+		terms.prefixArgs = append(terms.prefixArgs, prefix)
+	}
+	if entity.IsKana(query) {
+		reading := strings.ReplaceAll(entity.NormalizeKana(query), " ", "")
+		condition := searchReadingExpr + " LIKE ?"
+		terms.matches = append(terms.matches, "("+condition+")")
+		terms.args = append(terms.args, []interface{}{"%" + escapeLike(reading) + "%"})
+		prefixes = append(prefixes, condition)
+		terms.prefixArgs = append(terms.prefixArgs, "%"+escapeLike(reading)+"%")
+	}
+	terms.similarity = "GREATEST(" + strings.Join(similarities, ", ") + ")"
+	terms.prefix = "(" + strings.Join(prefixes, " OR ") + ")"
+	return terms
+}
+
+// condition returns the condition matching users any field of which matches
+func (t *userSearchTerms) condition() (string, []interface{}) {
+	var args []interface{}
+	for _, fieldArgs := range t.args {
+		args = append(args, fieldArgs...)
+	}
+	return "(" + strings.Join(t.matches, " OR ") + ")", args
+}
+
+// columns returns the select list of the search subquery: the score, which adds
+// one to the trigram similarity of prefix matches, and a matched flag per field
+func (t *userSearchTerms) columns() (string, []interface{}) {
+	columns := []string{"users.*", "(" + t.similarity + " + CASE WHEN " + t.prefix + " THEN 1 ELSE 0 END)::float8 AS search_score"}
+	args := append(append([]interface{}{}, t.similarityArgs...), t.prefixArgs...)
+
+	flags := []string{"matched_email", "matched_username", "matched_first_name", "matched_last_name", "matched_reading"}
+	for i, match := range t.matches {
+		columns = append(columns, match+" AS "+flags[i])
+		args = append(args, t.args[i]...)
+	}
+	return strings.Join(columns, ", "), args
+}
+
+// searchUsersQuery returns the query of users matching the search query and filter
+func (r *userRepository) searchUsersQuery(ctx context.Context, db *gorm.DB, query string, filter *repository.UserFilter) (*gorm.DB, *userSearchTerms, error) {
+	q := r.scoped(ctx, db).Model(&entity.User{})
+
+	q, err := applyUserFilter(q, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return q, nil, nil
+	}
+	terms := newUserSearchTerms(query)
+	condition, args := terms.condition()
+	return q.Where(condition, args...), terms, nil
+}
+
+// Search retrieves users matching a search query and filter with their relevance.
+// The matches are selected in a subquery with their score so that they can be
+// ordered and paginated by it like any other column.
+func (r *userRepository) Search(ctx context.Context, opts *repository.UserSearchOptions) ([]*repository.UserSearchResult, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	matches, terms, err := r.searchUsersQuery(ctx, db, opts.Query, opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if terms != nil {
+		columns, args := terms.columns()
+		matches = matches.Select(columns, args...)
+	} else {
+		matches = matches.Select("users.*, 0::float8 AS search_score")
+	}
+
+	// The subquery already applied soft deletion, which suspended status filters lift
+	query := db.WithContext(ctx).Unscoped().Table("(?) AS users", matches)
+
+	// Apply sorting; keyset pagination continues after the cursor
+	keys, err := searchSortKeys(opts.Sort, opts.After)
+	if err != nil {
+		return nil, err
+	}
+	if opts.After != nil {
+		query = applyKeyset(query, keys)
+	}
+	query = applyKeysetOrder(query, keys)
+
+	// Apply pagination
+	if opts.After == nil {
+		query = query.Offset(opts.Offset)
+	}
+	var rows []*userSearchRow
+	if err := query.
+		Limit(opts.Limit).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	results := make([]*repository.UserSearchResult, len(rows))
+	for i, row := range rows {
+		user := row.User
+		results[i] = &repository.UserSearchResult{
+			User:          &user,
+			Score:         row.SearchScore,
+			MatchedFields: row.matchedFields(),
+		}
+	}
+	return results, nil
+}
+
+// CountSearch returns the count of users matching a search query and filter
+func (r *userRepository) CountSearch(ctx context.Context, query string, filter *repository.UserFilter) (int64, error) {
+	db, err := r.getDB(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	matches, _, err := r.searchUsersQuery(ctx, db, query, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := matches.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count search matches: %w", err)
+	}
+
+	return count, nil
+}
+
+// searchSortKeys returns the ORDER BY terms of a search: the sort when one is
+// given, otherwise the best matches first with ID breaking ties
+func searchSortKeys(sort *repository.UserSortOptions, cursor *repository.UserCursor) ([]sortKey, error) {
+	if sort != nil {
+		return keysetSortKeys(sort, cursor)
+	}
+	if cursor == nil {
+		cursor = &repository.UserCursor{}
+	}
+	return []sortKey{
+		{expr: "search_score", desc: true, value: cursor.Score},
+		{expr: "id", desc: true, value: cursor.ID},
+	}, nil
+}
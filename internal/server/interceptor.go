@@ -3,28 +3,37 @@ package server
 import (
 	"context"
 	"log"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/gigi434/sample-grpc-server/internal/shared/auth"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+	"golang.org/x/text/language"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
 )
 
 // LoggingInterceptor logs all incoming requests
 func LoggingInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		start := time.Now()
-		
+
 		// Get metadata
 		md, _ := metadata.FromIncomingContext(ctx)
-		
+
 		// Log request
 		log.Printf("[REQUEST] Method: %s, Metadata: %v", info.FullMethod, md)
-		
+
 		// Handle request
 		resp, err := handler(ctx, req)
-		
+
 		// Log response
 		duration := time.Since(start)
 		if err != nil {
@@ -32,7 +41,7 @@ func LoggingInterceptor() grpc.UnaryServerInterceptor {
 		} else {
 			log.Printf("[RESPONSE] Method: %s, Duration: %v", info.FullMethod, duration)
 		}
-		
+
 		return resp, err
 	}
 }
@@ -46,63 +55,304 @@ func RecoveryInterceptor() grpc.UnaryServerInterceptor {
 				err = status.Errorf(codes.Internal, "internal server error")
 			}
 		}()
-		
+
 		return handler(ctx, req)
 	}
 }
 
+// ErrorInterceptor converts errors returned by handlers into gRPC statuses.
+// Errors that already carry a status pass through; others are translated to
+// application errors, whose status carries ErrorInfo, BadRequest and a
+// LocalizedMessage matching the accept-language metadata.
+func ErrorInterceptor(translators ...apperrors.Translator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		return nil, statusError(ctx, info.FullMethod, err, translators)
+	}
+}
+
+// statusError converts a handler error into a status error, translating errors
+// that do not already carry a status to application errors
+func statusError(ctx context.Context, method string, err error, translators []apperrors.Translator) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return translateStatus(ctx, method, err, translators).Err()
+}
+
+// ErrorStatus converts an error into the status ErrorInterceptor would return for
+// it. Handlers use it to report the errors of individual items of a batch.
+func ErrorStatus(ctx context.Context, err error, translators ...apperrors.Translator) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	method, _ := grpc.Method(ctx)
+	return translateStatus(ctx, method, err, translators)
+}
+
+// translateStatus translates an error to an application error and builds its status
+func translateStatus(ctx context.Context, method string, err error, translators []apperrors.Translator) *status.Status {
+	appErr := apperrors.Translate(err, translators...)
+	if appErr.Code == apperrors.CodeInternal {
+		// Only the generic message reaches the client
+		log.Printf("[INTERNAL] Method: %s, Error: %v", method, err)
+	}
+	return errorStatus(ctx, appErr)
+}
+
+// grpcCodes maps application error codes to gRPC status codes
+var grpcCodes = map[apperrors.Code]codes.Code{
+	apperrors.CodeInvalidArgument:    codes.InvalidArgument,
+	apperrors.CodeNotFound:           codes.NotFound,
+	apperrors.CodeAlreadyExists:      codes.AlreadyExists,
+	apperrors.CodeFailedPrecondition: codes.FailedPrecondition,
+	apperrors.CodeAborted:            codes.Aborted,
+	apperrors.CodeUnauthenticated:    codes.Unauthenticated,
+	apperrors.CodePermissionDenied:   codes.PermissionDenied,
+	apperrors.CodeInternal:           codes.Internal,
+}
+
+// errorStatus builds the status for an application error with its error details
+func errorStatus(ctx context.Context, appErr *apperrors.Error) *status.Status {
+	code, ok := grpcCodes[appErr.Code]
+	if !ok {
+		code = codes.Unknown
+	}
+	st := status.New(code, appErr.Message)
+
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   appErr.Reason,
+			Domain:   apperrors.Domain,
+			Metadata: appErr.Metadata,
+		},
+	}
+	if len(appErr.Violations) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, v := range appErr.Violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Description,
+			})
+		}
+		details = append(details, badRequest)
+	}
+	locale, message := localizedMessage(ctx, appErr)
+	details = append(details, &errdetails.LocalizedMessage{Locale: locale, Message: message})
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+// localizedMessage picks the translation best matching the accept-language
+// metadata, falling back to the English message
+func localizedMessage(ctx context.Context, appErr *apperrors.Error) (string, string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	accept := md.Get("accept-language")
+	if len(appErr.Translations) == 0 || len(accept) == 0 {
+		return language.English.String(), appErr.Message
+	}
+
+	tags := []language.Tag{language.English}
+	messages := []string{appErr.Message}
+	locales := make([]string, 0, len(appErr.Translations))
+	for locale := range appErr.Translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			continue
+		}
+		tags = append(tags, tag)
+		messages = append(messages, appErr.Translations[locale])
+	}
+
+	desired, _, err := language.ParseAcceptLanguage(strings.Join(accept, ","))
+	if err != nil {
+		return language.English.String(), appErr.Message
+	}
+	_, index, confidence := language.NewMatcher(tags).Match(desired...)
+	if confidence == language.No {
+		index = 0
+	}
+	return tags[index].String(), messages[index]
+}
+
 // ValidationInterceptor validates incoming requests
 func ValidationInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		// You can add custom validation logic here
 		// For example, check if required fields are present
-		
+
 		if validator, ok := req.(interface{ Validate() error }); ok {
 			if err := validator.Validate(); err != nil {
 				return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
 			}
 		}
-		
+
+		return handler(ctx, req)
+	}
+}
+
+// AuthInterceptor verifies the bearer token of each request and stores its claims
+// in the context. Tokens restricted to a password change only permit ChangePassword
+// for the token's own user.
+func AuthInterceptor(issuer *auth.TokenIssuer) grpc.UnaryServerInterceptor {
+	authenticate := authenticator(issuer)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
 		return handler(ctx, req)
 	}
 }
 
-// AuthInterceptor handles authentication
-func AuthInterceptor() grpc.UnaryServerInterceptor {
-	// List of methods that don't require authentication
+// authenticator returns a function verifying the bearer token of a request to a
+// method and returning the context with its claims. The request is nil for streams.
+func authenticator(issuer *auth.TokenIssuer) func(ctx context.Context, method string, req interface{}) (context.Context, error) {
+	// List of methods that don't require authentication.
+	// Open signup is disabled: new users join by accepting an invitation.
 	publicMethods := map[string]bool{
 		"/user.v1.UserService/AuthenticateUser": true,
-		"/user.v1.UserService/CreateUser":       true,
+		"/user.v1.UserService/AcceptInvitation": true,
 		"/health.v1.HealthService/Check":        true,
+		"/health.v1.HealthService/Watch":        true,
 	}
-	
-	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+
+	// Methods permitted by tokens restricted to a password change
+	passwordChangeMethods := map[string]bool{
+		"/user.v1.UserService/ChangePassword": true,
+	}
+
+	return func(ctx context.Context, method string, req interface{}) (context.Context, error) {
 		// Skip authentication for public methods
-		if publicMethods[info.FullMethod] {
-			return handler(ctx, req)
+		if publicMethods[method] {
+			return ctx, nil
 		}
-		
+
 		// Get metadata
 		md, ok := metadata.FromIncomingContext(ctx)
 		if !ok {
 			return nil, status.Errorf(codes.Unauthenticated, "metadata not found")
 		}
-		
+
 		// Check for authorization header
 		authHeader := md.Get("authorization")
 		if len(authHeader) == 0 {
 			return nil, status.Errorf(codes.Unauthenticated, "authorization header not found")
 		}
-		
-		// TODO: Validate token
-		// For now, we'll just check if a token is present
-		// In production, you should validate the JWT token here
-		
+
+		// Verify token
+		token := strings.TrimSpace(strings.TrimPrefix(authHeader[0], "Bearer "))
+		claims, err := issuer.Verify(token, time.Now())
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "%v", err)
+		}
+
+		if claims.Scope == auth.ScopePasswordChange {
+			if !passwordChangeMethods[method] {
+				return nil, status.Errorf(codes.PermissionDenied, "password change required")
+			}
+			if target, ok := req.(interface{ GetUserId() string }); !ok || target.GetUserId() != claims.UserID.String() {
+				return nil, status.Errorf(codes.PermissionDenied, "token only permits changing your own password")
+			}
+		}
+
+		return auth.WithClaims(ctx, claims), nil
+	}
+}
+
+// MembershipChecker reports whether a user belongs to an organization
+type MembershipChecker interface {
+	IsMember(ctx context.Context, organizationID, userID uuid.UUID) (bool, error)
+}
+
+// TenantInterceptor resolves the current organization and stores it in the context.
+// It runs after authentication: a token bound to an organization determines the
+// tenant, and the metadata header may only repeat it. Otherwise the header selects
+// the tenant, which an authenticated caller must be a member of. When required is
+// true, requests without a tenant are rejected, except for methods that operate
+// across tenants.
+func TenantInterceptor(header string, required bool, members MembershipChecker) grpc.UnaryServerInterceptor {
+	resolve := tenantResolver(header, required, members)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := resolve(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
 		return handler(ctx, req)
 	}
 }
 
+// tenantResolver returns a function resolving the tenant of a request to a method
+// and returning the context with it
+func tenantResolver(header string, required bool, members MembershipChecker) func(ctx context.Context, method string) (context.Context, error) {
+	// Services and methods that don't require a tenant
+	crossTenantPrefixes := []string{
+		"/health.v1.HealthService/",
+		"/organization.v1.OrganizationService/",
+		// The invitation token determines the organization
+		"/user.v1.UserService/AcceptInvitation",
+	}
+
+	return func(ctx context.Context, method string) (context.Context, error) {
+		// Get metadata
+		md, _ := metadata.FromIncomingContext(ctx)
+		var headerOrganizationID *uuid.UUID
+		if values := md.Get(header); len(values) > 0 && values[0] != "" {
+			organizationID, err := uuid.Parse(values[0])
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid %s metadata", header)
+			}
+			headerOrganizationID = &organizationID
+		}
+
+		claims, authenticated := auth.ClaimsFromContext(ctx)
+		switch {
+		case authenticated && claims.OrganizationID != nil:
+			if headerOrganizationID != nil && *headerOrganizationID != *claims.OrganizationID {
+				return nil, status.Errorf(codes.PermissionDenied, "token is not valid for the organization in %s metadata", header)
+			}
+			ctx = tenant.WithOrganizationID(ctx, *claims.OrganizationID)
+		case headerOrganizationID != nil:
+			// Unauthenticated requests only reach public methods, which look users up
+			// within the tenant themselves
+			if authenticated {
+				member, err := members.IsMember(ctx, *headerOrganizationID, claims.UserID)
+				if err != nil {
+					log.Printf("[INTERNAL] Method: %s, Error: failed to check membership: %v", method, err)
+					return nil, status.Errorf(codes.Internal, "internal server error")
+				}
+				if !member {
+					return nil, status.Errorf(codes.PermissionDenied, "not a member of the organization in %s metadata", header)
+				}
+			}
+			ctx = tenant.WithOrganizationID(ctx, *headerOrganizationID)
+		}
+
+		if _, ok := tenant.OrganizationIDFromContext(ctx); !ok && required {
+			for _, prefix := range crossTenantPrefixes {
+				if strings.HasPrefix(method, prefix) {
+					return ctx, nil
+				}
+			}
+			return nil, status.Errorf(codes.InvalidArgument, "%s metadata is required", header)
+		}
+
+		return ctx, nil
+	}
+}
+
 // ChainUnaryInterceptors chains multiple unary interceptors
 func ChainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.ServerOption {
 	return grpc.ChainUnaryInterceptor(interceptors...)
-}
\ No newline at end of file
+}
@@ -0,0 +1,285 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/shared/auth"
+	"github.com/gigi434/sample-grpc-server/internal/shared/tenant"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// userIDRequest is a request naming a target user like ChangePasswordRequest
+type userIDRequest struct {
+	userID string
+}
+
+func (r *userIDRequest) GetUserId() string {
+	return r.userID
+}
+
+func TestAuthInterceptorRestrictsPasswordChangeTokens(t *testing.T) {
+	issuer := auth.NewTokenIssuer([]byte("test-secret"), time.Hour, time.Minute)
+	userID := uuid.New()
+	otherUserID := uuid.New()
+
+	fullToken, _, err := issuer.Issue(userID, nil, auth.ScopeFull, time.Now())
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	restrictedToken, _, err := issuer.Issue(userID, nil, auth.ScopePasswordChange, time.Now())
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		req    interface{}
+		want   codes.Code
+	}{
+		{
+			name:   "restricted token changes own password",
+			token:  restrictedToken,
+			method: "/user.v1.UserService/ChangePassword",
+			req:    &userIDRequest{userID: userID.String()},
+			want:   codes.OK,
+		},
+		{
+			name:   "restricted token changes another user's password",
+			token:  restrictedToken,
+			method: "/user.v1.UserService/ChangePassword",
+			req:    &userIDRequest{userID: otherUserID.String()},
+			want:   codes.PermissionDenied,
+		},
+		{
+			name:   "restricted token changes password without target",
+			token:  restrictedToken,
+			method: "/user.v1.UserService/ChangePassword",
+			req:    struct{}{},
+			want:   codes.PermissionDenied,
+		},
+		{
+			name:   "restricted token reads own user",
+			token:  restrictedToken,
+			method: "/user.v1.UserService/GetUser",
+			req:    &userIDRequest{userID: userID.String()},
+			want:   codes.PermissionDenied,
+		},
+		{
+			name:   "restricted token creates user",
+			token:  restrictedToken,
+			method: "/user.v1.UserService/CreateUser",
+			req:    struct{}{},
+			want:   codes.PermissionDenied,
+		},
+		{
+			name:   "full token reads user",
+			token:  fullToken,
+			method: "/user.v1.UserService/GetUser",
+			req:    &userIDRequest{userID: otherUserID.String()},
+			want:   codes.OK,
+		},
+		{
+			name:   "full token changes own password",
+			token:  fullToken,
+			method: "/user.v1.UserService/ChangePassword",
+			req:    &userIDRequest{userID: userID.String()},
+			want:   codes.OK,
+		},
+		{
+			name:   "missing token",
+			method: "/user.v1.UserService/GetUser",
+			req:    struct{}{},
+			want:   codes.Unauthenticated,
+		},
+		{
+			name:   "invalid token",
+			token:  fullToken + "x",
+			method: "/user.v1.UserService/GetUser",
+			req:    struct{}{},
+			want:   codes.Unauthenticated,
+		},
+		{
+			name:   "public method without token",
+			method: "/user.v1.UserService/AuthenticateUser",
+			req:    struct{}{},
+			want:   codes.OK,
+		},
+	}
+
+	interceptor := AuthInterceptor(issuer)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.MD{}
+			if tt.token != "" {
+				md.Set("authorization", "Bearer "+tt.token)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+
+			handled := false
+			_, err := interceptor(ctx, tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				handled = true
+				return nil, nil
+			})
+
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("code = %v, want %v (err = %v)", got, tt.want, err)
+			}
+			if handled != (tt.want == codes.OK) {
+				t.Fatalf("handler called = %v, want %v", handled, tt.want == codes.OK)
+			}
+		})
+	}
+}
+
+func TestStreamAuthInterceptorRefusesPasswordChangeTokens(t *testing.T) {
+	issuer := auth.NewTokenIssuer([]byte("test-secret"), time.Hour, time.Minute)
+	restrictedToken, _, err := issuer.Issue(uuid.New(), nil, auth.ScopePasswordChange, time.Now())
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+restrictedToken))
+	err = StreamAuthInterceptor(issuer)(nil, &contextStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/user.v1.UserService/WatchUsers"}, func(srv interface{}, ss grpc.ServerStream) error {
+		t.Fatal("handler called for a restricted token")
+		return nil
+	})
+	if got := status.Code(err); got != codes.PermissionDenied {
+		t.Fatalf("code = %v, want %v (err = %v)", got, codes.PermissionDenied, err)
+	}
+}
+
+// memberships is a MembershipChecker backed by a set of organization and user pairs
+type memberships map[[2]uuid.UUID]bool
+
+func (m memberships) IsMember(ctx context.Context, organizationID, userID uuid.UUID) (bool, error) {
+	return m[[2]uuid.UUID{organizationID, userID}], nil
+}
+
+func TestTenantInterceptorResolvesTenant(t *testing.T) {
+	userID := uuid.New()
+	memberOrganizationID := uuid.New()
+	otherOrganizationID := uuid.New()
+	members := memberships{{memberOrganizationID, userID}: true}
+
+	tests := []struct {
+		name         string
+		claims       *auth.Claims
+		header       string
+		method       string
+		wantCode     codes.Code
+		wantTenant   uuid.UUID
+		wantNoTenant bool
+	}{
+		{
+			name:       "token organization",
+			claims:     &auth.Claims{UserID: userID, OrganizationID: &otherOrganizationID},
+			method:     "/user.v1.UserService/GetUser",
+			wantTenant: otherOrganizationID,
+		},
+		{
+			name:       "header repeats token organization",
+			claims:     &auth.Claims{UserID: userID, OrganizationID: &otherOrganizationID},
+			header:     otherOrganizationID.String(),
+			method:     "/user.v1.UserService/GetUser",
+			wantTenant: otherOrganizationID,
+		},
+		{
+			name:     "header conflicts with token organization",
+			claims:   &auth.Claims{UserID: userID, OrganizationID: &otherOrganizationID},
+			header:   memberOrganizationID.String(),
+			method:   "/user.v1.UserService/GetUser",
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:       "header names organization of member",
+			claims:     &auth.Claims{UserID: userID},
+			header:     memberOrganizationID.String(),
+			method:     "/user.v1.UserService/GetUser",
+			wantTenant: memberOrganizationID,
+		},
+		{
+			name:     "header names organization of non-member",
+			claims:   &auth.Claims{UserID: userID},
+			header:   otherOrganizationID.String(),
+			method:   "/user.v1.UserService/GetUser",
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "header names organization of non-member on cross-tenant method",
+			claims:   &auth.Claims{UserID: userID},
+			header:   otherOrganizationID.String(),
+			method:   "/organization.v1.OrganizationService/GetOrganization",
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:       "header on public method",
+			header:     otherOrganizationID.String(),
+			method:     "/user.v1.UserService/AuthenticateUser",
+			wantTenant: otherOrganizationID,
+		},
+		{
+			name:     "invalid header",
+			claims:   &auth.Claims{UserID: userID},
+			header:   "not-a-uuid",
+			method:   "/user.v1.UserService/GetUser",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "missing tenant",
+			claims:   &auth.Claims{UserID: userID},
+			method:   "/user.v1.UserService/GetUser",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:         "missing tenant on cross-tenant method",
+			claims:       &auth.Claims{UserID: userID},
+			method:       "/organization.v1.OrganizationService/CreateOrganization",
+			wantNoTenant: true,
+		},
+	}
+
+	interceptor := TenantInterceptor("x-organization-id", true, members)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.MD{}
+			if tt.header != "" {
+				md.Set("x-organization-id", tt.header)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+			if tt.claims != nil {
+				ctx = auth.WithClaims(ctx, tt.claims)
+			}
+
+			var resolved context.Context
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				resolved = ctx
+				return nil, nil
+			})
+
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("code = %v, want %v (err = %v)", got, tt.wantCode, err)
+			}
+			if tt.wantCode != codes.OK {
+				return
+			}
+			organizationID, ok := tenant.OrganizationIDFromContext(resolved)
+			if tt.wantNoTenant {
+				if ok {
+					t.Fatalf("tenant = %v, want none", organizationID)
+				}
+				return
+			}
+			if !ok || organizationID != tt.wantTenant {
+				t.Fatalf("tenant = %v, want %v", organizationID, tt.wantTenant)
+			}
+		})
+	}
+}
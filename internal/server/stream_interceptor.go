@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gigi434/sample-grpc-server/internal/shared/auth"
+	apperrors "github.com/gigi434/sample-grpc-server/internal/shared/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// contextStream is a server stream whose context was replaced by an interceptor
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the replaced context
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// validatingStream validates each message received from the client
+type validatingStream struct {
+	grpc.ServerStream
+}
+
+// RecvMsg receives a message and validates it
+func (s *validatingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if validator, ok := m.(interface{ Validate() error }); ok {
+		if err := validator.Validate(); err != nil {
+			return status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// StreamLoggingInterceptor logs all incoming streams
+func StreamLoggingInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		// Get metadata
+		md, _ := metadata.FromIncomingContext(ss.Context())
+
+		// Log stream start
+		log.Printf("[STREAM] Method: %s, Metadata: %v", info.FullMethod, md)
+
+		// Handle stream
+		err := handler(srv, ss)
+
+		// Log stream end
+		duration := time.Since(start)
+		if err != nil {
+			log.Printf("[ERROR] Method: %s, Duration: %v, Error: %v", info.FullMethod, duration, err)
+		} else {
+			log.Printf("[STREAM END] Method: %s, Duration: %v", info.FullMethod, duration)
+		}
+
+		return err
+	}
+}
+
+// StreamRecoveryInterceptor recovers from panics in stream handlers and returns an error
+func StreamRecoveryInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[PANIC] Method: %s, Panic: %v", info.FullMethod, r)
+				err = status.Errorf(codes.Internal, "internal server error")
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// StreamErrorInterceptor converts errors returned by stream handlers into gRPC
+// statuses like ErrorInterceptor
+func StreamErrorInterceptor(translators ...apperrors.Translator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		if err == nil {
+			return nil
+		}
+		return statusError(ss.Context(), info.FullMethod, err, translators)
+	}
+}
+
+// StreamValidationInterceptor validates the messages clients send on streams
+func StreamValidationInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatingStream{ServerStream: ss})
+	}
+}
+
+// StreamAuthInterceptor verifies the bearer token of each stream like
+// AuthInterceptor. Tokens restricted to a password change permit no streams.
+func StreamAuthInterceptor(issuer *auth.TokenIssuer) grpc.StreamServerInterceptor {
+	authenticate := authenticator(issuer)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), info.FullMethod, nil)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// StreamTenantInterceptor resolves the current organization of each stream like TenantInterceptor
+func StreamTenantInterceptor(header string, required bool, members MembershipChecker) grpc.StreamServerInterceptor {
+	resolve := tenantResolver(header, required, members)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := resolve(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// ChainStreamInterceptors chains multiple stream interceptors
+func ChainStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) grpc.ServerOption {
+	return grpc.ChainStreamInterceptor(interceptors...)
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// attributeIndexPrefix starts the names of the indexes enforcing unique custom
+// attributes. The names continue with the attribute key and the uniqueness scope.
+const attributeIndexPrefix = "idx_users_attr_"
+
+// attributeIndexScopes are the name suffixes of the indexes of a unique attribute
+var attributeIndexScopes = []string{"_global", "_org"}
+
+// attributeIndexStatements returns the statements creating the indexes that enforce
+// a unique attribute. Like email and username, values are unique among live users
+// within their uniqueness scope. Users without a value or with a null one do not
+// take part. Keys are validated identifiers, so they are safe to embed.
+func attributeIndexStatements(key string) []string {
+	value := fmt.Sprintf("(attributes -> '%s')", key)
+	present := fmt.Sprintf("jsonb_typeof(attributes -> '%s') <> 'null'", key)
+	return []string{
+		fmt.Sprintf(`CREATE UNIQUE INDEX IF NOT EXISTS %s ON users (%s)
+			WHERE deleted_at IS NULL AND uniqueness_organization_id IS NULL AND %s`,
+			attributeIndexPrefix+key+attributeIndexScopes[0], value, present),
+		fmt.Sprintf(`CREATE UNIQUE INDEX IF NOT EXISTS %s ON users (uniqueness_organization_id, %s)
+			WHERE deleted_at IS NULL AND uniqueness_organization_id IS NOT NULL AND %s`,
+			attributeIndexPrefix+key+attributeIndexScopes[1], value, present),
+	}
+}
+
+// CreateAttributeIndexes creates the unique indexes of a unique attribute. It fails
+// with entity.ErrInvalidAttributeDefinition when users already share a value.
+func CreateAttributeIndexes(db *gorm.DB, key string) error {
+	for _, statement := range attributeIndexStatements(key) {
+		if err := db.Exec(statement).Error; err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return fmt.Errorf("%w: users already share values of %s", entity.ErrInvalidAttributeDefinition, key)
+			}
+			return fmt.Errorf("failed to create index for attribute %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// DropAttributeIndexes drops the unique indexes of an attribute, if any
+func DropAttributeIndexes(db *gorm.DB, key string) error {
+	for _, scope := range attributeIndexScopes {
+		if err := db.Exec("DROP INDEX IF EXISTS " + attributeIndexPrefix + key + scope).Error; err != nil {
+			return fmt.Errorf("failed to drop index for attribute %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// AttributeIndexKey returns the attribute key of an index enforcing a unique attribute
+func AttributeIndexKey(indexName string) (string, bool) {
+	if !strings.HasPrefix(indexName, attributeIndexPrefix) {
+		return "", false
+	}
+	for _, scope := range attributeIndexScopes {
+		if strings.HasSuffix(indexName, scope) {
+			return strings.TrimSuffix(strings.TrimPrefix(indexName, attributeIndexPrefix), scope), true
+		}
+	}
+	return "", false
+}
+
+// createAttributeIndexes creates the indexes of the unique attributes defined before
+// they were enforced by the database. Attributes whose users already share values
+// are logged and left unenforced, so that startup does not depend on user data.
+func createAttributeIndexes(db *gorm.DB) error {
+	var definitions []entity.AttributeDefinition
+	if err := db.Where("\"unique\" = ?", true).Find(&definitions).Error; err != nil {
+		return fmt.Errorf("failed to list unique attribute definitions: %w", err)
+	}
+
+	for _, definition := range definitions {
+		if len(definition.Key) > entity.MaxUniqueAttributeKeyLength {
+			log.Printf("Warning: the key of attribute %s is too long for its uniqueness to be enforced by the database", definition.Key)
+			continue
+		}
+		if err := CreateAttributeIndexes(db, definition.Key); err != nil {
+			if errors.Is(err, entity.ErrInvalidAttributeDefinition) {
+				log.Printf("Warning: %v; its uniqueness is not enforced", err)
+				if err := DropAttributeIndexes(db, definition.Key); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -5,10 +5,140 @@ import (
 	"log"
 
 	"github.com/gigi434/sample-grpc-server/internal/config"
+	groupentity "github.com/gigi434/sample-grpc-server/internal/modules/group/domain/entity"
+	organizationentity "github.com/gigi434/sample-grpc-server/internal/modules/organization/domain/entity"
+	preferenceentity "github.com/gigi434/sample-grpc-server/internal/modules/preference/domain/entity"
 	"github.com/gigi434/sample-grpc-server/internal/modules/user/domain/entity"
 	"gorm.io/gorm"
 )
 
+// schemaStatements are applied after AutoMigrate for changes it cannot express.
+// Every statement must be idempotent because it runs on every startup.
+var schemaStatements = []string{
+	// Email and username were globally unique before organizations were introduced.
+	// Uniqueness now depends on each organization's setting, so replace the legacy
+	// unique indexes with plain ones and the scoped partial indexes below.
+	`DO $$ BEGIN
+		IF EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_users_email' AND indexdef LIKE 'CREATE UNIQUE INDEX%') THEN
+			DROP INDEX idx_users_email;
+		END IF;
+		IF EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_users_username' AND indexdef LIKE 'CREATE UNIQUE INDEX%') THEN
+			DROP INDEX idx_users_username;
+		END IF;
+	END $$`,
+	`CREATE INDEX IF NOT EXISTS idx_users_email ON users (email)`,
+	`CREATE INDEX IF NOT EXISTS idx_users_username ON users (username)`,
+	// Users created before uniqueness_organization_id existed belong to the scope of
+	// their organization. Backfill it once, before the unique indexes below exist.
+	`DO $$ BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname IN ('idx_users_email_global', 'idx_users_email_lower_global')) THEN
+			UPDATE users SET uniqueness_organization_id = m.organization_id
+			FROM organization_memberships m
+			JOIN organizations o ON o.id = m.organization_id
+			WHERE m.user_id = users.id
+				AND o.user_uniqueness_scope = 'organization'
+				AND users.uniqueness_organization_id IS NULL;
+		END IF;
+	END $$`,
+	// Case-sensitive unique indexes are superseded by the case-insensitive ones below
+	`DROP INDEX IF EXISTS idx_users_email_global`,
+	`DROP INDEX IF EXISTS idx_users_email_organization`,
+	`DROP INDEX IF EXISTS idx_users_username_global`,
+	`DROP INDEX IF EXISTS idx_users_username_organization`,
+	// Emails and usernames are looked up case-insensitively
+	`CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users (lower(email))`,
+	`CREATE INDEX IF NOT EXISTS idx_users_username_lower ON users (lower(username))`,
+	// Prefix filters on email and username compare lower(column) with LIKE, which
+	// needs pattern operator class indexes under non-C collations
+	`CREATE INDEX IF NOT EXISTS idx_users_email_lower_pattern ON users (lower(email) text_pattern_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_users_username_lower_pattern ON users (lower(username) text_pattern_ops)`,
+	// Email and username are unique among live users within their uniqueness scope:
+	// across the deployment when uniqueness_organization_id is NULL, otherwise
+	// within that organization. Soft-deleted users do not hold on to identifiers.
+	// Emails compare case-insensitively; usernames compare by skeleton, which also
+	// rejects look-alikes differing only in case, width, or homoglyphs.
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower_global ON users (lower(email))
+		WHERE deleted_at IS NULL AND uniqueness_organization_id IS NULL`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower_organization ON users (uniqueness_organization_id, lower(email))
+		WHERE deleted_at IS NULL AND uniqueness_organization_id IS NOT NULL`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_skeleton_global ON users (username_skeleton)
+		WHERE deleted_at IS NULL AND uniqueness_organization_id IS NULL`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_skeleton_organization ON users (uniqueness_organization_id, username_skeleton)
+		WHERE deleted_at IS NULL AND uniqueness_organization_id IS NOT NULL`,
+
+	// Sorting by name reading places users without a reading last
+	`CREATE INDEX IF NOT EXISTS idx_users_reading ON users
+		(NULLIF(family_name_kana, '') NULLS LAST, NULLIF(given_name_kana, '') NULLS LAST, id)`,
+	// Keyset pagination of the default newest-first listing compares (created_at, id)
+	`CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users (created_at DESC, id DESC)`,
+
+	// Searches match email, username and names by trigram similarity and prefix,
+	// and kana queries a substring of the name reading, all served by GIN indexes
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING gin (lower(email) gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON users USING gin (lower(username) gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_users_first_name_trgm ON users USING gin (lower(first_name) gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_users_last_name_trgm ON users USING gin (lower(last_name) gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_users_reading_trgm ON users
+		USING gin (replace(family_name_kana || given_name_kana, ' ', '') gin_trgm_ops)`,
+
+	// Every committed change to a user is recorded in user_changes for WatchUsers,
+	// with snapshots of the user before and after it. Sign-ins only move
+	// last_login_at and are not recorded. Restoring a soft-deleted user records a
+	// creation. Watchers are woken through the user_changes channel, which Postgres
+	// notifies when the transaction commits.
+	`CREATE OR REPLACE FUNCTION record_user_change() RETURNS trigger AS $$
+	DECLARE
+		old_data jsonb;
+		new_data jsonb;
+		kind text;
+		subject_id uuid;
+	BEGIN
+		IF TG_OP = 'INSERT' THEN
+			new_data := to_jsonb(NEW) - 'password';
+			kind := 'created';
+			subject_id := NEW.id;
+		ELSIF TG_OP = 'DELETE' THEN
+			old_data := to_jsonb(OLD) - 'password';
+			kind := 'deleted';
+			subject_id := OLD.id;
+		ELSE
+			old_data := to_jsonb(OLD) - 'password';
+			new_data := to_jsonb(NEW) - 'password';
+			IF old_data - 'last_login_at' = new_data - 'last_login_at' THEN
+				RETURN NULL;
+			END IF;
+			IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+				kind := 'deleted';
+			ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+				kind := 'created';
+			ELSE
+				kind := 'updated';
+			END IF;
+			subject_id := NEW.id;
+		END IF;
+
+		INSERT INTO user_changes (txid, user_id, change_type, before_data, after_data, changed_at)
+		VALUES (pg_current_xact_id()::text::bigint, subject_id, kind, old_data, new_data, now());
+		PERFORM pg_notify('user_changes', '');
+		RETURN NULL;
+	END
+	$$ LANGUAGE plpgsql`,
+	// The position up to which user_changes has been pruned, in its single row.
+	// Resume tokens before it have expired.
+	`CREATE TABLE IF NOT EXISTS user_changes_pruned (
+		id boolean PRIMARY KEY DEFAULT true CHECK (id),
+		txid bigint NOT NULL,
+		seq bigint NOT NULL
+	)`,
+	`DO $$ BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'users_record_change') THEN
+			CREATE TRIGGER users_record_change AFTER INSERT OR UPDATE OR DELETE ON users
+				FOR EACH ROW EXECUTE FUNCTION record_user_change();
+		END IF;
+	END $$`,
+}
+
 // Migrate runs database migrations
 func Migrate() error {
 	db, err := config.GetDB()
@@ -19,6 +149,18 @@ func Migrate() error {
 	// List of models to migrate
 	models := []interface{}{
 		&entity.User{},
+		&entity.AttributeDefinition{},
+		&entity.Invitation{},
+		&entity.IdentifierRule{},
+		&entity.LoginEvent{},
+		&entity.AuditEvent{},
+		&entity.UserChange{},
+		&organizationentity.Organization{},
+		&organizationentity.Membership{},
+		&groupentity.Group{},
+		&groupentity.GroupRole{},
+		&groupentity.GroupMember{},
+		&preferenceentity.Preferences{},
 		// Add other models here as they are created
 	}
 
@@ -27,10 +169,50 @@ func Migrate() error {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	// Compute username skeletons for users created before they were stored
+	if err := backfillUsernameSkeletons(db); err != nil {
+		return err
+	}
+
+	// Apply schema changes AutoMigrate cannot express
+	for _, statement := range schemaStatements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to apply schema statement: %w", err)
+		}
+	}
+
+	// Enforce unique custom attributes defined before the database enforced them
+	if err := createAttributeIndexes(db); err != nil {
+		return err
+	}
+
 	log.Println("Database migrations completed successfully")
 	return nil
 }
 
+// backfillUsernameSkeletons fills in the skeleton of users that do not have one yet.
+// Skeletons are computed in Go, so this cannot be expressed as a schema statement.
+func backfillUsernameSkeletons(db *gorm.DB) error {
+	var users []entity.User
+	result := db.Unscoped().
+		Select("id", "username").
+		Where("username_skeleton = ''").
+		FindInBatches(&users, 500, func(tx *gorm.DB, batch int) error {
+			for _, user := range users {
+				if err := db.Unscoped().Model(&entity.User{}).
+					Where("id = ?", user.ID).
+					UpdateColumn("username_skeleton", entity.UsernameSkeleton(user.Username)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to backfill username skeletons: %w", result.Error)
+	}
+	return nil
+}
+
 // DropTables drops all tables (use with caution!)
 func DropTables() error {
 	db, err := config.GetDB()
@@ -41,6 +223,18 @@ func DropTables() error {
 	// List of models to drop
 	models := []interface{}{
 		&entity.User{},
+		&entity.AttributeDefinition{},
+		&entity.Invitation{},
+		&entity.IdentifierRule{},
+		&entity.LoginEvent{},
+		&entity.AuditEvent{},
+		&entity.UserChange{},
+		&organizationentity.Organization{},
+		&organizationentity.Membership{},
+		&groupentity.Group{},
+		&groupentity.GroupRole{},
+		&groupentity.GroupMember{},
+		&preferenceentity.Preferences{},
 		// Add other models here as they are created
 	}
 
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gigi434/sample-grpc-server/internal/config"
+	"gorm.io/gorm"
+)
+
+type transactionKey struct{}
+
+// Transactor runs functions within a database transaction. Repositories get their
+// connection from Conn, so those called with the context passed to the function
+// take part in the transaction.
+type Transactor struct{}
+
+// NewTransactor creates a new instance of Transactor
+func NewTransactor() *Transactor {
+	return &Transactor{}
+}
+
+// WithinTransaction runs fn in a transaction that commits when fn returns nil and
+// rolls back otherwise. Called within a transaction, fn joins it.
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	db, err := config.GetDB()
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, transactionKey{}, tx))
+	})
+}
+
+// Conn returns the connection for a context: the transaction it carries, or the
+// connection pool outside of a transaction
+func Conn(ctx context.Context) (*gorm.DB, error) {
+	if tx, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return tx, nil
+	}
+	return config.GetDB()
+}
@@ -0,0 +1,165 @@
+// Package errors defines application errors that are safe to return to clients.
+// Modules translate their domain errors into these, and the gRPC server turns
+// them into statuses with google.rpc error details.
+package errors
+
+import (
+	stderrors "errors"
+)
+
+// Domain identifies this service in google.rpc.ErrorInfo details
+const Domain = "sample-grpc-server"
+
+// Code classifies an application error; each code maps to one gRPC status code
+type Code string
+
+const (
+	CodeInvalidArgument    Code = "INVALID_ARGUMENT"
+	CodeNotFound           Code = "NOT_FOUND"
+	CodeAlreadyExists      Code = "ALREADY_EXISTS"
+	CodeFailedPrecondition Code = "FAILED_PRECONDITION"
+	CodeAborted            Code = "ABORTED"
+	CodeUnauthenticated    Code = "UNAUTHENTICATED"
+	CodePermissionDenied   Code = "PERMISSION_DENIED"
+	CodeInternal           Code = "INTERNAL"
+)
+
+// Reasons shared by every module
+const (
+	ReasonMissingField = "MISSING_FIELD"
+	ReasonInvalidField = "INVALID_FIELD"
+	ReasonInternal     = "INTERNAL"
+)
+
+// FieldViolation names a request field whose value was rejected
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// Error is an application error. Code, Reason, Message, Metadata, Violations and
+// Translations are returned to clients; the cause is only for logs.
+type Error struct {
+	Code Code
+	// Reason is a stable UPPER_SNAKE_CASE identifier clients can branch on
+	Reason  string
+	Message string
+
+	Metadata   map[string]string
+	Violations []FieldViolation
+
+	// Translations holds the message in other languages, keyed by BCP 47 tag
+	Translations map[string]string
+
+	cause error
+}
+
+// New creates an application error
+func New(code Code, reason, message string) *Error {
+	return &Error{
+		Code:    code,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// MissingField reports a required request field that was not set
+func MissingField(field string) *Error {
+	return New(CodeInvalidArgument, ReasonMissingField, field+" is required").
+		WithViolation(field, "is required")
+}
+
+// InvalidField reports a request field whose value was rejected
+func InvalidField(field, description string) *Error {
+	return New(CodeInvalidArgument, ReasonInvalidField, "invalid "+field+": "+description).
+		WithViolation(field, description)
+}
+
+// Internal wraps an unexpected error; clients only see a generic message
+func Internal(cause error) *Error {
+	return New(CodeInternal, ReasonInternal, "internal error").WithCause(cause)
+}
+
+// Error implements error, including the cause for logs
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the cause
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// WithCause returns a copy of the error with the given cause
+func (e *Error) WithCause(cause error) *Error {
+	c := *e
+	c.cause = cause
+	return &c
+}
+
+// WithViolation returns a copy of the error with a field violation added
+func (e *Error) WithViolation(field, description string) *Error {
+	c := *e
+	c.Violations = append(append([]FieldViolation(nil), e.Violations...), FieldViolation{Field: field, Description: description})
+	return &c
+}
+
+// WithMetadata returns a copy of the error with a metadata entry added
+func (e *Error) WithMetadata(key, value string) *Error {
+	c := *e
+	c.Metadata = make(map[string]string, len(e.Metadata)+1)
+	for k, v := range e.Metadata {
+		c.Metadata[k] = v
+	}
+	c.Metadata[key] = value
+	return &c
+}
+
+// Rule maps a domain error, matched with errors.Is, to an application error.
+// The domain error's text becomes the client message.
+type Rule struct {
+	Err          error
+	Code         Code
+	Reason       string
+	Translations map[string]string
+}
+
+// Translator converts the errors it recognizes to application errors and returns nil otherwise
+type Translator func(err error) *Error
+
+// Rules returns a translator applying the first matching rule
+func Rules(rules ...Rule) Translator {
+	return func(err error) *Error {
+		for _, rule := range rules {
+			if stderrors.Is(err, rule.Err) {
+				return &Error{
+					Code:         rule.Code,
+					Reason:       rule.Reason,
+					Message:      rule.Err.Error(),
+					Translations: rule.Translations,
+					cause:        err,
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// Translate converts any error to an application error. Application errors are
+// returned as is; otherwise the first translator that recognizes the error wins,
+// and unrecognized errors become internal errors.
+func Translate(err error, translators ...Translator) *Error {
+	var appErr *Error
+	if stderrors.As(err, &appErr) {
+		return appErr
+	}
+	for _, translate := range translators {
+		if appErr := translate(err); appErr != nil {
+			return appErr
+		}
+	}
+	return Internal(err)
+}
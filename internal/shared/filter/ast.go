@@ -0,0 +1,95 @@
+// Package filter parses AIP-160 filter expressions, such as
+//
+//	status = ACTIVE AND created_at > "2026-01-01T00:00:00Z" AND (email : "@corp.example" OR is_admin)
+//
+// into an AST and checks them against a schema of filterable fields. Storage
+// layers compile checked expressions into queries.
+package filter
+
+import "fmt"
+
+// Operator is the comparison of a restriction
+type Operator string
+
+const (
+	// OpNone marks a bare field, which must be a boolean and matches when true
+	OpNone         Operator = ""
+	OpEqual        Operator = "="
+	OpNotEqual     Operator = "!="
+	OpLess         Operator = "<"
+	OpLessEqual    Operator = "<="
+	OpGreater      Operator = ">"
+	OpGreaterEqual Operator = ">="
+	// OpHas matches strings containing the value, or any value when it is "*"
+	OpHas Operator = ":"
+)
+
+// Expr is a node of a filter expression
+type Expr interface {
+	// Position returns the 1-based character position where the node starts
+	Position() int
+}
+
+// And matches when all operands match
+type And struct {
+	Operands []Expr
+	Pos      int
+}
+
+// Or matches when any operand matches
+type Or struct {
+	Operands []Expr
+	Pos      int
+}
+
+// Not matches when its operand does not match
+type Not struct {
+	Operand Expr
+	Pos     int
+}
+
+// Restriction compares a field with a value
+type Restriction struct {
+	Field string
+	Op    Operator
+	// Arg is nil for bare fields
+	Arg *Literal
+	Pos int
+}
+
+// Literal is a value in an expression. Check sets Value to the literal converted
+// to the type of the field it is compared with.
+type Literal struct {
+	Text   string
+	Quoted bool
+	Pos    int
+
+	Value interface{}
+	// Prefix and Suffix are set for string equality with a leading or trailing "*"
+	// wildcard; Value then holds the text without the wildcard
+	Prefix bool
+	Suffix bool
+	// Any is set for the presence test `field:*`
+	Any bool
+}
+
+func (e *And) Position() int         { return e.Pos }
+func (e *Or) Position() int          { return e.Pos }
+func (e *Not) Position() int         { return e.Pos }
+func (e *Restriction) Position() int { return e.Pos }
+
+// Error reports an invalid expression and where it is invalid
+type Error struct {
+	Pos int
+	Msg string
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos)
+}
+
+// errorf creates an Error at a position
+func errorf(pos int, format string, args ...interface{}) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
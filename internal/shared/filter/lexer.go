@@ -0,0 +1,114 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+)
+
+// tokenKind classifies a lexical token
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	// tokenText is an unquoted word: a field name, keyword, number or bare value
+	tokenText
+	// tokenString is a double- or single-quoted string
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenComparator
+)
+
+// token is a lexical token with its 1-based position
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// isKeyword reports whether the token is the given upper-case keyword
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenText && t.text == keyword
+}
+
+// specialChars end unquoted words
+const specialChars = `()"'=!<>:`
+
+// lex splits a filter expression into tokens
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: pos})
+			i++
+		case r == '=' || r == ':':
+			tokens = append(tokens, token{kind: tokenComparator, text: string(r), pos: pos})
+			i++
+		case r == '!' || r == '<' || r == '>':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, token{kind: tokenComparator, text: string(r) + "=", pos: pos})
+				i += 2
+				continue
+			}
+			if r == '!' {
+				return nil, errorf(pos, "expected \"!=\"")
+			}
+			tokens = append(tokens, token{kind: tokenComparator, text: string(r), pos: pos})
+			i++
+		case r == '"' || r == '\'':
+			text, end, err := lexString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: pos})
+			i = end
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(specialChars, runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenText, text: string(runes[start:i]), pos: pos})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+// lexString reads the quoted string starting at start and returns its unescaped
+// text and the index after the closing quote
+func lexString(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var text strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case quote:
+			return text.String(), i + 1, nil
+		case '\\':
+			if i+1 == len(runes) {
+				return "", 0, errorf(i+1, "unterminated escape sequence")
+			}
+			i++
+			switch runes[i] {
+			case 'n':
+				text.WriteRune('\n')
+			case 't':
+				text.WriteRune('\t')
+			case '\\', '"', '\'':
+				text.WriteRune(runes[i])
+			default:
+				return "", 0, errorf(i, "unknown escape sequence \\%c", runes[i])
+			}
+		default:
+			text.WriteRune(runes[i])
+		}
+	}
+	return "", 0, errorf(start+1, "unterminated string")
+}
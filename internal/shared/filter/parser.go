@@ -0,0 +1,257 @@
+package filter
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxLength is the longest expression Parse accepts, in characters
+	MaxLength = 2000
+	// maxDepth bounds the nesting of parentheses and negations
+	maxDepth = 32
+)
+
+// Parse parses a filter expression; an empty expression yields a nil Expr.
+//
+// As in AIP-160, OR binds tighter than AND, and terms separated only by
+// whitespace are combined with AND:
+//
+//	a = 1 b = 2 OR c = 3   is   a = 1 AND (b = 2 OR c = 3)
+//
+// Terms are negated with NOT or a leading "-".
+func Parse(input string) (Expr, error) {
+	if length := utf8.RuneCountInString(input); length > MaxLength {
+		return nil, errorf(MaxLength+1, "filter is longer than %d characters", MaxLength)
+	}
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, errorf(t.pos, "unexpected %q", t.text)
+	}
+	return expr, nil
+}
+
+// parser is a recursive descent parser over the tokens of an expression
+type parser struct {
+	tokens []token
+	next   int
+	depth  int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+// expression = sequence { "AND" sequence }
+func (p *parser) expression() (Expr, error) {
+	pos := p.peek().pos
+	operands, err := p.list(p.sequence, func(t token) bool {
+		if t.isKeyword("AND") {
+			p.advance()
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &And{Operands: operands, Pos: pos}, nil
+}
+
+// sequence = factor { factor }
+func (p *parser) sequence() (Expr, error) {
+	pos := p.peek().pos
+	operands, err := p.list(p.factor, startsTerm)
+	if err != nil {
+		return nil, err
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &And{Operands: operands, Pos: pos}, nil
+}
+
+// factor = term { "OR" term }
+func (p *parser) factor() (Expr, error) {
+	pos := p.peek().pos
+	operands, err := p.list(p.term, func(t token) bool {
+		if t.isKeyword("OR") {
+			p.advance()
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &Or{Operands: operands, Pos: pos}, nil
+}
+
+// list parses one or more operands while more reports that another follows
+func (p *parser) list(operand func() (Expr, error), more func(token) bool) ([]Expr, error) {
+	var operands []Expr
+	for {
+		expr, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, expr)
+		if !more(p.peek()) {
+			return operands, nil
+		}
+	}
+}
+
+// term = [ "NOT" | "-" ] simple
+func (p *parser) term() (Expr, error) {
+	t := p.peek()
+	negated := false
+	switch {
+	case t.isKeyword("NOT"):
+		p.advance()
+		negated = true
+	case t.kind == tokenText && strings.HasPrefix(t.text, "-") && len(t.text) > 1:
+		// Strip the minus so the rest parses as a field
+		p.tokens[p.next].text = t.text[1:]
+		p.tokens[p.next].pos++
+		negated = true
+	}
+	if !negated {
+		return p.simple()
+	}
+
+	if err := p.enter(t.pos); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	operand, err := p.simple()
+	if err != nil {
+		return nil, err
+	}
+	return &Not{Operand: operand, Pos: t.pos}, nil
+}
+
+// simple = restriction | "(" expression ")"
+func (p *parser) simple() (Expr, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenLParen:
+		p.advance()
+		if err := p.enter(t.pos); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != tokenRParen {
+			return nil, errorf(closing.pos, "expected \")\" to close \"(\" opened at %d", t.pos)
+		}
+		return expr, nil
+	case t.kind == tokenText && !t.isKeyword("AND") && !t.isKeyword("OR") && !t.isKeyword("NOT"):
+		return p.restriction()
+	case t.kind == tokenString:
+		return nil, errorf(t.pos, "expected a field before %q; free-text search is not supported", t.text)
+	case t.kind == tokenEOF:
+		return nil, errorf(t.pos, "unexpected end of filter")
+	default:
+		return nil, errorf(t.pos, "unexpected %q", t.text)
+	}
+}
+
+// restriction = field [ comparator value ]
+func (p *parser) restriction() (Expr, error) {
+	field := p.advance()
+	if !isFieldName(field.text) {
+		return nil, errorf(field.pos, "invalid field name %q", field.text)
+	}
+	restriction := &Restriction{Field: field.text, Pos: field.pos}
+
+	comparator := p.peek()
+	if comparator.kind != tokenComparator {
+		return restriction, nil
+	}
+	p.advance()
+	restriction.Op = Operator(comparator.text)
+
+	value := p.advance()
+	if value.kind != tokenText && value.kind != tokenString {
+		return nil, errorf(value.pos, "expected a value after %q", comparator.text)
+	}
+	restriction.Arg = &Literal{Text: value.text, Quoted: value.kind == tokenString, Pos: value.pos}
+	return restriction, nil
+}
+
+// enter descends one nesting level, rejecting overly deep expressions
+func (p *parser) enter(pos int) error {
+	p.depth++
+	if p.depth > maxDepth {
+		return errorf(pos, "filter is nested more than %d levels deep", maxDepth)
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+// startsTerm reports whether a token begins a term of an implicit AND sequence
+func startsTerm(t token) bool {
+	switch t.kind {
+	case tokenLParen, tokenString:
+		return true
+	case tokenText:
+		return !t.isKeyword("AND") && !t.isKeyword("OR")
+	default:
+		return false
+	}
+}
+
+// isFieldName reports whether s is a field name: letters, digits and underscores
+// not starting with a digit, optionally separated by dots
+func isFieldName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, part := range strings.Split(s, ".") {
+		if part == "" {
+			return false
+		}
+		for i, r := range part {
+			switch {
+			case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			case r >= '0' && r <= '9' && i > 0:
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// format renders an expression compactly, e.g. AND(a = 1, OR(b, NOT(c : "x")))
+func format(expr Expr) string {
+	switch e := expr.(type) {
+	case nil:
+		return "<nil>"
+	case *And:
+		return "AND(" + formatAll(e.Operands) + ")"
+	case *Or:
+		return "OR(" + formatAll(e.Operands) + ")"
+	case *Not:
+		return "NOT(" + format(e.Operand) + ")"
+	case *Restriction:
+		if e.Arg == nil {
+			return e.Field
+		}
+		arg := e.Arg.Text
+		if e.Arg.Quoted {
+			arg = `"` + arg + `"`
+		}
+		return e.Field + " " + string(e.Op) + " " + arg
+	default:
+		return "?"
+	}
+}
+
+func formatAll(operands []Expr) string {
+	parts := make([]string, len(operands))
+	for i, operand := range operands {
+		parts[i] = format(operand)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "empty", input: "", want: "<nil>"},
+		{name: "blank", input: "  \t ", want: "<nil>"},
+		{name: "bare field", input: "is_admin", want: "is_admin"},
+		{name: "comparison", input: "age >= 21", want: "age >= 21"},
+		{name: "comparison without spaces", input: "age>=21", want: "age >= 21"},
+		{name: "every comparator", input: "a = 1 b != 2 c < 3 d <= 4 e > 5 f >= 6 g : 7",
+			want: "AND(a = 1, b != 2, c < 3, d <= 4, e > 5, f >= 6, g : 7)"},
+		{name: "quoted value", input: `email = "a b@example.com"`, want: `email = "a b@example.com"`},
+		{name: "single quoted value", input: `name = 'O\'Brien'`, want: `name = "O'Brien"`},
+		{name: "escapes", input: `note = "tab\there\nline \\ \""`, want: "note = \"tab\there\nline \\ \"\""},
+		{name: "dotted field", input: "attributes.team = sales", want: "attributes.team = sales"},
+		{name: "explicit and", input: "a = 1 AND b = 2", want: "AND(a = 1, b = 2)"},
+		{name: "implicit and", input: "a = 1 b = 2", want: "AND(a = 1, b = 2)"},
+		{name: "or", input: "a = 1 OR b = 2 OR c = 3", want: "OR(a = 1, b = 2, c = 3)"},
+		{name: "or binds tighter than and", input: "a = 1 AND b = 2 OR c = 3", want: "AND(a = 1, OR(b = 2, c = 3))"},
+		{name: "or binds tighter than implicit and", input: "a = 1 b = 2 OR c = 3", want: "AND(a = 1, OR(b = 2, c = 3))"},
+		{name: "implicit and within explicit and", input: "a b AND c", want: "AND(AND(a, b), c)"},
+		{name: "parentheses", input: "(a = 1 AND b = 2) OR c = 3", want: "OR(AND(a = 1, b = 2), c = 3)"},
+		{name: "redundant parentheses", input: "((a))", want: "a"},
+		{name: "not", input: "NOT a = 1", want: "NOT(a = 1)"},
+		{name: "minus", input: "-a = 1", want: "NOT(a = 1)"},
+		{name: "not of group", input: "NOT (a OR b)", want: "NOT(OR(a, b))"},
+		{name: "negative value", input: "balance > -5", want: "balance > -5"},
+		{name: "presence", input: "last_login_at:*", want: "last_login_at : *"},
+		{name: "wildcard value", input: `email = "*@example.com"`, want: `email = "*@example.com"`},
+		{name: "lower case keywords are fields", input: "and or", want: "AND(and, or)"},
+		{name: "unicode value", input: "last_name = 山田", want: "last_name = 山田"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if got := format(expr); got != tt.want {
+				t.Fatalf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantPos int
+		wantMsg string
+	}{
+		{name: "missing value", input: "a =", wantPos: 4, wantMsg: `expected a value after "="`},
+		{name: "missing field", input: "= 1", wantPos: 1, wantMsg: `unexpected "="`},
+		{name: "dangling and", input: "a = 1 AND", wantPos: 10, wantMsg: "unexpected end of filter"},
+		{name: "dangling or", input: "a OR", wantPos: 5, wantMsg: "unexpected end of filter"},
+		{name: "unclosed parenthesis", input: "(a = 1", wantPos: 7, wantMsg: `expected ")" to close "(" opened at 1`},
+		{name: "unopened parenthesis", input: "a = 1)", wantPos: 6, wantMsg: `unexpected ")"`},
+		{name: "free text", input: `"hello"`, wantPos: 1, wantMsg: "free-text search is not supported"},
+		{name: "invalid field", input: "1a = 1", wantPos: 1, wantMsg: `invalid field name "1a"`},
+		{name: "empty field segment", input: "a..b = 1", wantPos: 1, wantMsg: `invalid field name "a..b"`},
+		{name: "bang without equals", input: "a ! 1", wantPos: 3, wantMsg: `expected "!="`},
+		{name: "unterminated string", input: `a = "abc`, wantPos: 5, wantMsg: "unterminated string"},
+		{name: "unknown escape", input: `a = "\q"`, wantPos: 6, wantMsg: `unknown escape sequence \q`},
+		{name: "too deep", input: strings.Repeat("(", maxDepth+1) + "a" + strings.Repeat(")", maxDepth+1),
+			wantPos: maxDepth + 1, wantMsg: "nested more than"},
+		{name: "too long", input: strings.Repeat("a", MaxLength+1), wantPos: MaxLength + 1, wantMsg: "longer than"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.input)
+			if err == nil {
+				t.Fatalf("Parse(%q) = %s, want error", tt.input, format(expr))
+			}
+			var filterErr *Error
+			if !errors.As(err, &filterErr) {
+				t.Fatalf("Parse(%q) error = %v, want *Error", tt.input, err)
+			}
+			if filterErr.Pos != tt.wantPos {
+				t.Errorf("error position = %d, want %d (%v)", filterErr.Pos, tt.wantPos, err)
+			}
+			if !strings.Contains(filterErr.Msg, tt.wantMsg) {
+				t.Errorf("error message = %q, want it to contain %q", filterErr.Msg, tt.wantMsg)
+			}
+		})
+	}
+}
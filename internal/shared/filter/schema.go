@@ -0,0 +1,172 @@
+package filter
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FieldType is the type of a filterable field
+type FieldType int
+
+const (
+	TypeString FieldType = iota
+	TypeBool
+	TypeInt
+	TypeTimestamp
+	TypeUUID
+	// TypeEnum fields compare with one of the field's Values, written unquoted
+	TypeEnum
+)
+
+// Field describes a filterable field
+type Field struct {
+	Type FieldType
+	// Nullable fields can be tested for presence with `field:*`
+	Nullable bool
+	// Values lists the values of an enum field
+	Values []string
+}
+
+// Schema maps field names to the fields expressions may reference
+type Schema map[string]Field
+
+// operators lists the comparisons each field type supports
+var operators = map[FieldType][]Operator{
+	TypeString:    {OpEqual, OpNotEqual, OpLess, OpLessEqual, OpGreater, OpGreaterEqual, OpHas},
+	TypeBool:      {OpNone, OpEqual, OpNotEqual},
+	TypeInt:       {OpEqual, OpNotEqual, OpLess, OpLessEqual, OpGreater, OpGreaterEqual},
+	TypeTimestamp: {OpEqual, OpNotEqual, OpLess, OpLessEqual, OpGreater, OpGreaterEqual},
+	TypeUUID:      {OpEqual, OpNotEqual},
+	TypeEnum:      {OpEqual, OpNotEqual},
+}
+
+// Check validates the expression against the schema and converts each literal
+// to the type of the field it is compared with
+func (s Schema) Check(expr Expr) error {
+	switch e := expr.(type) {
+	case nil:
+		return nil
+	case *And:
+		return s.checkAll(e.Operands)
+	case *Or:
+		return s.checkAll(e.Operands)
+	case *Not:
+		return s.Check(e.Operand)
+	case *Restriction:
+		return s.checkRestriction(e)
+	default:
+		return errorf(expr.Position(), "unsupported expression")
+	}
+}
+
+func (s Schema) checkAll(operands []Expr) error {
+	for _, operand := range operands {
+		if err := s.Check(operand); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s Schema) checkRestriction(r *Restriction) error {
+	field, ok := s[r.Field]
+	if !ok {
+		return errorf(r.Pos, "unknown field %q", r.Field)
+	}
+
+	// Presence test
+	if r.Op == OpHas && !r.Arg.Quoted && r.Arg.Text == "*" {
+		if !field.Nullable {
+			return errorf(r.Pos, "field %q is always present", r.Field)
+		}
+		r.Arg.Any = true
+		return nil
+	}
+
+	if !supports(field.Type, r.Op) {
+		if r.Op == OpNone {
+			return errorf(r.Pos, "field %q must be compared with a value", r.Field)
+		}
+		return errorf(r.Pos, "field %q does not support %q", r.Field, r.Op)
+	}
+	if r.Op == OpNone {
+		return nil
+	}
+
+	value, err := convert(field, r.Arg)
+	if err != nil {
+		return err
+	}
+	r.Arg.Value = value
+
+	// Leading and trailing wildcards in string equality match suffixes and prefixes
+	if field.Type == TypeString && (r.Op == OpEqual || r.Op == OpNotEqual) {
+		text := r.Arg.Text
+		if strings.HasPrefix(text, "*") && len(text) > 1 {
+			r.Arg.Suffix = true
+			text = text[1:]
+		}
+		if strings.HasSuffix(text, "*") && len(text) > 1 {
+			r.Arg.Prefix = true
+			text = text[:len(text)-1]
+		}
+		if strings.Contains(text, "*") {
+			return errorf(r.Arg.Pos, "wildcards are only allowed at the start or end of a value")
+		}
+		r.Arg.Value = text
+	}
+	return nil
+}
+
+// supports reports whether a field type supports the operator
+func supports(fieldType FieldType, op Operator) bool {
+	for _, supported := range operators[fieldType] {
+		if op == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// convert converts a literal to the field's type
+func convert(field Field, arg *Literal) (interface{}, error) {
+	switch field.Type {
+	case TypeString:
+		return arg.Text, nil
+	case TypeBool:
+		if arg.Quoted || (arg.Text != "true" && arg.Text != "false") {
+			return nil, errorf(arg.Pos, "expected true or false, got %q", arg.Text)
+		}
+		return arg.Text == "true", nil
+	case TypeInt:
+		value, err := strconv.ParseInt(arg.Text, 10, 64)
+		if arg.Quoted || err != nil {
+			return nil, errorf(arg.Pos, "expected an integer, got %q", arg.Text)
+		}
+		return value, nil
+	case TypeTimestamp:
+		value, err := time.Parse(time.RFC3339Nano, arg.Text)
+		if err != nil {
+			return nil, errorf(arg.Pos, "expected an RFC 3339 timestamp such as \"2026-01-01T00:00:00Z\", got %q", arg.Text)
+		}
+		return value, nil
+	case TypeUUID:
+		value, err := uuid.Parse(arg.Text)
+		if err != nil {
+			return nil, errorf(arg.Pos, "expected a UUID, got %q", arg.Text)
+		}
+		return value, nil
+	case TypeEnum:
+		for _, value := range field.Values {
+			if !arg.Quoted && arg.Text == value {
+				return value, nil
+			}
+		}
+		return nil, errorf(arg.Pos, "expected one of %s, got %q", strings.Join(field.Values, ", "), arg.Text)
+	default:
+		return nil, errorf(arg.Pos, "unsupported field type")
+	}
+}
@@ -0,0 +1,148 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var testSchema = Schema{
+	"email":         {Type: TypeString},
+	"is_admin":      {Type: TypeBool},
+	"version":       {Type: TypeInt},
+	"created_at":    {Type: TypeTimestamp},
+	"last_login_at": {Type: TypeTimestamp, Nullable: true},
+	"id":            {Type: TypeUUID},
+	"status":        {Type: TypeEnum, Values: []string{"ACTIVE", "INACTIVE"}},
+}
+
+func TestSchemaCheck(t *testing.T) {
+	id := uuid.MustParse("0b6f7c1e-7d3a-4c55-9a57-2f1c0f7c9b10")
+
+	tests := []struct {
+		name       string
+		input      string
+		wantValue  interface{}
+		wantPrefix bool
+		wantSuffix bool
+		wantAny    bool
+	}{
+		{name: "string", input: `email = "a@example.com"`, wantValue: "a@example.com"},
+		{name: "string has", input: "email : example", wantValue: "example"},
+		{name: "string prefix", input: `email = "admin*"`, wantValue: "admin", wantPrefix: true},
+		{name: "string suffix", input: `email = "*@example.com"`, wantValue: "@example.com", wantSuffix: true},
+		{name: "string infix", input: `email != "*example*"`, wantValue: "example", wantPrefix: true, wantSuffix: true},
+		{name: "bool", input: "is_admin = false", wantValue: false},
+		{name: "bare bool", input: "is_admin", wantValue: nil},
+		{name: "int", input: "version > 3", wantValue: int64(3)},
+		{name: "negative int", input: "version > -3", wantValue: int64(-3)},
+		{name: "timestamp", input: `created_at >= "2026-01-02T03:04:05Z"`, wantValue: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{name: "presence", input: "last_login_at:*", wantAny: true},
+		{name: "uuid", input: "id = " + id.String(), wantValue: id},
+		{name: "enum", input: "status = ACTIVE", wantValue: "ACTIVE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if err := testSchema.Check(expr); err != nil {
+				t.Fatalf("Check(%q) error = %v", tt.input, err)
+			}
+
+			r := expr.(*Restriction)
+			if r.Arg == nil {
+				if tt.wantValue != nil || tt.wantAny {
+					t.Fatalf("Check(%q) left no argument", tt.input)
+				}
+				return
+			}
+			if r.Arg.Any != tt.wantAny {
+				t.Errorf("Any = %v, want %v", r.Arg.Any, tt.wantAny)
+			}
+			if r.Arg.Prefix != tt.wantPrefix || r.Arg.Suffix != tt.wantSuffix {
+				t.Errorf("Prefix, Suffix = %v, %v, want %v, %v", r.Arg.Prefix, r.Arg.Suffix, tt.wantPrefix, tt.wantSuffix)
+			}
+			if got, ok := r.Arg.Value.(time.Time); ok {
+				if !got.Equal(tt.wantValue.(time.Time)) {
+					t.Errorf("Value = %v, want %v", got, tt.wantValue)
+				}
+				return
+			}
+			if r.Arg.Value != tt.wantValue {
+				t.Errorf("Value = %#v, want %#v", r.Arg.Value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestSchemaCheckConvertsNestedLiterals(t *testing.T) {
+	expr, err := Parse("NOT (version = 1 OR is_admin = true) email : x")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := testSchema.Check(expr); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	or := expr.(*And).Operands[0].(*Not).Operand.(*Or)
+	if got := or.Operands[0].(*Restriction).Arg.Value; got != int64(1) {
+		t.Errorf("version value = %#v, want int64(1)", got)
+	}
+	if got := or.Operands[1].(*Restriction).Arg.Value; got != true {
+		t.Errorf("is_admin value = %#v, want true", got)
+	}
+}
+
+func TestSchemaCheckErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantPos int
+		wantMsg string
+	}{
+		{name: "unknown field", input: "a = 1 AND nickname = x", wantPos: 11, wantMsg: `unknown field "nickname"`},
+		{name: "bare non-bool", input: "email", wantPos: 1, wantMsg: "must be compared with a value"},
+		{name: "unsupported operator", input: "is_admin > true", wantPos: 1, wantMsg: `does not support ">"`},
+		{name: "has on int", input: "version : 1", wantPos: 1, wantMsg: `does not support ":"`},
+		{name: "presence of required field", input: "created_at:*", wantPos: 1, wantMsg: "always present"},
+		{name: "quoted bool", input: `is_admin = "true"`, wantPos: 12, wantMsg: "expected true or false"},
+		{name: "invalid int", input: "version = 1.5", wantPos: 11, wantMsg: "expected an integer"},
+		{name: "quoted int", input: `version = "1"`, wantPos: 11, wantMsg: "expected an integer"},
+		{name: "invalid timestamp", input: `created_at > "2026-01-01"`, wantPos: 14, wantMsg: "expected an RFC 3339 timestamp"},
+		{name: "invalid uuid", input: "id = 123", wantPos: 6, wantMsg: "expected a UUID"},
+		{name: "unknown enum value", input: "status = SUSPENDED", wantPos: 10, wantMsg: "expected one of ACTIVE, INACTIVE"},
+		{name: "quoted enum value", input: `status = "ACTIVE"`, wantPos: 10, wantMsg: "expected one of"},
+		{name: "inner wildcard", input: `email = "a*b"`, wantPos: 9, wantMsg: "wildcards are only allowed"},
+		{name: "lone wildcard", input: `email = "*"`, wantPos: 9, wantMsg: "wildcards are only allowed"},
+	}
+
+	schema := Schema{"a": {Type: TypeInt}}
+	for name, field := range testSchema {
+		schema[name] = field
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			err = schema.Check(expr)
+			var filterErr *Error
+			if !errors.As(err, &filterErr) {
+				t.Fatalf("Check(%q) error = %v, want *Error", tt.input, err)
+			}
+			if filterErr.Pos != tt.wantPos {
+				t.Errorf("error position = %d, want %d (%v)", filterErr.Pos, tt.wantPos, err)
+			}
+			if !strings.Contains(filterErr.Msg, tt.wantMsg) {
+				t.Errorf("error message = %q, want it to contain %q", filterErr.Msg, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MembershipTable is the table linking users to the organizations they belong to
+const MembershipTable = "organization_memberships"
+
+// UniquenessScope controls where email and username must be unique
+type UniquenessScope string
+
+const (
+	// UniquenessGlobal requires identifiers to be unique across the whole deployment
+	UniquenessGlobal UniquenessScope = "global"
+	// UniquenessOrganization requires identifiers to be unique within an organization only
+	UniquenessOrganization UniquenessScope = "organization"
+)
+
+// IsValid reports whether the uniqueness scope is supported
+func (s UniquenessScope) IsValid() bool {
+	return s == UniquenessGlobal || s == UniquenessOrganization
+}
+
+type organizationKey struct{}
+
+type unscopedKey struct{}
+
+// WithOrganizationID returns a context carrying the current tenant
+func WithOrganizationID(ctx context.Context, organizationID uuid.UUID) context.Context {
+	return context.WithValue(ctx, organizationKey{}, organizationID)
+}
+
+// OrganizationIDFromContext returns the current tenant, if any
+func OrganizationIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	organizationID, ok := ctx.Value(organizationKey{}).(uuid.UUID)
+	if !ok || organizationID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return organizationID, true
+}
+
+// Unscoped returns a context in which repositories skip tenant scoping.
+// It is intended for cross-tenant checks such as global uniqueness.
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+// IsUnscoped reports whether tenant scoping has been disabled for the context
+func IsUnscoped(ctx context.Context) bool {
+	unscoped, _ := ctx.Value(unscopedKey{}).(bool)
+	return unscoped
+}
+
+// ScopeUsers returns a GORM scope restricting the user ID column to members of
+// the current tenant. It is a no-op without a tenant or in an unscoped context.
+func ScopeUsers(ctx context.Context, userIDColumn string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if IsUnscoped(ctx) {
+			return db
+		}
+		organizationID, ok := OrganizationIDFromContext(ctx)
+		if !ok {
+			return db
+		}
+		return db.Where(
+			userIDColumn+" IN (SELECT user_id FROM "+MembershipTable+" WHERE organization_id = ?)",
+			organizationID,
+		)
+	}
+}
+
+// ScopeOrganization returns a GORM scope restricting an organization ID column to
+// the current tenant. It is a no-op without a tenant or in an unscoped context.
+func ScopeOrganization(ctx context.Context, organizationIDColumn string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if IsUnscoped(ctx) {
+			return db
+		}
+		organizationID, ok := OrganizationIDFromContext(ctx)
+		if !ok {
+			return db
+		}
+		return db.Where(organizationIDColumn+" = ?", organizationID)
+	}
+}
@@ -69,6 +69,36 @@ protoc \
     --go-grpc_opt=paths=source_relative \
     "${PROTO_DIR}"/v1/health/*.proto
 
+# Generate Go code for v1 organization service
+echo -e "${GREEN}Generating organization service proto files...${NC}"
+protoc \
+    --proto_path="${PROTO_DIR}" \
+    --go_out="${OUTPUT_DIR}" \
+    --go_opt=paths=source_relative \
+    --go-grpc_out="${OUTPUT_DIR}" \
+    --go-grpc_opt=paths=source_relative \
+    "${PROTO_DIR}"/v1/organization/*.proto
+
+# Generate Go code for v1 group service
+echo -e "${GREEN}Generating group service proto files...${NC}"
+protoc \
+    --proto_path="${PROTO_DIR}" \
+    --go_out="${OUTPUT_DIR}" \
+    --go_opt=paths=source_relative \
+    --go-grpc_out="${OUTPUT_DIR}" \
+    --go-grpc_opt=paths=source_relative \
+    "${PROTO_DIR}"/v1/group/*.proto
+
+# Generate Go code for v1 preference service
+echo -e "${GREEN}Generating preference service proto files...${NC}"
+protoc \
+    --proto_path="${PROTO_DIR}" \
+    --go_out="${OUTPUT_DIR}" \
+    --go_opt=paths=source_relative \
+    --go-grpc_out="${OUTPUT_DIR}" \
+    --go-grpc_opt=paths=source_relative \
+    "${PROTO_DIR}"/v1/preference/*.proto
+
 echo -e "${GREEN}Protocol Buffer code generation completed!${NC}"
 echo -e "${GREEN}Generated files are in: ${OUTPUT_DIR}${NC}"
 
@@ -14,7 +14,7 @@ CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 -- 暗号化用の拡張機能
 CREATE EXTENSION IF NOT EXISTS "pgcrypto";
 
--- テキスト検索用の拡張機能（将来の検索機能のため）
+-- テキスト検索用の拡張機能（ユーザー検索のトライグラム索引で使用）
 CREATE EXTENSION IF NOT EXISTS "pg_trgm";
 
 -- デフォルトのスキーマに権限を付与